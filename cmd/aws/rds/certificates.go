@@ -0,0 +1,94 @@
+package rds
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+func runCertificates(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	expiryDays, _ := cmd.Flags().GetInt("expiry-days")
+	allRegions, _ := cmd.Flags().GetBool("all-regions")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Sans --all-regions, seule la région du profil est interrogée
+	regions := []string{""}
+	if allRegions {
+		var err error
+		regions, err = rds.ListRegions(profile, "")
+		if err != nil {
+			return fmt.Errorf("RDS: ListRegions: %w", err)
+		}
+	}
+
+	// Avec --db-instance-identifier, les régions où l'instance n'existe pas sont ignorées
+	report, err := rds.CertificateReports(dbInstanceIdentifier, profile, regions, expiryDays, concurrency)
+	if err != nil {
+		if len(report) == 0 {
+			return fmt.Errorf("RDS: CertificateReports: %w", err)
+		}
+		// Les régions accessibles sont affichées malgré les erreurs
+		slog.Warn("Rapport incomplet", slog.Any("error", err))
+	}
+	if report == nil {
+		report = []rds.CertificateStatus{}
+	}
+
+	if format == "json" {
+		return output.PrintJSON(report)
+	}
+
+	columns := []string{"Region", "DBInstanceIdentifier", "CA", "CAValidTill", "ServerCertValidTill", "DaysLeft", "NewerCA", "PendingCA", "Status"}
+	rows := make([][]string, 0, len(report))
+	for _, status := range report {
+		rows = append(rows, []string{
+			status.Region,
+			status.DBInstanceIdentifier,
+			status.CACertificateIdentifier,
+			formatDate(status.CAValidTill),
+			formatDate(status.ServerCertificateValidTill),
+			fmt.Sprintf("%d", status.DaysLeft),
+			status.NewerCA,
+			status.PendingCA,
+			status.Status,
+		})
+	}
+	return output.PrintRows(format, columns, rows)
+}
+
+// formatDate affiche une date au format AAAA-MM-JJ, ou une chaîne vide si elle est inconnue
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+func CertificatesCmd() *cobra.Command {
+	certificatesCmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "Report the CA certificate of each instance and its rotation",
+		Long: `Report, for each instance, its certificate authority (CA), the expiry date of the CA and of the server certificate,
+whether a newer CA is available and whether a CA change is pending (applied at the next maintenance window).
+Without --db-instance-identifier, every instance of the region is reported; --all-regions covers every enabled region,
+scanned in parallel (--concurrency at a time). With --db-instance-identifier and --all-regions, the regions where the
+instance does not exist are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: runCertificates,
+	}
+	certificatesCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	certificatesCmd.Flags().Int("expiry-days", 90, "Signaler les certificats qui expirent dans ce nombre de jours")
+	certificatesCmd.Flags().Bool("all-regions", false, "Interroger toutes les régions activées du compte")
+	certificatesCmd.Flags().Int("concurrency", 8, "Nombre maximal de régions interrogées en parallèle")
+	return certificatesCmd
+}
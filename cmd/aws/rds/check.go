@@ -0,0 +1,72 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+)
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetStringSlice("check-profile")
+	format, _ := cmd.Flags().GetString("format")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	var results []check.Result
+	for _, checkProfile := range profiles {
+		profileResults, err := rdsInstance.Check(checkProfile)
+		if err != nil {
+			return fmt.Errorf("RDS: Check: %w", err)
+		}
+		results = append(results, profileResults...)
+	}
+
+	// Règles d'audit évaluées sur le modèle commun aux fournisseurs
+	if len(rulesFiles) > 0 {
+		ruleResults, err := check.RunRules(rulesFiles, rdsInstance.Facts())
+		if err != nil {
+			return fmt.Errorf("RDS: RunRules: %w", err)
+		}
+		results = append(results, ruleResults...)
+	}
+
+	err = check.PrintResults(format, results)
+	if err != nil {
+		return err
+	}
+
+	if check.Failed(results) {
+		return check.ErrFailed
+	}
+	return nil
+}
+
+func CheckCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the parameter group against embedded rule profiles",
+		Long: `Check the parameter group against embedded rule profiles (pgbadger, quellog, security, performance).
+The command exits with a non-zero status when a rule of severity error is not satisfied.
+The profiles are selected with --check-profile, because --profile is the AWS profile under rds.
+The top-level "cloud_helper check --profile" checks several providers at once.`,
+		Args: cobra.NoArgs,
+		RunE: SelectorRunE(runCheck),
+	}
+	checkCmd.Flags().StringSlice("check-profile", []string{"pgbadger"}, "Profils de vérification (pgbadger, quellog, security, performance), équivalent de --profile de cloud_helper check")
+	checkCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown, junit)")
+	checkCmd.Flags().StringSlice("rules", nil, "Fichiers de règles d'audit YAML ou JSON à évaluer en plus des profils")
+	return checkCmd
+}
@@ -0,0 +1,225 @@
+package rds
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+func runClone(cmd *cobra.Command, args []string) error {
+	nameTemplate, _ := cmd.Flags().GetString("name-template")
+	overridesFlag, _ := cmd.Flags().GetString("overrides")
+	attach, _ := cmd.Flags().GetBool("attach")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	// Surcharges : profil prédéfini puis affectations name=value
+	var overrides []pgsettings.Assignment
+	if overridesFlag != "" {
+		profile, ok := rds.OverrideProfiles[overridesFlag]
+		if !ok {
+			return fmt.Errorf("profil de surcharges %s inconnu (%s)", overridesFlag, strings.Join(overrideProfileNames(), ", "))
+		}
+		overrides = append(overrides, profile...)
+	}
+	assignments, err := pgsettings.ParseAssignments(args)
+	if err != nil {
+		return err
+	}
+	overrides = append(overrides, assignments...)
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err = rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	return cloneParameterGroup(&rdsInstance, nameTemplate, overrides, attach, dryRun, force, false)
+}
+
+// cloneParameterGroup copie le groupe de paramètres de l'instance et, sur Aurora, celui du cluster
+// lorsque des surcharges de niveau cluster sont demandées, applique les surcharges, attache
+// éventuellement les nouveaux groupes et indique le redémarrage nécessaire.
+// Avec defaultOnly, seuls les groupes par défaut (non modifiables) sont clonés
+func cloneParameterGroup(rdsInstance *rds.RDS, nameTemplate string, overrides []pgsettings.Assignment, attach bool, dryRun bool, force bool, defaultOnly bool) error {
+	err := rdsInstance.ValidateAssignments(overrides)
+	if err != nil {
+		return fmt.Errorf("RDS: ValidateAssignments: %w", err)
+	}
+
+	cloneInstance, cloneCluster, err := rdsInstance.ParameterGroupLevels(overrides)
+	if err != nil {
+		return fmt.Errorf("RDS: ParameterGroupLevels: %w", err)
+	}
+	if len(overrides) == 0 {
+		cloneInstance = true
+	}
+
+	sourceName := rdsInstance.GetDBParameterGroupName()
+	var clusterSourceName, clusterIdentifier string
+	if cluster := rdsInstance.GetDbCluster(); cluster != nil {
+		clusterSourceName = cluster.DBClusterParameterGroup
+		clusterIdentifier = cluster.DBClusterIdentifier
+	}
+	if defaultOnly {
+		cloneInstance = cloneInstance && rds.IsDefaultParameterGroup(sourceName)
+		cloneCluster = cloneCluster && rds.IsDefaultParameterGroup(clusterSourceName)
+	}
+
+	var targetName, clusterTargetName string
+	if cloneInstance {
+		targetName, err = rdsInstance.CloneParameterGroupName(nameTemplate)
+		if err != nil {
+			return fmt.Errorf("RDS: CloneParameterGroupName: %w", err)
+		}
+		fmt.Printf("Copie de %s vers %s\n", sourceName, targetName)
+	}
+	if cloneCluster {
+		clusterTargetName, err = rdsInstance.CloneClusterParameterGroupName(nameTemplate)
+		if err != nil {
+			return fmt.Errorf("RDS: CloneClusterParameterGroupName: %w", err)
+		}
+		fmt.Printf("Copie de %s vers %s\n", clusterSourceName, clusterTargetName)
+	}
+	for _, override := range overrides {
+		fmt.Printf("  %s = %s\n", override.Name, override.Value)
+	}
+	if attach && cloneInstance {
+		fmt.Printf("Attachement de %s à %s (redémarrage nécessaire)\n", targetName, rdsInstance.GetdbInstance().DBInstanceIdentifier)
+	}
+	if attach && cloneCluster {
+		fmt.Printf("Attachement de %s au cluster %s (redémarrage nécessaire)\n", clusterTargetName, clusterIdentifier)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		fmt.Print("Continuer ? (y/n): ")
+		var response string
+		_, err = fmt.Scanln(&response)
+		if err != nil {
+			return fmt.Errorf("scanln: %w", err)
+		}
+
+		if strings.ToLower(response) != "y" {
+			return nil
+		}
+	}
+
+	if cloneInstance {
+		err = rdsInstance.CopyParameterGroup(targetName)
+		if err != nil {
+			return fmt.Errorf("RDS: CopyParameterGroup: %w", err)
+		}
+		slog.Info("Groupe de paramètres créé", slog.String("source", sourceName), slog.String("target", targetName))
+
+		err = rdsInstance.UseParameterGroup(targetName)
+		if err != nil {
+			return fmt.Errorf("RDS: UseParameterGroup: %w", err)
+		}
+	}
+
+	if cloneCluster {
+		err = rdsInstance.CopyClusterParameterGroup(clusterTargetName)
+		if err != nil {
+			return fmt.Errorf("RDS: CopyClusterParameterGroup: %w", err)
+		}
+		slog.Info("Groupe de paramètres de cluster créé", slog.String("source", clusterSourceName), slog.String("target", clusterTargetName))
+
+		err = rdsInstance.UseClusterParameterGroup(clusterTargetName)
+		if err != nil {
+			return fmt.Errorf("RDS: UseClusterParameterGroup: %w", err)
+		}
+	}
+
+	changes, err := rdsInstance.PlanParameterChanges(overrides)
+	if err != nil {
+		return fmt.Errorf("RDS: PlanParameterChanges: %w", err)
+	}
+
+	if len(changes) > 0 {
+		err = printParameterChanges(changes)
+		if err != nil {
+			return err
+		}
+
+		err = rdsInstance.ApplyParameterChanges(changes)
+		if err != nil {
+			return fmt.Errorf("RDS: ApplyParameterChanges: %w", err)
+		}
+	}
+
+	if !attach {
+		if cloneInstance {
+			slog.Info("Groupe de paramètres prêt, non attaché", slog.String("name", targetName))
+		}
+		if cloneCluster {
+			slog.Info("Groupe de paramètres de cluster prêt, non attaché", slog.String("name", clusterTargetName))
+		}
+		return nil
+	}
+
+	// Un changement de groupe de paramètres n'est appliqué qu'au redémarrage
+	instances := []string{rdsInstance.GetdbInstance().DBInstanceIdentifier}
+	if cloneInstance {
+		err = rdsInstance.AttachParameterGroup(targetName)
+		if err != nil {
+			return fmt.Errorf("RDS: AttachParameterGroup: %w", err)
+		}
+		slog.Warn("Groupe de paramètres attaché, redémarrage nécessaire", slog.String("instance", instances[0]), slog.String("name", targetName))
+	}
+	if cloneCluster {
+		err = rdsInstance.AttachClusterParameterGroup(clusterTargetName)
+		if err != nil {
+			return fmt.Errorf("RDS: AttachClusterParameterGroup: %w", err)
+		}
+		slog.Warn("Groupe de paramètres de cluster attaché, redémarrage nécessaire", slog.String("cluster", clusterIdentifier), slog.String("name", clusterTargetName))
+
+		instances = nil
+		for _, member := range rdsInstance.GetDbCluster().DBClusterMembers {
+			instances = append(instances, member.DBInstanceIdentifier)
+		}
+	}
+	for _, instance := range instances {
+		fmt.Printf("aws rds reboot-db-instance --db-instance-identifier %s\n", instance)
+	}
+
+	return nil
+}
+
+func overrideProfileNames() []string {
+	var names []string
+	for name := range rds.OverrideProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func CloneCmd() *cobra.Command {
+	cloneCmd := &cobra.Command{
+		Use:   "clone-parameter-group [name=value...]",
+		Short: "Copy the attached parameter group (and the Aurora cluster one for cluster-level overrides) into a custom one, apply overrides and attach it",
+		Args:  cobra.MinimumNArgs(0),
+		RunE:  runClone,
+	}
+	cloneCmd.Flags().String("name-template", rds.DefaultCloneNameTemplate, "Modèle du nom du nouveau groupe ({{.Identifier}}, {{.Family}}, {{.Source}})")
+	cloneCmd.Flags().String("overrides", "", "Profil de surcharges à appliquer (pgbadger-ready)")
+	cloneCmd.Flags().Bool("attach", true, "Attacher le nouveau groupe à l'instance")
+	cloneCmd.Flags().Bool("dry-run", false, "Afficher les opérations sans les effectuer")
+	cloneCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation")
+	return cloneCmd
+}
@@ -0,0 +1,117 @@
+package rds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	against, _ := cmd.Flags().GetString("against")
+	format, _ := cmd.Flags().GetString("format")
+
+	if against == "" {
+		return fmt.Errorf("le flag --against est obligatoire")
+	}
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	againstParameters, err := loadAgainstParameters(&rdsInstance, against, profile)
+	if err != nil {
+		return err
+	}
+
+	parameters := rdsInstance.GetDBParameters()
+	diffs := parameters.Diff(againstParameters)
+
+	if format == "json" {
+		return output.PrintJSON(diffs)
+	}
+
+	columns := []string{"ParameterName", "Change", "Value", "Against", "Source", "AgainstSource", "ApplyType", "Level"}
+	rows := make([][]string, 0, len(diffs))
+	for _, diff := range diffs {
+		rows = append(rows, []string{diff.ParameterName, diff.Change, diff.Value, diff.AgainstValue, diff.Source, diff.AgainstSource, diff.ApplyType, diff.Level})
+	}
+	return output.PrintRows(format, columns, rows)
+}
+
+// loadAgainstParameters charge les paramètres de référence : valeurs par défaut de la famille,
+// fichier JSON (format aws rds describe-db-parameters) ou autre instance
+func loadAgainstParameters(rdsInstance *rds.RDS, against string, profile string) (rds.DescribeDBParametersResult, error) {
+	var parameters rds.DescribeDBParametersResult
+
+	if against == "engine-default" {
+		family, err := rdsInstance.GetDBParameterGroupFamily()
+		if err != nil {
+			return parameters, fmt.Errorf("RDS: GetDBParameterGroupFamily: %w", err)
+		}
+
+		parameters, err = rdsInstance.DescribeEngineDefaultParameters(family)
+		if err != nil {
+			return parameters, fmt.Errorf("RDS: DescribeEngineDefaultParameters: %w", err)
+		}
+
+		// Sur Aurora, les paramètres du cluster sont comparés aux valeurs par défaut des groupes de cluster
+		if rdsInstance.GetDbCluster() != nil {
+			clusterFamily, err := rdsInstance.GetDBClusterParameterGroupFamily()
+			if err != nil {
+				return parameters, fmt.Errorf("RDS: GetDBClusterParameterGroupFamily: %w", err)
+			}
+
+			clusterDefaults, err := rdsInstance.DescribeEngineDefaultClusterParameters(clusterFamily)
+			if err != nil {
+				return parameters, fmt.Errorf("RDS: DescribeEngineDefaultClusterParameters: %w", err)
+			}
+			parameters = parameters.Merge(clusterDefaults)
+		}
+		return parameters, nil
+	}
+
+	if _, err := os.Stat(against); err == nil {
+		content, err := os.ReadFile(against)
+		if err != nil {
+			return parameters, fmt.Errorf("os.ReadFile: %w", err)
+		}
+
+		err = json.Unmarshal(content, &parameters)
+		if err != nil {
+			return parameters, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		return parameters, nil
+	}
+
+	var againstInstance rds.RDS
+	err := againstInstance.Init(against, profile)
+	if err != nil {
+		return parameters, fmt.Errorf("RDS: Init %s: %w", against, err)
+	}
+
+	return againstInstance.GetDBParameters(), nil
+}
+
+func DiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the parameter group with another instance, the engine defaults or a file",
+		Args:  cobra.NoArgs,
+		RunE:  runDiff,
+	}
+	diffCmd.Flags().String("against", "", "Référence : identifiant d'instance, engine-default ou fichier JSON")
+	diffCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, json, markdown)")
+	return diffCmd
+}
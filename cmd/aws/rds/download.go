@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/events"
+	"github.com/robinportigliatti/cloud_helper/internal/metrics"
 )
 
 // Fonction d'exécution de la commande download
@@ -36,20 +38,30 @@ func runDownload(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("RDS: DownloadLogs: %w", err)
 		}
 	case "metrics":
-		err = rdsInstance.DownloadMetrics(startFlag, endFlag, dirFlag)
+		// Les événements sont tracés sur les graphes des métriques
+		markers := eventMarkers(rdsInstance, dbInstanceIdentifier, startFlag, endFlag, "")
+		err = rdsInstance.DownloadMetrics(startFlag, endFlag, dirFlag, markers...)
 		if err != nil {
 			return fmt.Errorf("RDS: DownloadMetrics: %w", err)
 		}
+	case "events":
+		_, err = downloadEvents(rdsInstance, dbInstanceIdentifier, startFlag, endFlag, dirFlag)
+		if err != nil {
+			return err
+		}
 	case "all":
 		err = rdsInstance.DownloadLogs(startFlag, dirFlag, endFlag)
 		if err != nil {
 			return fmt.Errorf("RDS: DownloadLogs: %w", err)
 		}
 
-		err = rdsInstance.DownloadMetrics(startFlag, endFlag, dirFlag)
+		markers := eventMarkers(rdsInstance, dbInstanceIdentifier, startFlag, endFlag, dirFlag)
+		err = rdsInstance.DownloadMetrics(startFlag, endFlag, dirFlag, markers...)
 		if err != nil {
 			return fmt.Errorf("RDS: DownloadMetrics: %w", err)
 		}
+	default:
+		return fmt.Errorf("type inconnu : %s (logs, metrics, events, all)", typeFlag)
 	}
 
 	slog.Info("Téléchargement terminé",
@@ -62,14 +74,56 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// eventMarkers récupère les événements à tracer sur les graphes des métriques. Les événements ne sont qu'un
+// complément : une erreur (DescribeEvents refusé...) est signalée et les métriques sont téléchargées sans marqueurs
+func eventMarkers(rdsInstance rds.RDS, dbInstanceIdentifier string, startFlag string, endFlag string, directory string) []metrics.Marker {
+	instanceEvents, err := downloadEvents(rdsInstance, dbInstanceIdentifier, startFlag, endFlag, directory)
+	if err != nil {
+		slog.Warn("Événements indisponibles, métriques sans marqueurs", slog.Any("error", err))
+		return nil
+	}
+	return events.Markers(instanceEvents)
+}
+
+// downloadEvents récupère les événements de l'instance sur la période et, si directory est renseigné,
+// les écrit au format CSV et JSON
+func downloadEvents(rdsInstance rds.RDS, dbInstanceIdentifier string, startFlag string, endFlag string, directory string) ([]events.Event, error) {
+	start, err := time.Parse("2006/01/02 15:04:00", startFlag)
+	if err != nil {
+		return nil, fmt.Errorf("--start: %w", err)
+	}
+	end, err := time.Parse("2006/01/02 15:04:00", endFlag)
+	if err != nil {
+		return nil, fmt.Errorf("--end: %w", err)
+	}
+
+	instanceEvents, err := rdsInstance.Events(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("RDS: Events: %w", err)
+	}
+	if directory == "" {
+		return instanceEvents, nil
+	}
+
+	files, err := events.Write(directory, dbInstanceIdentifier, instanceEvents)
+	if err != nil {
+		return nil, fmt.Errorf("events: Write: %w", err)
+	}
+	slog.Info("Événements écrits", slog.Int("events", len(instanceEvents)), slog.Any("files", files))
+	return instanceEvents, nil
+}
+
 func DownloadCmd() *cobra.Command {
 	downloadCmd := &cobra.Command{
 		Use:   "download",
-		Short: "Download RDS logs or metrics",
-		Args:  cobra.MinimumNArgs(0),
-		RunE:  runDownload,
+		Short: "Download RDS logs, metrics or events",
+		Long: `Download RDS logs, CloudWatch metrics or RDS events (reboots, failovers, parameter group changes, maintenance)
+between --start and --end. Events are written as CSV and JSON under <directory>/events and drawn as markers
+on the metric graphs and in the HTML metrics report.`,
+		Args: cobra.MinimumNArgs(0),
+		RunE: SelectorRunE(runDownload),
 	}
-	downloadCmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, metrics)")
+	downloadCmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, metrics, events, all)")
 	downloadCmd.Flags().String("start", time.Now().AddDate(0, 0, -1).Format("2006/01/02 15:04:00"), "Date de début")
 	downloadCmd.Flags().String("end", time.Now().Format("2006/01/02 15:04:00"), "Date de fin")
 	downloadCmd.Flags().String("directory", "./", "Répertoire de destination")
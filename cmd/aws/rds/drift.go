@@ -0,0 +1,60 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/drift"
+)
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	var expected []drift.Expected
+	for _, parameter := range rdsInstance.GetDBParameters().Parameters {
+		// Les formules ({DBInstanceClassMemory/32768}...) ne sont pas comparables
+		if parameter.ParameterValue == "" || strings.HasPrefix(parameter.ParameterValue, "{") {
+			continue
+		}
+		expected = append(expected, drift.Expected{Name: parameter.ParameterName, Value: parameter.ParameterValue})
+	}
+
+	connection, err := getConnection(cmd, rdsInstance)
+	if err != nil {
+		return err
+	}
+
+	items, err := drift.Report(context.Background(), connection, expected)
+	if err != nil {
+		return fmt.Errorf("drift: Report: %w", err)
+	}
+
+	return drift.PrintItems(format, items)
+}
+
+func DriftCmd() *cobra.Command {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare the parameter group with the live pg_settings of the instance",
+		Args:  cobra.NoArgs,
+		RunE:  runDrift,
+	}
+	addConnectionFlags(driftCmd)
+	driftCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json)")
+	return driftCmd
+}
@@ -0,0 +1,59 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+)
+
+func runForecast(cmd *cobra.Command, args []string) error {
+	storage, _ := cmd.Flags().GetBool("storage")
+	days, _ := cmd.Flags().GetInt("days")
+	horizon, _ := cmd.Flags().GetInt("horizon")
+	format, _ := cmd.Flags().GetString("format")
+
+	if !storage {
+		return fmt.Errorf("précisez la ressource à prévoir (--storage)")
+	}
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+	if dbInstanceIdentifier == "" {
+		return fmt.Errorf("--db-instance-identifier est obligatoire")
+	}
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	storageForecast, err := rdsInstance.ForecastStorage(days, horizon)
+	if err != nil {
+		return fmt.Errorf("RDS: ForecastStorage: %w", err)
+	}
+
+	return forecast.Print(format, storageForecast)
+}
+
+func ForecastCmd() *cobra.Command {
+	forecastCmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Forecast storage growth of the instance",
+		Long: `Fit a linear trend to the used storage (AllocatedStorage - FreeStorageSpace) over the last days and estimate
+the days until the disk is full and until the autoscaling cap (MaxAllocatedStorage) is reached.
+A storage size and IOPS are recommended within the ranges returned by DescribeValidDBInstanceModifications.`,
+		Args: cobra.NoArgs,
+		RunE: runForecast,
+	}
+	forecastCmd.Flags().Bool("storage", false, "Prévoir l'occupation du stockage")
+	forecastCmd.Flags().Int("days", 30, "Nombre de jours d'historique utilisés pour la tendance")
+	forecastCmd.Flags().Int("horizon", 90, "Nombre de jours couverts par la recommandation")
+	forecastCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return forecastCmd
+}
@@ -0,0 +1,108 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// inventoryRequested indique si --list doit produire l'inventaire multi-régions et multi-comptes
+// plutôt que la liste de la région du profil
+func inventoryRequested(cmd *cobra.Command) bool {
+	for _, name := range []string{"regions", "profiles", "role-arn", "format"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// inventoryTargets construit les couples profil / région à inventorier. Avec --regions=all,
+// les régions activées sont listées pour chaque profil et chaque rôle assumé ; un profil ou un rôle dont
+// les régions ne peuvent pas être listées est ignoré et son erreur retournée avec les autres cibles
+func inventoryTargets(profiles []string, regions []string, roleARNs []string) ([]rds.Target, error) {
+	if len(roleARNs) == 0 {
+		roleARNs = []string{""}
+	}
+	// Sans --regions, seule la région du profil est interrogée
+	if len(regions) == 0 {
+		regions = []string{""}
+	}
+
+	var targets []rds.Target
+	var errs []error
+	for _, profile := range profiles {
+		for _, roleARN := range roleARNs {
+			targetRegions := regions
+			if len(regions) == 1 && strings.EqualFold(regions[0], "all") {
+				var err error
+				targetRegions, err = rds.ListRegions(profile, roleARN)
+				if err != nil {
+					source := profile
+					if roleARN != "" {
+						source = fmt.Sprintf("%s (%s)", profile, roleARN)
+					}
+					errs = append(errs, fmt.Errorf("RDS: ListRegions %s: %w", source, err))
+					continue
+				}
+			}
+			for _, region := range targetRegions {
+				targets = append(targets, rds.Target{Profile: profile, Region: region, RoleARN: roleARN})
+			}
+		}
+	}
+	return targets, errors.Join(errs...)
+}
+
+func runInventory(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString("format")
+	regions, _ := cmd.Flags().GetStringSlice("regions")
+	profiles, _ := cmd.Flags().GetStringSlice("profiles")
+	roleARNs, _ := cmd.Flags().GetStringSlice("role-arn")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	// Sans --profiles, le profil global est utilisé
+	if len(profiles) == 0 {
+		profiles = []string{viper.GetString("profile")}
+	}
+
+	targets, targetsErr := inventoryTargets(profiles, regions, roleARNs)
+	inventory, err := rds.Inventory(targets, concurrency)
+	if err = errors.Join(targetsErr, err); err != nil {
+		if len(inventory) == 0 {
+			return fmt.Errorf("RDS: Inventory: %w", err)
+		}
+		// Les régions et comptes accessibles sont affichés malgré les erreurs
+		slog.Warn("Inventaire incomplet", slog.Any("error", err))
+	}
+
+	if format == "json" {
+		return output.PrintJSON(inventory)
+	}
+
+	columns := []string{"Account", "Region", "Profile", "DBClusterIdentifier", "DBInstanceIdentifier", "Role", "Engine", "EngineVersion", "DBInstanceClass", "Status", "Endpoint", "Port"}
+	rows := make([][]string, 0, len(inventory))
+	for _, entry := range inventory {
+		rows = append(rows, []string{
+			entry.Account,
+			entry.Region,
+			entry.Profile,
+			entry.DBClusterIdentifier,
+			entry.DBInstanceIdentifier,
+			entry.Role,
+			entry.Engine,
+			entry.EngineVersion,
+			entry.DBInstanceClass,
+			entry.Status,
+			entry.Endpoint,
+			fmt.Sprintf("%d", entry.Port),
+		})
+	}
+	return output.PrintRows(format, columns, rows)
+}
@@ -1,12 +1,15 @@
 package rds
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/robinportigliatti/cloud_helper/cmd/aws/rds/psql"
 	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
 )
 
 func runPsql(cmd *cobra.Command, args []string) error {
@@ -21,9 +24,114 @@ func runPsql(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("RDS: Init: %w", err)
 	}
 
-	// Implémenter la logique pour la commande psql
-	fmt.Println("Connected to PostgreSQL instance:", rdsInstance.GetdbInstance().DBInstanceIdentifier)
-	return nil
+	iam, _ := cmd.Flags().GetBool("iam")
+	secret, _ := cmd.Flags().GetBool("secret")
+	pgpass, _ := cmd.Flags().GetBool("pgpass")
+	username, _ := cmd.Flags().GetString("username")
+	database, _ := cmd.Flags().GetString("dbname")
+	command, _ := cmd.Flags().GetString("command")
+	format, _ := cmd.Flags().GetString("format")
+
+	if username == "" {
+		username = rdsInstance.GetdbInstance().MasterUsername
+	}
+
+	if pgpass {
+		switch {
+		case iam:
+			// Le jeton IAM n'est valable que 15 minutes
+			str, err := rdsInstance.GenPgPassIAM(username, database)
+			if err != nil {
+				return fmt.Errorf("RDS: GenPgPassIAM: %w", err)
+			}
+			fmt.Println(str)
+		case secret:
+			entries, err := rdsInstance.GetPgPassEntries(database)
+			for _, entry := range entries {
+				fmt.Println(entry.String())
+			}
+			if err != nil {
+				return fmt.Errorf("RDS: GetPgPassEntries: %w", err)
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("RDS: %w", rds.ErrNoMasterUserSecret)
+			}
+		default:
+			return fmt.Errorf("le flag --pgpass nécessite --iam ou --secret")
+		}
+		return nil
+	}
+
+	connection, err := getConnection(cmd, rdsInstance)
+	if err != nil {
+		return err
+	}
+
+	if command == "" {
+		err = postgres.LaunchPsql(connection)
+		if err != nil {
+			return fmt.Errorf("LaunchPsql: %w", err)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	conn, err := postgres.Connect(ctx, connection)
+	if err != nil {
+		return fmt.Errorf("postgres: Connect: %w", err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	columns, rows, err := postgres.Query(ctx, conn, command)
+	if err != nil {
+		return fmt.Errorf("postgres: Query: %w", err)
+	}
+
+	return output.PrintRows(format, columns, rows)
+}
+
+// getConnection construit les paramètres de connexion à l'instance selon les flags :
+// jeton IAM, secret Secrets Manager, ou mot de passe passé en flag (à défaut .pgpass)
+func getConnection(cmd *cobra.Command, rdsInstance rds.RDS) (postgres.Connection, error) {
+	iam, _ := cmd.Flags().GetBool("iam")
+	secret, _ := cmd.Flags().GetBool("secret")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	if username == "" {
+		username = rdsInstance.GetdbInstance().MasterUsername
+	}
+
+	switch {
+	case iam:
+		connection, err := rdsInstance.GetIAMConnection(username, database)
+		if err != nil {
+			return connection, fmt.Errorf("RDS: GetIAMConnection: %w", err)
+		}
+		return connection, nil
+	case secret:
+		connection, err := rdsInstance.GetSecretConnection(database)
+		if err != nil {
+			return connection, fmt.Errorf("RDS: GetSecretConnection: %w", err)
+		}
+		return connection, nil
+	default:
+		connection, err := rdsInstance.GetConnection(username, database, password)
+		if err != nil {
+			return connection, fmt.Errorf("RDS: GetConnection: %w", err)
+		}
+		return connection, nil
+	}
+}
+
+// addConnectionFlags ajoute les flags de connexion à l'instance utilisés par getConnection
+func addConnectionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("iam", false, "Utiliser l'authentification IAM (jeton signé localement, sslmode=verify-full)")
+	cmd.Flags().Bool("secret", false, "Utiliser le mot de passe maître stocké dans Secrets Manager (ManageMasterUserPassword)")
+	cmd.Flags().String("username", "", "Nom d'utilisateur PostgreSQL (par défaut : utilisateur maître)")
+	cmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	cmd.Flags().String("dbname", "postgres", "Nom de la base de données")
 }
 
 func PsqlCmd() *cobra.Command {
@@ -33,6 +141,12 @@ func PsqlCmd() *cobra.Command {
 		Args:  cobra.MinimumNArgs(0),
 		RunE:  runPsql,
 	}
-	psqlCmd.AddCommand(psql.ShowCmd())
+	addConnectionFlags(psqlCmd)
+	psqlCmd.Flags().Bool("pgpass", false, "Afficher une ligne .pgpass au lieu de lancer psql (avec --iam ou --secret)")
+	psqlCmd.Flags().StringP("command", "c", "", "Requête SQL à exécuter au lieu de lancer psql")
+	psqlCmd.Flags().StringP("format", "F", "default", "Format de sortie de la requête (default, csv, json)")
+	showCmd := psql.ShowCmd(getConnection)
+	addConnectionFlags(showCmd)
+	psqlCmd.AddCommand(showCmd)
 	return psqlCmd
 }
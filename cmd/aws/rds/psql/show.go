@@ -1,21 +1,30 @@
 package psql
 
 import (
-	"encoding/csv"
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
 )
 
 var outputFormat string
 var force bool
+var nonDefault bool
+var parameterGroup bool
 
-func runShow(cmd *cobra.Command, args []string) error {
+// ConnectionFunc construit les paramètres de connexion à l'instance à partir des flags de la commande
+type ConnectionFunc func(cmd *cobra.Command, rdsInstance rds.RDS) (postgres.Connection, error)
+
+// liveSettingsQuery retourne la valeur active de chaque paramètre telle que SHOW l'affiche, et son origine
+const liveSettingsQuery = `SELECT name, current_setting(name), source FROM pg_settings ORDER BY name`
+
+func runShow(cmd *cobra.Command, args []string, connect ConnectionFunc) error {
 	// Récupération des variables globales
 	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
 	profile := viper.GetString("profile")
@@ -27,19 +36,42 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("RDS: Init: %w", err)
 	}
 
-	if len(args) == 0 {
-		if !force {
-			fmt.Print("Voulez-vous récupérer tous les paramètres ? (y/n): ")
-			var response string
-			_, err = fmt.Scanln(&response)
-			if err != nil {
-				return fmt.Errorf("scanln: %w", err)
-			}
+	if nonDefault {
+		// Uniquement les paramètres personnalisés
+		nonDefaultParameters, err := rdsInstance.GetNonDefaultParameters()
+		if err != nil {
+			return fmt.Errorf("RDS: GetNonDefaultParameters: %w", err)
+		}
 
-			if strings.ToLower(response) != "y" {
-				return nil
+		parameters := make([]output.Setting, 0)
+		for _, parameter := range nonDefaultParameters {
+			if len(args) > 0 && !slices.Contains(args, parameter.ParameterName) {
+				continue
 			}
+			parameters = append(parameters, output.Setting{Name: parameter.ParameterName, Value: parameter.ParameterValue, Level: parameter.Level})
 		}
+
+		return output.PrintSettings(outputFormat, parameters)
+	}
+
+	if len(args) == 0 && !force {
+		fmt.Print("Voulez-vous récupérer tous les paramètres ? (y/n): ")
+		var response string
+		_, err = fmt.Scanln(&response)
+		if err != nil {
+			return fmt.Errorf("scanln: %w", err)
+		}
+
+		if strings.ToLower(response) != "y" {
+			return nil
+		}
+	}
+
+	if !parameterGroup {
+		return showLiveSettings(cmd, args, rdsInstance, connect)
+	}
+
+	if len(args) == 0 {
 		// Récupérer tous les paramètres
 		args, err = rdsInstance.GetAllParameterNames()
 		if err != nil {
@@ -47,52 +79,74 @@ func runShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	parameters := make([]map[string]string, 0)
+	parameters := make([]output.Setting, 0)
 	for _, setting := range args {
-		value, err := rdsInstance.GetParameterValueByParameterName(setting)
+		parameter, err := rdsInstance.GetParameterByParameterName(setting)
 		if err != nil {
-			return fmt.Errorf("RDS: GetParameterValueByParameterName: %w", err)
+			return fmt.Errorf("RDS: GetParameterByParameterName: %w", err)
 		}
-		parameters = append(parameters, map[string]string{"Name": setting, "Value": value})
+		parameters = append(parameters, output.Setting{Name: setting, Value: parameter.ParameterValue, Level: parameter.Level})
 	}
 
-	switch outputFormat {
-	case "csv":
-		w := csv.NewWriter(os.Stdout)
-		for _, param := range parameters {
-			if err := w.Write([]string{param["Name"], param["Value"]}); err != nil {
-				return fmt.Errorf("CSV Write: %w", err)
-			}
-		}
-		w.Flush()
-	case "json":
-		data, err := json.MarshalIndent(parameters, "", "  ")
-		if err != nil {
-			return fmt.Errorf("JSON Marshal: %w", err)
+	return output.PrintSettings(outputFormat, parameters)
+}
+
+// showLiveSettings affiche les paramètres actifs du serveur (pg_settings), avec leur origine
+// (configuration file pour le groupe de paramètres, database, user, default...)
+func showLiveSettings(cmd *cobra.Command, args []string, rdsInstance rds.RDS, connect ConnectionFunc) error {
+	connection, err := connect(cmd, rdsInstance)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := postgres.Connect(ctx, connection)
+	if err != nil {
+		return fmt.Errorf("postgres: Connect: %w", err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	_, rows, err := postgres.Query(ctx, conn, liveSettingsQuery)
+	if err != nil {
+		return fmt.Errorf("pg_settings: %w", err)
+	}
+
+	live := make(map[string]output.Setting, len(rows))
+	parameters := make([]output.Setting, 0, len(rows))
+	for _, row := range rows {
+		setting := output.Setting{Name: row[0], Value: row[1], Level: row[2]}
+		live[strings.ToLower(setting.Name)] = setting
+		if len(args) == 0 {
+			parameters = append(parameters, setting)
 		}
-		fmt.Println(string(data))
-	default:
-		if len(parameters) == 1 {
-			for _, param := range parameters {
-				fmt.Println(param["Value"])
-			}
-		} else {
-			for _, param := range parameters {
-				fmt.Printf("%s = %s\n", param["Name"], param["Value"])
-			}
+	}
+
+	for _, name := range args {
+		setting, ok := live[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("paramètre %s introuvable dans pg_settings", name)
 		}
+		parameters = append(parameters, setting)
 	}
-	return nil
+
+	return output.PrintSettings(outputFormat, parameters)
 }
 
-func ShowCmd() *cobra.Command {
+func ShowCmd(connect ConnectionFunc) *cobra.Command {
 	showCmd := &cobra.Command{
 		Use:   "show [settings...]",
 		Short: "Show PostgreSQL settings",
 		Args:  cobra.MinimumNArgs(0),
-		RunE:  runShow,
+		Long: `Show the live settings of the server (pg_settings, with the source of each value), over a connection
+opened with the psql connection flags. With --parameter-group, read the RDS parameter group instead
+(cluster or instance level on Aurora). --non-default always reads the parameter group.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShow(cmd, args, connect)
+		},
 	}
 	showCmd.Flags().StringVarP(&outputFormat, "format", "F", "default", "Format de sortie (default, csv, json)")
 	showCmd.Flags().BoolVarP(&force, "force", "f", false, "Ne pas demander confirmation pour récupérer tous les paramètres")
+	showCmd.Flags().BoolVar(&nonDefault, "non-default", false, "Afficher uniquement les paramètres du groupe de paramètres dont la valeur diffère de la valeur par défaut")
+	showCmd.Flags().BoolVar(&parameterGroup, "parameter-group", false, "Lire le groupe de paramètres RDS au lieu de pg_settings sur le serveur")
 	return showCmd
 }
@@ -19,6 +19,9 @@ var list bool
 var RdsCmd = &cobra.Command{
 	Use:   "rds",
 	Short: "Interact with PostgreSQL",
+	Long: `Interact with RDS PostgreSQL instances.
+With --list, list the instances of the profile region. With --regions, --profiles or --role-arn, build a merged
+inventory of several regions and accounts, scanned in parallel (--concurrency at a time), with account and region columns.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		// Lier les flags à Viper pour pouvoir les récupérer partout
 		viper.Set("profile", profile)
@@ -31,6 +34,10 @@ var RdsCmd = &cobra.Command{
 func RunRdsCmd(cmd *cobra.Command, args []string) error {
 	list := viper.GetBool("list")
 
+	if list && inventoryRequested(cmd) {
+		return runInventory(cmd)
+	}
+
 	if list {
 		var rds rdsPkg.RDS
 		profile := viper.GetString("profile")
@@ -43,11 +50,39 @@ func RunRdsCmd(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("RDS: DescribeDbInstances: %w", err)
 		}
+		dbClusters, err := rds.GetDbClusters()
+		if err != nil {
+			return fmt.Errorf("RDS: DescribeDbClusters: %w", err)
+		}
+
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+		// Clusters Aurora et leurs membres
+		for _, dbCluster := range dbClusters {
+			_, _ = fmt.Fprintf(w, "Cluster %s (%s %s) |\t Writer: %s |\t Reader: %s |\t %d\n", dbCluster.DBClusterIdentifier, dbCluster.Engine, dbCluster.Status, dbCluster.Endpoint, dbCluster.ReaderEndpoint, dbCluster.Port)
+			_, _ = fmt.Fprintln(w, "DBInstanceIdentifier |\tRole |\tDBInstanceStatus |\t Endpoint |\t Port")
+			for _, dbInstance := range dbInstances {
+				member, ok := dbCluster.GetMember(dbInstance.DBInstanceIdentifier)
+				if !ok {
+					continue
+				}
+				role := "reader"
+				if member.IsClusterWriter {
+					role = "writer"
+				}
+				_, _ = fmt.Fprintf(w, "%s |\t %s |\t %s |\t %s |\t %d\n", dbInstance.DBInstanceIdentifier, role, dbInstance.DBInstanceStatus, dbInstance.Endpoint.Address, dbInstance.Endpoint.Port)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+
+		// Instances hors cluster
 		_, _ = fmt.Fprintln(w, "DBInstanceIdentifier |\tDBInstanceStatus |\t Endpoint |\t Port")
 
 		// Remplissage du tableau avec les données
 		for _, dbInstance := range dbInstances {
+			if dbInstance.DBClusterIdentifier != "" {
+				continue
+			}
 			_, _ = fmt.Fprintf(w, "%s |\t %s |\t %s |\t %d\n", dbInstance.DBInstanceIdentifier, dbInstance.DBInstanceStatus, dbInstance.Endpoint.Address, dbInstance.Endpoint.Port)
 		}
 
@@ -84,6 +119,12 @@ func init() {
 	RdsCmd.PersistentFlags().StringVar(&profile, "profile", "default", "Profil AWS à utiliser")
 	RdsCmd.PersistentFlags().StringVar(&dbInstanceIdentifier, "db-instance-identifier", "", "Identifiant de l'instance RDS")
 	RdsCmd.PersistentFlags().BoolVar(&list, "list", false, "Identifiant de l'instance RDS")
+	RdsCmd.PersistentFlags().String("selector", "", "Sélection des instances par étiquettes (env=prod,team=payments) pour download, check et generate")
+	RdsCmd.Flags().StringSlice("regions", nil, "Régions à inventorier avec --list, séparées par des virgules, ou all pour toutes les régions activées")
+	RdsCmd.Flags().StringSlice("profiles", nil, "Profils AWS à inventorier avec --list, séparés par des virgules (défaut : --profile)")
+	RdsCmd.Flags().StringSlice("role-arn", nil, "Rôles IAM à assumer (AssumeRole) avec chaque profil pour inventorier d'autres comptes")
+	RdsCmd.Flags().Int("concurrency", 8, "Nombre maximal de couples région / compte inventoriés en parallèle")
+	RdsCmd.Flags().StringP("format", "F", "default", "Format de sortie de l'inventaire (default, csv, json, markdown)")
 	err := viper.BindPFlag("profile", RdsCmd.PersistentFlags().Lookup("profile"))
 	if err != nil {
 		slog.Error("Erreur lors du binding du flag profile", slog.Any("error", err))
@@ -98,4 +139,16 @@ func init() {
 
 	RdsCmd.AddCommand(PsqlCmd())
 	RdsCmd.AddCommand(DownloadCmd())
+	RdsCmd.AddCommand(DriftCmd())
+	RdsCmd.AddCommand(DiffCmd())
+	RdsCmd.AddCommand(SetCmd())
+	RdsCmd.AddCommand(CloneCmd())
+	RdsCmd.AddCommand(CheckCmd())
+	RdsCmd.AddCommand(SecurityCmd())
+	RdsCmd.AddCommand(CertificatesCmd())
+	RdsCmd.AddCommand(ForecastCmd())
+	RdsCmd.AddCommand(RecommendCmd())
+	RdsCmd.AddCommand(UpgradePlanCmd())
+	RdsCmd.AddCommand(RestoreTestCmd())
+	RdsCmd.AddCommand(TopologyCmd())
 }
@@ -0,0 +1,58 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/sizing"
+)
+
+func runRecommend(cmd *cobra.Command, args []string) error {
+	sizingFlag, _ := cmd.Flags().GetBool("sizing")
+	window, _ := cmd.Flags().GetInt("window")
+	format, _ := cmd.Flags().GetString("format")
+
+	if !sizingFlag {
+		return fmt.Errorf("précisez la recommandation souhaitée (--sizing)")
+	}
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+	if dbInstanceIdentifier == "" {
+		return fmt.Errorf("--db-instance-identifier est obligatoire")
+	}
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	recommendation, err := rdsInstance.RecommendSizing(window)
+	if err != nil {
+		return fmt.Errorf("RDS: RecommendSizing: %w", err)
+	}
+
+	return sizing.Print(format, recommendation)
+}
+
+func RecommendCmd() *cobra.Command {
+	recommendCmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Recommend a right-sized instance class",
+		Long: `Analyse p95 and max of CPUUtilization, memory used (from FreeableMemory), DatabaseConnections, ReadIOPS and WriteIOPS
+over the window, then suggest a larger or smaller instance class of the same family, with the reasons.
+IOPS are compared with the EBS baseline of the class and with the storage limit (provisioned IOPS or gp2 baseline).
+Parameters of the parameter group whose formula depends on DBInstanceClassMemory or DBInstanceVCPU are evaluated for both classes.`,
+		Args: cobra.NoArgs,
+		RunE: runRecommend,
+	}
+	recommendCmd.Flags().Bool("sizing", false, "Recommander une classe d'instance")
+	recommendCmd.Flags().Int("window", 14, "Nombre de jours analysés")
+	recommendCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return recommendCmd
+}
@@ -0,0 +1,168 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/restore"
+)
+
+func runRestoreTest(cmd *cobra.Command, args []string) error {
+	create, _ := cmd.Flags().GetBool("create")
+	wait, _ := cmd.Flags().GetBool("wait")
+	validateFile, _ := cmd.Flags().GetString("validate")
+	deleteClone, _ := cmd.Flags().GetBool("delete")
+	cloneName, _ := cmd.Flags().GetString("clone-name")
+	restoreTimeFlag, _ := cmd.Flags().GetString("restore-time")
+	instanceClass, _ := cmd.Flags().GetString("instance-class")
+	extraTags, _ := cmd.Flags().GetStringToString("tag")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	reportFile, _ := cmd.Flags().GetString("report")
+	format, _ := cmd.Flags().GetString("format")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+	if dbInstanceIdentifier == "" {
+		return fmt.Errorf("--db-instance-identifier est obligatoire")
+	}
+
+	if !create && !wait && validateFile == "" && !deleteClone {
+		return fmt.Errorf("aucune étape demandée (--create, --wait, --validate, --delete)")
+	}
+	if cloneName == "" {
+		if !create {
+			return fmt.Errorf("--clone-name est obligatoire sans --create")
+		}
+		cloneName = restore.GenerateName(dbInstanceIdentifier, time.Now())
+	}
+
+	var restoreTime *time.Time
+	if restoreTimeFlag != "" {
+		parsed, err := time.Parse(time.RFC3339, restoreTimeFlag)
+		if err != nil {
+			return fmt.Errorf("--restore-time: %w", err)
+		}
+		restoreTime = &parsed
+	}
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	tags := restore.Tags(dbInstanceIdentifier, extraTags)
+	report := restore.NewReport("rds", dbInstanceIdentifier, cloneName, restoreTime, tags)
+
+	if create {
+		err = report.Run(restore.StepCreate, func() (string, error) {
+			if err := rdsInstance.RestoreToPointInTime(cloneName, restoreTime, tags, instanceClass); err != nil {
+				return "", fmt.Errorf("RDS: RestoreToPointInTime: %w", err)
+			}
+			return fmt.Sprintf("restauration de %s vers %s", dbInstanceIdentifier, cloneName), nil
+		})
+	} else {
+		report.Skip(restore.StepCreate, "--create absent")
+	}
+
+	if err == nil && wait {
+		err = report.Run(restore.StepWait, func() (string, error) {
+			return restore.Wait(func() (string, bool, error) { return rdsInstance.DBInstanceStatus(cloneName) }, restore.PollInterval, timeout)
+		})
+	} else {
+		report.Skip(restore.StepWait, "--wait absent ou étape précédente en échec")
+	}
+
+	if err == nil && validateFile != "" {
+		err = report.Run(restore.StepValidate, func() (string, error) {
+			var clone rds.RDS
+			if err := clone.Init(cloneName, profile); err != nil {
+				return "", fmt.Errorf("RDS: Init: %w", err)
+			}
+			connection, err := getConnection(cmd, clone)
+			if err != nil {
+				return "", err
+			}
+			return restore.Validate(context.Background(), connection, validateFile)
+		})
+	} else {
+		report.Skip(restore.StepValidate, "--validate absent ou étape précédente en échec")
+	}
+
+	// Le clone est supprimé même si la validation a échoué, mais pas si sa création ou l'attente a échoué
+	reason, deleteBlocked := report.DeleteBlocked()
+	switch {
+	case !deleteClone:
+		report.Skip(restore.StepDelete, "--delete absent")
+	case deleteBlocked:
+		slog.Warn("Suppression du clone ignorée", slog.String("clone", cloneName), slog.String("reason", reason))
+		report.Skip(restore.StepDelete, reason)
+	default:
+		deleteErr := report.Run(restore.StepDelete, func() (string, error) {
+			cloneTags, err := rdsInstance.DBInstanceTags(cloneName)
+			if err != nil {
+				return "", fmt.Errorf("RDS: DBInstanceTags: %w", err)
+			}
+			if err := restore.CheckDeletable(cloneName, dbInstanceIdentifier, cloneTags); err != nil {
+				return "", err
+			}
+			if err := rdsInstance.DeleteDBInstance(cloneName); err != nil {
+				return "", fmt.Errorf("RDS: DeleteDBInstance: %w", err)
+			}
+			return fmt.Sprintf("suppression de %s lancée, sans snapshot final", cloneName), nil
+		})
+		if err == nil {
+			err = deleteErr
+		}
+	}
+
+	if reportFile == "" {
+		reportFile = cloneName + "-restore-test.md"
+	}
+	if writeErr := restore.Write(reportFile, report); writeErr != nil {
+		return fmt.Errorf("restore: Write: %w", writeErr)
+	}
+	slog.Info("Rapport de restauration écrit", slog.String("file", reportFile))
+
+	if printErr := restore.Print(format, report); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func RestoreTestCmd() *cobra.Command {
+	restoreTestCmd := &cobra.Command{
+		Use:   "restore-test",
+		Short: "Restore the instance to a point in time into a clone, validate it and delete it",
+		Long: `Test that backups can be restored. Each step runs only when its flag is given:
+  --create    restore the instance to a point in time (--restore-time, default latest restorable time) into a clone
+              with a generated name and restore-test tags
+  --wait      wait until the clone is available, with progress output
+  --validate  run a SQL script on the clone (connection flags as for psql)
+  --delete    delete the clone without final snapshot; only a clone tagged by --create can be deleted;
+              skipped when --wait failed or timed out
+The duration of each step and the restore time are written to a report (Markdown, or JSON with a .json extension).`,
+		Args: cobra.NoArgs,
+		RunE: runRestoreTest,
+	}
+	restoreTestCmd.Flags().Bool("create", false, "Créer le clone")
+	restoreTestCmd.Flags().Bool("wait", false, "Attendre que le clone soit disponible")
+	restoreTestCmd.Flags().String("validate", "", "Script SQL de validation exécuté sur le clone")
+	restoreTestCmd.Flags().Bool("delete", false, "Supprimer le clone à la fin")
+	restoreTestCmd.Flags().String("clone-name", "", "Nom du clone (par défaut : <instance>-rt-<date>)")
+	restoreTestCmd.Flags().String("restore-time", "", "Instant restauré, RFC3339 (par défaut : dernier instant restaurable)")
+	restoreTestCmd.Flags().String("instance-class", "", "Classe d'instance du clone (par défaut : celle de la source)")
+	restoreTestCmd.Flags().StringToString("tag", nil, "Étiquettes supplémentaires du clone (key=value)")
+	restoreTestCmd.Flags().Duration("timeout", 2*time.Hour, "Durée maximale d'attente du clone")
+	restoreTestCmd.Flags().String("report", "", "Fichier du rapport (par défaut : <clone>-restore-test.md)")
+	addConnectionFlags(restoreTestCmd)
+	restoreTestCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return restoreTestCmd
+}
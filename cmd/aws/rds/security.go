@@ -0,0 +1,47 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+func runSecurity(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	expiryDays, _ := cmd.Flags().GetInt("expiry-days")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	findings, err := rdsInstance.SecurityFindings(expiryDays)
+	if err != nil {
+		return fmt.Errorf("RDS: SecurityFindings: %w", err)
+	}
+
+	return security.PrintFindings(format, findings)
+}
+
+func SecurityCmd() *cobra.Command {
+	securityCmd := &cobra.Command{
+		Use:   "security",
+		Short: "Audit the security posture of the instance",
+		Long: `Audit the security posture of the instance: public exposure (security groups open to 0.0.0.0/0 on the PostgreSQL port),
+TLS enforcement (rds.force_ssl), storage encryption, password-only authentication, CA certificate expiry and deletion protection.`,
+		Args: cobra.NoArgs,
+		RunE: runSecurity,
+	}
+	securityCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	securityCmd.Flags().Int("expiry-days", 90, "Signaler les autorités de certification qui expirent dans ce nombre de jours")
+	return securityCmd
+}
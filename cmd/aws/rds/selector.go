@@ -0,0 +1,39 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// SelectorRunE exécute la commande pour chaque instance dont les étiquettes satisfont --selector,
+// avec un résumé par instance à la fin. Sans sélecteur, la commande est exécutée une seule fois
+func SelectorRunE(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		expression, _ := cmd.Flags().GetString("selector")
+		if expression == "" {
+			return run(cmd, args)
+		}
+		if viper.GetString("db-instance-identifier") != "" {
+			return fmt.Errorf("--db-instance-identifier et --selector sont incompatibles")
+		}
+
+		sel, err := selector.Parse(expression)
+		if err != nil {
+			return fmt.Errorf("selector: Parse: %w", err)
+		}
+
+		identifiers, err := rds.SelectInstances(viper.GetString("profile"), sel)
+		if err != nil {
+			return fmt.Errorf("RDS: SelectInstances: %w", err)
+		}
+
+		return selector.ForEach(identifiers, func(dbInstanceIdentifier string) error {
+			viper.Set("db-instance-identifier", dbInstanceIdentifier)
+			return run(cmd, args)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+func runSet(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	assignments, err := pgsettings.ParseAssignments(args)
+	if err != nil {
+		return err
+	}
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Initialisation de la connexion à RDS
+	var rdsInstance rds.RDS
+	err = rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	changes, err := rdsInstance.PlanParameterChanges(assignments)
+	if errors.Is(err, rds.ErrDefaultParameterGroup) {
+		// Proposer de cloner le groupe par défaut avec les valeurs demandées
+		slog.Warn("Le groupe de paramètres par défaut ne peut pas être modifié", slog.Any("error", err))
+		if dryRun {
+			return cloneParameterGroup(&rdsInstance, rds.DefaultCloneNameTemplate, assignments, true, dryRun, force, true)
+		}
+
+		if !force {
+			fmt.Print("Cloner le groupe par défaut avec ces valeurs et l'attacher à l'instance (ou au cluster) ? (y/n): ")
+			var response string
+			_, err = fmt.Scanln(&response)
+			if err != nil {
+				return fmt.Errorf("scanln: %w", err)
+			}
+
+			if strings.ToLower(response) != "y" {
+				return nil
+			}
+		}
+		return cloneParameterGroup(&rdsInstance, rds.DefaultCloneNameTemplate, assignments, true, dryRun, true, true)
+	}
+	if err != nil {
+		return fmt.Errorf("RDS: PlanParameterChanges: %w", err)
+	}
+
+	if len(changes) == 0 {
+		slog.Info("Aucune modification : les paramètres sont déjà à la valeur demandée")
+		return nil
+	}
+
+	err = printParameterChanges(changes)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		fmt.Print("Appliquer ces modifications ? (y/n): ")
+		var response string
+		_, err = fmt.Scanln(&response)
+		if err != nil {
+			return fmt.Errorf("scanln: %w", err)
+		}
+
+		if strings.ToLower(response) != "y" {
+			return nil
+		}
+	}
+
+	err = rdsInstance.ApplyParameterChanges(changes)
+	if err != nil {
+		return fmt.Errorf("RDS: ApplyParameterChanges: %w", err)
+	}
+
+	return reportReboot(&rdsInstance, changes)
+}
+
+// printParameterChanges affiche le diff des modifications planifiées
+func printParameterChanges(changes []rds.ParameterChange) error {
+	columns := []string{"ParameterGroup", "ParameterName", "Current", "New", "ApplyType", "ApplyMethod"}
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []string{change.ParameterGroup, change.ParameterName, change.CurrentValue, change.ParameterValue, change.ApplyType, change.ApplyMethod})
+	}
+	return output.PrintRows("default", columns, rows)
+}
+
+// reportReboot indique les instances à redémarrer pour appliquer les paramètres statiques
+func reportReboot(rdsInstance *rds.RDS, changes []rds.ParameterChange) error {
+	instances, err := rdsInstance.GetInstancesNeedingReboot(changes)
+	if err != nil {
+		return fmt.Errorf("RDS: GetInstancesNeedingReboot: %w", err)
+	}
+
+	if len(instances) == 0 {
+		slog.Info("Modifications appliquées, aucun redémarrage nécessaire")
+		return nil
+	}
+
+	slog.Warn("Modifications appliquées, redémarrage nécessaire", slog.Any("instances", instances))
+	for _, instance := range instances {
+		fmt.Printf("aws rds reboot-db-instance --db-instance-identifier %s\n", instance)
+	}
+	return nil
+}
+
+func SetCmd() *cobra.Command {
+	setCmd := &cobra.Command{
+		Use:   "set name=value [name=value...]",
+		Short: "Modify parameters of the instance parameter group",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSet,
+	}
+	setCmd.Flags().Bool("dry-run", false, "Afficher les modifications sans les appliquer")
+	setCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation avant d'appliquer les modifications")
+	return setCmd
+}
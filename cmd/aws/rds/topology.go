@@ -0,0 +1,54 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+func runTopology(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+
+	// Toutes les instances de la région sont chargées pour relier sources et réplicas
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init("", profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	instanceTopology, err := rdsInstance.Topology()
+	if err != nil {
+		return fmt.Errorf("RDS: Topology: %w", err)
+	}
+
+	if dbInstanceIdentifier != "" {
+		instanceTopology = instanceTopology.Family(dbInstanceIdentifier)
+		if len(instanceTopology.Nodes) == 0 {
+			return fmt.Errorf("instance %s introuvable", dbInstanceIdentifier)
+		}
+	}
+
+	return topology.Print(format, instanceTopology)
+}
+
+func TopologyCmd() *cobra.Command {
+	topologyCmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Show the replication topology of the RDS instances",
+		Long: `Show primaries, read replicas and Aurora clusters (writer and readers) of the region, with cross-region replicas.
+Each node shows the maximum replica lag (ReplicaLag, AuroraReplicaLag) and replication slot lag (OldestReplicationSlotLag)
+over the last hour. With --db-instance-identifier, only the topology containing the instance is shown.
+Output as a tree (default), Graphviz (dot), Mermaid (mermaid), csv, markdown or json.`,
+		Args: cobra.NoArgs,
+		RunE: runTopology,
+	}
+	topologyCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, dot, mermaid, csv, json, markdown)")
+	return topologyCmd
+}
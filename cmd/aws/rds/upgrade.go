@@ -0,0 +1,81 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+func runUpgradePlan(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	extensions, _ := cmd.Flags().GetBool("extensions")
+
+	// Récupération des variables globales
+	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
+	profile := viper.GetString("profile")
+	if extensions && dbInstanceIdentifier == "" {
+		return fmt.Errorf("--extensions nécessite --db-instance-identifier")
+	}
+
+	calendar, err := upgrade.LoadCalendar()
+	if err != nil {
+		return fmt.Errorf("upgrade: LoadCalendar: %w", err)
+	}
+
+	// Initialisation de la connexion à RDS ; sans identifiant, toutes les instances de la région
+	var rdsInstance rds.RDS
+	err = rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	plans, err := rdsInstance.UpgradePlans(calendar)
+	if err != nil {
+		return fmt.Errorf("RDS: UpgradePlans: %w", err)
+	}
+
+	// Les extensions sont lues sur une seule instance : elles ne valent que pour son plan
+	if extensions && len(plans) > 1 {
+		return fmt.Errorf("--extensions ne vérifie qu'une instance, %d plans ont été produits", len(plans))
+	}
+	if extensions && len(plans) > 0 {
+		connection, err := getConnection(cmd, rdsInstance)
+		if err != nil {
+			return err
+		}
+		plan := &plans[0]
+		supported, err := rdsInstance.SupportedExtensions(rdsInstance.GetdbInstance().Engine, plan.Path[len(plan.Path)-1])
+		if err != nil {
+			return fmt.Errorf("RDS: SupportedExtensions: %w", err)
+		}
+		err = plan.CheckExtensions(context.Background(), calendar, connection, supported)
+		if err != nil {
+			return fmt.Errorf("upgrade: CheckExtensions: %w", err)
+		}
+	}
+
+	return upgrade.Print(format, plans)
+}
+
+func UpgradePlanCmd() *cobra.Command {
+	upgradePlanCmd := &cobra.Command{
+		Use:   "upgrade-plan",
+		Short: "Plan the engine upgrade of the instances",
+		Long: `List the valid upgrade targets of each PostgreSQL instance (DescribeDBEngineVersions), flag versions close to
+their end of support from the embedded calendar, and chain the latest targets into an upgrade path.
+Without --db-instance-identifier, every instance of the region is planned.
+With --extensions, the installed extensions are checked against the final version of the path: removed extensions,
+extensions missing from the allowed values of rds.extensions in the engine defaults of the target family, and
+extensions to update. The default versions are read on the current server, not on the target version.`,
+		Args: cobra.NoArgs,
+		RunE: runUpgradePlan,
+	}
+	upgradePlanCmd.Flags().Bool("extensions", false, "Vérifier les extensions installées (connexion à l'instance)")
+	addConnectionFlags(upgradePlanCmd)
+	upgradePlanCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, markdown, json)")
+	return upgradePlanCmd
+}
@@ -89,6 +89,7 @@ func init() {
 	AzureCmd.PersistentFlags().StringVar(&serverName, "server-name", "", "PostgreSQL Flexible Server Name")
 	AzureCmd.PersistentFlags().StringVar(&subscription, "subscription", "", "Azure Subscription ID")
 	AzureCmd.PersistentFlags().BoolVar(&list, "list", false, "List all PostgreSQL Flexible Servers")
+	AzureCmd.PersistentFlags().String("selector", "", "Select servers by tags (env=prod,team=payments) for download and check")
 
 	if err := viper.BindPFlag("account-name", AzureCmd.PersistentFlags().Lookup("account-name")); err != nil {
 		slog.Error("Erreur lors du binding du flag account-name", slog.Any("error", err))
@@ -113,4 +114,13 @@ func init() {
 	AzureCmd.AddCommand(DownloadCmd()) // Ajout de la sous-commande "download"
 	AzureCmd.AddCommand(ListCmd())
 	AzureCmd.AddCommand(PsqlCmd())
+	AzureCmd.AddCommand(DriftCmd())
+	AzureCmd.AddCommand(ShowCmd())
+	AzureCmd.AddCommand(SetCmd())
+	AzureCmd.AddCommand(CheckCmd())
+	AzureCmd.AddCommand(SecurityCmd())
+	AzureCmd.AddCommand(ForecastCmd())
+	AzureCmd.AddCommand(UpgradePlanCmd())
+	AzureCmd.AddCommand(RestoreCmd())
+	AzureCmd.AddCommand(TopologyCmd())
 }
@@ -0,0 +1,73 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+)
+
+// Fonction d'exécution de la commande check
+func runCheck(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetStringSlice("check-profile")
+	format, _ := cmd.Flags().GetString("format")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	var results []check.Result
+	for _, checkProfile := range profiles {
+		profileResults, err := pf.Check(checkProfile)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: Check: %w", err)
+		}
+		results = append(results, profileResults...)
+	}
+
+	// Règles d'audit évaluées sur le modèle commun aux fournisseurs
+	if len(rulesFiles) > 0 {
+		ruleResults, err := check.RunRules(rulesFiles, pf.Facts())
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: RunRules: %w", err)
+		}
+		results = append(results, ruleResults...)
+	}
+
+	err = check.PrintResults(format, results)
+	if err != nil {
+		return err
+	}
+
+	if check.Failed(results) {
+		return check.ErrFailed
+	}
+	return nil
+}
+
+func CheckCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the server parameters against embedded rule profiles",
+		Long: `Check the server parameters of the flexible server against embedded rule profiles (pgbadger, quellog, security, performance).
+The command exits with a non-zero status when a rule of severity error is not satisfied.
+The profiles are selected with --check-profile, because --profile is the AWS profile under rds.
+The top-level "cloud_helper check --profile" checks several providers at once.`,
+		Args: cobra.NoArgs,
+		RunE: selectorRunE(runCheck),
+	}
+	checkCmd.Flags().StringSlice("check-profile", []string{"pgbadger"}, "Profils de vérification (pgbadger, quellog, security, performance), équivalent de --profile de cloud_helper check")
+	checkCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown, junit)")
+	checkCmd.Flags().StringSlice("rules", nil, "Fichiers de règles d'audit YAML ou JSON à évaluer en plus des profils")
+	return checkCmd
+}
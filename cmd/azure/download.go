@@ -10,17 +10,34 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/robinportigliatti/cloud_helper/internal/azure" // Adapter selon ton chemin d'importation
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/events"
 )
 
 // DownloadCmd retourne une commande "download" avec des arguments
 func DownloadCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "download",
-		Short: "Download files from an Azure container within a time range",
-		RunE:  RunDownloadCmd,
+		Short: "Download files from an Azure container or the server activity log within a time range",
+		Long: `Download log files from an Azure Blob container (--type logs) or the Azure Activity Log of the flexible server
+(--type events: restarts, failovers, parameter changes, maintenance) between --begin-time and --end-time.
+Events are written as CSV and JSON under <directory>/events.
+With --selector, the events of every matching server are downloaded (--type events only).`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Les logs sont lus dans le conteneur de stockage, quel que soit le serveur
+			expression, _ := cmd.Flags().GetString("selector")
+			typeFlag, _ := cmd.Flags().GetString("type")
+			if expression != "" && typeFlag != "events" {
+				return fmt.Errorf("--selector n'est disponible qu'avec --type events")
+			}
+			return nil
+		},
+		RunE: selectorRunE(RunDownloadCmd),
 	}
 
 	// Ajout des flags avec des valeurs par défaut
+	cmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, events, all)")
+	cmd.Flags().String("directory", "./", "Répertoire de destination des événements")
 	cmd.Flags().String("container-name", "", "Azure Blob container name (obligatoire)")
 	cmd.Flags().String("begin-time", "", "Start time for filtering files (obligatoire, format: YYYY-MM-DD HH:MM:SS)")
 	cmd.Flags().String("end-time", "", "End time for filtering files (obligatoire, format: YYYY-MM-DD HH:MM:SS)")
@@ -48,14 +65,23 @@ func DownloadCmd() *cobra.Command {
 
 func RunDownloadCmd(cmd *cobra.Command, args []string) error {
 	// Récupération des arguments
+	typeFlag, _ := cmd.Flags().GetString("type")
+	dirFlag, _ := cmd.Flags().GetString("directory")
 	accountName := viper.GetString("account-name")
 	containerName := viper.GetString("container-name")
 	beginTimeStr := viper.GetString("begin-time")
 	endTimeStr := viper.GetString("end-time")
 
+	if typeFlag != "logs" && typeFlag != "events" && typeFlag != "all" {
+		return fmt.Errorf("type inconnu : %s (logs, events, all)", typeFlag)
+	}
+
 	// Vérification des paramètres obligatoires
-	if accountName == "" || containerName == "" || beginTimeStr == "" || endTimeStr == "" {
-		return fmt.Errorf("les paramètres --account-name, --container-name, --begin-time et --end-time sont obligatoires")
+	if typeFlag != "events" && (accountName == "" || containerName == "") {
+		return fmt.Errorf("les paramètres --account-name et --container-name sont obligatoires")
+	}
+	if beginTimeStr == "" || endTimeStr == "" {
+		return fmt.Errorf("les paramètres --begin-time et --end-time sont obligatoires")
 	}
 
 	// Conversion des dates
@@ -74,6 +100,16 @@ func RunDownloadCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("end-time doit être postérieur à begin-time")
 	}
 
+	if typeFlag == "events" || typeFlag == "all" {
+		err = downloadEvents(beginTime, endTime, dirFlag)
+		if err != nil {
+			return err
+		}
+		if typeFlag == "events" {
+			return nil
+		}
+	}
+
 	// Affichage des paramètres récupérés
 	slog.Info("Download parameters",
 		"Account Name", accountName,
@@ -98,3 +134,31 @@ func RunDownloadCmd(cmd *cobra.Command, args []string) error {
 	slog.Info("Téléchargement et traitement terminés avec succès!")
 	return nil
 }
+
+// downloadEvents écrit le journal d'activité du serveur sur la période au format CSV et JSON
+func downloadEvents(beginTime time.Time, endTime time.Time, directory string) error {
+	serverName := viper.GetString("server-name")
+	resourceGroup := viper.GetString("resource-group")
+	subscription := viper.GetString("subscription")
+	if serverName == "" {
+		return fmt.Errorf("le paramètre --server-name est obligatoire pour les événements")
+	}
+
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	serverEvents, err := pf.Events(beginTime, endTime)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Events: %w", err)
+	}
+
+	files, err := events.Write(directory, serverName, serverEvents)
+	if err != nil {
+		return fmt.Errorf("events: Write: %w", err)
+	}
+	slog.Info("Événements écrits", slog.Int("events", len(serverEvents)), slog.Any("files", files))
+	return nil
+}
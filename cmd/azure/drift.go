@@ -0,0 +1,64 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/drift"
+)
+
+// Fonction d'exécution de la commande drift
+func runDrift(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	var expected []drift.Expected
+	for _, configuration := range pf.GetConfigurations().Value {
+		if configuration.Properties.Value == "" {
+			continue
+		}
+		expected = append(expected, drift.Expected{Name: configuration.Name, Value: configuration.Properties.Value})
+	}
+
+	connection, err := pf.GetConnection(username, database, password)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: GetConnection: %w", err)
+	}
+
+	items, err := drift.Report(context.Background(), connection, expected)
+	if err != nil {
+		return fmt.Errorf("drift: Report: %w", err)
+	}
+
+	return drift.PrintItems(format, items)
+}
+
+func DriftCmd() *cobra.Command {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare the server configurations with the live pg_settings of the Flexible Server",
+		Args:  cobra.NoArgs,
+		RunE:  runDrift,
+	}
+	driftCmd.Flags().String("username", "", "Nom d'utilisateur PostgreSQL (par défaut : login administrateur)")
+	driftCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	driftCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	driftCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json)")
+	return driftCmd
+}
@@ -0,0 +1,56 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+)
+
+// Fonction d'exécution de la commande forecast
+func runForecast(cmd *cobra.Command, args []string) error {
+	storage, _ := cmd.Flags().GetBool("storage")
+	days, _ := cmd.Flags().GetInt("days")
+	horizon, _ := cmd.Flags().GetInt("horizon")
+	format, _ := cmd.Flags().GetString("format")
+
+	if !storage {
+		return fmt.Errorf("précisez la ressource à prévoir (--storage)")
+	}
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	storageForecast, err := pf.ForecastStorage(days, horizon)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: ForecastStorage: %w", err)
+	}
+
+	return forecast.Print(format, storageForecast)
+}
+
+func ForecastCmd() *cobra.Command {
+	forecastCmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Forecast storage growth of the flexible server",
+		Long: `Fit a linear trend to the used storage (Azure Monitor storage_used) over the last days and estimate the days until the disk is full.
+A storage size is recommended among the sizes offered by Azure, large enough for the growth until the horizon and for the peak IOPS.`,
+		Args: cobra.NoArgs,
+		RunE: runForecast,
+	}
+	forecastCmd.Flags().Bool("storage", false, "Prévoir l'occupation du stockage")
+	forecastCmd.Flags().Int("days", 30, "Nombre de jours d'historique utilisés pour la tendance")
+	forecastCmd.Flags().Int("horizon", 90, "Nombre de jours couverts par la recommandation")
+	forecastCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return forecastCmd
+}
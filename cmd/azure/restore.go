@@ -0,0 +1,171 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/restore"
+)
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	create, _ := cmd.Flags().GetBool("create")
+	wait, _ := cmd.Flags().GetBool("wait")
+	validateFile, _ := cmd.Flags().GetString("validate")
+	deleteClone, _ := cmd.Flags().GetBool("delete")
+	cloneName, _ := cmd.Flags().GetString("clone-name")
+	restoreTimeFlag, _ := cmd.Flags().GetString("restore-time")
+	extraTags, _ := cmd.Flags().GetStringToString("tag")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	reportFile, _ := cmd.Flags().GetString("report")
+	format, _ := cmd.Flags().GetString("format")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+	if serverName == "" || resourceGroup == "" {
+		return fmt.Errorf("--server-name et --resource-group sont obligatoires")
+	}
+
+	if !create && !wait && validateFile == "" && !deleteClone {
+		return fmt.Errorf("aucune étape demandée (--create, --wait, --validate, --delete)")
+	}
+	if cloneName == "" {
+		if !create {
+			return fmt.Errorf("--clone-name est obligatoire sans --create")
+		}
+		cloneName = restore.GenerateName(serverName, time.Now())
+	}
+
+	var restoreTime *time.Time
+	if restoreTimeFlag != "" {
+		parsed, err := time.Parse(time.RFC3339, restoreTimeFlag)
+		if err != nil {
+			return fmt.Errorf("--restore-time: %w", err)
+		}
+		restoreTime = &parsed
+	}
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	tags := restore.Tags(serverName, extraTags)
+	report := restore.NewReport("azure", serverName, cloneName, restoreTime, tags)
+
+	if create {
+		err = report.Run(restore.StepCreate, func() (string, error) {
+			if err := pf.RestoreServer(cloneName, restoreTime, tags); err != nil {
+				return "", fmt.Errorf("PostgresFlex: RestoreServer: %w", err)
+			}
+			return fmt.Sprintf("restauration de %s vers %s", serverName, cloneName), nil
+		})
+	} else {
+		report.Skip(restore.StepCreate, "--create absent")
+	}
+
+	if err == nil && wait {
+		err = report.Run(restore.StepWait, func() (string, error) {
+			return restore.Wait(func() (string, bool, error) { return pf.ServerState(cloneName) }, restore.PollInterval, timeout)
+		})
+	} else {
+		report.Skip(restore.StepWait, "--wait absent ou étape précédente en échec")
+	}
+
+	if err == nil && validateFile != "" {
+		err = report.Run(restore.StepValidate, func() (string, error) {
+			var clone postgresflex.PostgresFlex
+			if err := clone.Init(cloneName, resourceGroup, subscription); err != nil {
+				return "", fmt.Errorf("PostgresFlex: Init: %w", err)
+			}
+			connection, err := clone.GetConnection(username, database, password)
+			if err != nil {
+				return "", fmt.Errorf("PostgresFlex: GetConnection: %w", err)
+			}
+			return restore.Validate(context.Background(), connection, validateFile)
+		})
+	} else {
+		report.Skip(restore.StepValidate, "--validate absent ou étape précédente en échec")
+	}
+
+	// Le clone est supprimé même si la validation a échoué, mais pas si sa création ou l'attente a échoué
+	reason, deleteBlocked := report.DeleteBlocked()
+	switch {
+	case !deleteClone:
+		report.Skip(restore.StepDelete, "--delete absent")
+	case deleteBlocked:
+		slog.Warn("Suppression du clone ignorée", slog.String("clone", cloneName), slog.String("reason", reason))
+		report.Skip(restore.StepDelete, reason)
+	default:
+		deleteErr := report.Run(restore.StepDelete, func() (string, error) {
+			cloneTags, err := pf.ServerTags(cloneName)
+			if err != nil {
+				return "", fmt.Errorf("PostgresFlex: ServerTags: %w", err)
+			}
+			if err := restore.CheckDeletable(cloneName, serverName, cloneTags); err != nil {
+				return "", err
+			}
+			if err := pf.DeleteServer(cloneName); err != nil {
+				return "", fmt.Errorf("PostgresFlex: DeleteServer: %w", err)
+			}
+			return fmt.Sprintf("%s supprimé", cloneName), nil
+		})
+		if err == nil {
+			err = deleteErr
+		}
+	}
+
+	if reportFile == "" {
+		reportFile = cloneName + "-restore-test.md"
+	}
+	if writeErr := restore.Write(reportFile, report); writeErr != nil {
+		return fmt.Errorf("restore: Write: %w", writeErr)
+	}
+	slog.Info("Rapport de restauration écrit", slog.String("file", reportFile))
+
+	if printErr := restore.Print(format, report); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func RestoreCmd() *cobra.Command {
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the Flexible Server to a point in time into a new server, validate it and delete it",
+		Long: `Test that backups can be restored. Each step runs only when its flag is given:
+  --create    restore the server to a point in time (--restore-time, default now) into a new server
+              with a generated name and restore-test tags
+  --wait      wait until the new server is Ready, with progress output
+  --validate  run a SQL script on the new server
+  --delete    delete the new server; only a server tagged by --create can be deleted;
+              skipped when --wait failed or timed out
+The duration of each step and the restore time are written to a report (Markdown, or JSON with a .json extension).`,
+		Args: cobra.NoArgs,
+		RunE: runRestore,
+	}
+	restoreCmd.Flags().Bool("create", false, "Créer le serveur restauré")
+	restoreCmd.Flags().Bool("wait", false, "Attendre que le serveur restauré soit disponible")
+	restoreCmd.Flags().String("validate", "", "Script SQL de validation exécuté sur le serveur restauré")
+	restoreCmd.Flags().Bool("delete", false, "Supprimer le serveur restauré à la fin")
+	restoreCmd.Flags().String("clone-name", "", "Nom du serveur restauré (par défaut : <serveur>-rt-<date>)")
+	restoreCmd.Flags().String("restore-time", "", "Instant restauré, RFC3339 (par défaut : maintenant)")
+	restoreCmd.Flags().StringToString("tag", nil, "Étiquettes supplémentaires du serveur restauré (key=value)")
+	restoreCmd.Flags().Duration("timeout", 2*time.Hour, "Durée maximale d'attente du serveur restauré")
+	restoreCmd.Flags().String("report", "", "Fichier du rapport (par défaut : <clone>-restore-test.md)")
+	restoreCmd.Flags().String("username", "", "Nom d'utilisateur PostgreSQL (par défaut : login administrateur)")
+	restoreCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	restoreCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	restoreCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return restoreCmd
+}
@@ -0,0 +1,46 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// Fonction d'exécution de la commande security
+func runSecurity(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	findings, err := pf.SecurityFindings()
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: SecurityFindings: %w", err)
+	}
+
+	return security.PrintFindings(format, findings)
+}
+
+func SecurityCmd() *cobra.Command {
+	securityCmd := &cobra.Command{
+		Use:   "security",
+		Short: "Audit the security posture of the flexible server",
+		Long: `Audit the security posture of the flexible server: public network access and firewall rules open to the whole Internet,
+TLS enforcement (require_secure_transport) and password-only authentication.`,
+		Args: cobra.NoArgs,
+		RunE: runSecurity,
+	}
+	securityCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return securityCmd
+}
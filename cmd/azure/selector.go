@@ -0,0 +1,61 @@
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// selectorRunE exécute la commande pour chaque serveur dont les étiquettes (tags) satisfont --selector,
+// avec un résumé par serveur à la fin. Sans sélecteur, la commande est exécutée une seule fois
+func selectorRunE(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		expression, _ := cmd.Flags().GetString("selector")
+		if expression == "" {
+			return run(cmd, args)
+		}
+		if viper.GetString("server-name") != "" {
+			return fmt.Errorf("--server-name et --selector sont incompatibles")
+		}
+
+		sel, err := selector.Parse(expression)
+		if err != nil {
+			return fmt.Errorf("selector: Parse: %w", err)
+		}
+
+		var pf postgresflex.PostgresFlex
+		err = pf.Init("", viper.GetString("resource-group"), viper.GetString("subscription"))
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: Init: %w", err)
+		}
+		servers, err := pf.SelectServers(sel)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: SelectServers: %w", err)
+		}
+
+		// Sans --resource-group, les serveurs sont listés sur tout l'abonnement : chaque serveur est
+		// exécuté dans son propre groupe de ressources, et identifié par groupe/nom dans le résumé
+		resourceGroups := make(map[string]string, len(servers))
+		names := make([]string, 0, len(servers))
+		for _, server := range servers {
+			resourceGroup := server.ResourceGroup()
+			if resourceGroup == "" {
+				resourceGroup = viper.GetString("resource-group")
+			}
+			name := resourceGroup + "/" + server.Name
+			resourceGroups[name] = resourceGroup
+			names = append(names, name)
+		}
+
+		return selector.ForEach(names, func(name string) error {
+			_, serverName, _ := strings.Cut(name, "/")
+			viper.Set("resource-group", resourceGroups[name])
+			viper.Set("server-name", serverName)
+			return run(cmd, args)
+		})
+	}
+}
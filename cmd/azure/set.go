@@ -0,0 +1,102 @@
+package azure
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// Fonction d'exécution de la commande set
+func runSet(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	assignments, err := pgsettings.ParseAssignments(args)
+	if err != nil {
+		return err
+	}
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err = pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	changes, err := pf.PlanConfigurationChanges(assignments)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: PlanConfigurationChanges: %w", err)
+	}
+
+	if len(changes) == 0 {
+		slog.Info("Aucune modification : les paramètres sont déjà à la valeur demandée")
+		return nil
+	}
+
+	restart := false
+	columns := []string{"Name", "Current", "New", "RequiresRestart"}
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []string{change.Name, change.CurrentValue, change.Value, strconv.FormatBool(change.RequiresRestart)})
+		restart = restart || change.RequiresRestart
+	}
+	err = output.PrintRows("default", columns, rows)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		fmt.Print("Appliquer ces modifications ? (y/n): ")
+		var response string
+		_, err = fmt.Scanln(&response)
+		if err != nil {
+			return fmt.Errorf("scanln: %w", err)
+		}
+
+		if strings.ToLower(response) != "y" {
+			return nil
+		}
+	}
+
+	err = pf.ApplyConfigurationChanges(changes)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: ApplyConfigurationChanges: %w", err)
+	}
+
+	if restart {
+		// Les paramètres statiques ne sont pris en compte qu'au redémarrage du serveur
+		slog.Warn("Modifications appliquées, redémarrage nécessaire", slog.String("server", serverName))
+		fmt.Printf("az postgres flexible-server restart --resource-group %s --name %s\n", resourceGroup, serverName)
+	} else {
+		slog.Info("Modifications appliquées, aucun redémarrage nécessaire", slog.String("server", serverName))
+	}
+
+	return nil
+}
+
+func SetCmd() *cobra.Command {
+	setCmd := &cobra.Command{
+		Use:   "set name=value [name=value...]",
+		Short: "Modify server configurations of an Azure PostgreSQL Flexible Server",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSet,
+	}
+	setCmd.Flags().Bool("dry-run", false, "Afficher les modifications sans les appliquer")
+	setCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation avant d'appliquer les modifications")
+	return setCmd
+}
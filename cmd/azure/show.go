@@ -0,0 +1,89 @@
+package azure
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+var outputFormat string
+var force bool
+var nonDefault bool
+
+// Déclaration de la commande show
+var showCmd = &cobra.Command{
+	Use:   "show [settings...]",
+	Short: "Show server configurations of an Azure PostgreSQL Flexible Server",
+	Args:  cobra.MinimumNArgs(0),
+	RunE:  runShow,
+}
+
+// Fonction d'exécution de la commande show
+func runShow(cmd *cobra.Command, args []string) error {
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	if nonDefault {
+		// Uniquement les configurations dont la valeur diffère de la valeur par défaut
+		parameters := make([]output.Setting, 0)
+		for _, config := range pf.GetNonDefaultConfigurations() {
+			if len(args) > 0 && !slices.Contains(args, config.Name) {
+				continue
+			}
+			parameters = append(parameters, output.Setting{Name: config.Name, Value: config.Properties.Value})
+		}
+
+		return output.PrintSettings(outputFormat, parameters)
+	}
+
+	if len(args) == 0 {
+		if !force {
+			fmt.Print("Voulez-vous récupérer tous les paramètres ? (y/n): ")
+			var response string
+			_, err = fmt.Scanln(&response)
+			if err != nil {
+				return fmt.Errorf("scanln: %w", err)
+			}
+
+			if strings.ToLower(response) != "y" {
+				return nil
+			}
+		}
+		// Récupérer tous les paramètres
+		args, err = pf.GetAllConfigurationNames()
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: GetAllConfigurationNames: %w", err)
+		}
+	}
+
+	parameters := make([]output.Setting, 0)
+	for _, setting := range args {
+		value, err := pf.GetConfigurationValue(setting)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: GetConfigurationValue: %w", err)
+		}
+		parameters = append(parameters, output.Setting{Name: setting, Value: value})
+	}
+
+	return output.PrintSettings(outputFormat, parameters)
+}
+
+func ShowCmd() *cobra.Command {
+	showCmd.Flags().StringVarP(&outputFormat, "format", "F", "default", "Format de sortie (default, csv, json)")
+	showCmd.Flags().BoolVarP(&force, "force", "f", false, "Ne pas demander confirmation pour récupérer tous les paramètres")
+	showCmd.Flags().BoolVar(&nonDefault, "non-default", false, "Afficher uniquement les paramètres dont la valeur diffère de la valeur par défaut")
+	return showCmd
+}
@@ -0,0 +1,54 @@
+package azure
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+func runTopology(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	// Tous les serveurs du groupe de ressources (ou de l'abonnement) sont listés pour relier sources et réplicas
+	var pf postgresflex.PostgresFlex
+	err := pf.Init("", resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	serverTopology, err := pf.Topology()
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Topology: %w", err)
+	}
+
+	if serverName != "" {
+		serverTopology = serverTopology.Family(serverName)
+		if len(serverTopology.Nodes) == 0 {
+			return fmt.Errorf("serveur %s introuvable", serverName)
+		}
+	}
+
+	return topology.Print(format, serverTopology)
+}
+
+func TopologyCmd() *cobra.Command {
+	topologyCmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Show the replication topology of the flexible servers",
+		Long: `Show primaries and read replicas (sourceServerResourceId) of the resource group, or of the subscription without
+--resource-group, with cross-region replicas. Replicas show the maximum physical_replication_delay_in_seconds over the
+last hour, primaries the maximum physical_replication_delay_in_bytes. With --server-name, only the topology containing
+the server is shown. Output as a tree (default), Graphviz (dot), Mermaid (mermaid), csv, markdown or json.`,
+		Args: cobra.NoArgs,
+		RunE: runTopology,
+	}
+	topologyCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, dot, mermaid, csv, json, markdown)")
+	return topologyCmd
+}
@@ -0,0 +1,73 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+func runUpgradePlan(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	extensions, _ := cmd.Flags().GetBool("extensions")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	resourceGroup := viper.GetString("resource-group")
+	serverName := viper.GetString("server-name")
+	subscription := viper.GetString("subscription")
+
+	calendar, err := upgrade.LoadCalendar()
+	if err != nil {
+		return fmt.Errorf("upgrade: LoadCalendar: %w", err)
+	}
+
+	// Initialisation de la connexion à Azure PostgreSQL
+	var pf postgresflex.PostgresFlex
+	err = pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+
+	plan, err := pf.UpgradePlan(calendar)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: UpgradePlan: %w", err)
+	}
+
+	if extensions {
+		connection, err := pf.GetConnection(username, database, password)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: GetConnection: %w", err)
+		}
+		err = plan.CheckExtensions(context.Background(), calendar, connection, pf.SupportedExtensions())
+		if err != nil {
+			return fmt.Errorf("upgrade: CheckExtensions: %w", err)
+		}
+	}
+
+	return upgrade.Print(format, []upgrade.Plan{plan})
+}
+
+func UpgradePlanCmd() *cobra.Command {
+	upgradePlanCmd := &cobra.Command{
+		Use:   "upgrade-plan",
+		Short: "Plan the major version upgrade of the Flexible Server",
+		Long: `List the versions the server can be upgraded to in place (capabilities API, supportedVersionsToUpgrade of its tier),
+and flag a version close to its end of support from the embedded calendar.
+With --extensions, the installed extensions are checked against the latest target version: removed extensions,
+extensions missing from the allowed values of azure.extensions (read on the current server, Azure does not publish
+the list of another version) and extensions to update.`,
+		Args: cobra.NoArgs,
+		RunE: runUpgradePlan,
+	}
+	upgradePlanCmd.Flags().Bool("extensions", false, "Vérifier les extensions installées (connexion au serveur)")
+	upgradePlanCmd.Flags().String("username", "", "Nom d'utilisateur PostgreSQL (par défaut : login administrateur)")
+	upgradePlanCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	upgradePlanCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	upgradePlanCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, markdown, json)")
+	return upgradePlanCmd
+}
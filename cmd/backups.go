@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/backup"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+)
+
+// Déclaration de la commande backups
+var backupsCmd = &cobra.Command{
+	Use:   "backups",
+	Short: "Rapport de conformité des sauvegardes et de la restauration à un instant donné",
+	Long: `Liste les sauvegardes automatiques et manuelles de chaque instance, calcule la fenêtre de restauration
+et la compare à la politique (rétention minimale, PITR activé, copie dans une autre région).
+Les instances de tous les fournisseurs demandés sont regroupées dans un même tableau.
+Les copies manuelles de snapshots RDS ne sont pas visibles depuis la région de l'instance : sans réplication
+des sauvegardes automatiques (ou réplica Aurora dans une autre région), la copie est inconnue (unknown).
+
+Exemples d'utilisation:
+  # Instances RDS du profil et instances Cloud SQL du projet
+  cloud_helper backups --provider rds,gcp --profile prod --project-id my-project
+
+  # Serveur Azure, avec copie géo-redondante exigée
+  cloud_helper backups --provider azure --resource-group rg --server-name pg01 --require-cross-region`,
+	Args: cobra.NoArgs,
+	RunE: runBackups,
+}
+
+// Fonction d'exécution de la commande backups
+func runBackups(cmd *cobra.Command, args []string) error {
+	providers, _ := cmd.Flags().GetStringSlice("provider")
+	format, _ := cmd.Flags().GetString("format")
+	snapshots, _ := cmd.Flags().GetBool("snapshots")
+
+	var policy backup.Policy
+	policy.MinRetentionDays, _ = cmd.Flags().GetInt("min-retention")
+	policy.RequirePITR, _ = cmd.Flags().GetBool("require-pitr")
+	policy.RequireCrossRegion, _ = cmd.Flags().GetBool("require-cross-region")
+
+	var reports []backup.Report
+	for _, provider := range providers {
+		var providerReports []backup.Report
+		var err error
+		switch provider {
+		case "rds":
+			providerReports, err = rdsBackupReports(cmd)
+		case "gcp":
+			providerReports, err = gcpBackupReports(cmd)
+		case "azure":
+			providerReports, err = azureBackupReports(cmd)
+		default:
+			return fmt.Errorf("fournisseur %q non géré (rds, gcp, azure)", provider)
+		}
+		if err != nil {
+			return err
+		}
+		reports = append(reports, providerReports...)
+	}
+
+	for i := range reports {
+		policy.Evaluate(&reports[i])
+	}
+	return backup.Print(format, reports, snapshots)
+}
+
+// rdsBackupReports retourne les rapports des instances RDS de la région du profil
+func rdsBackupReports(cmd *cobra.Command) ([]backup.Report, error) {
+	profile, _ := cmd.Flags().GetString("profile")
+	dbInstanceIdentifier, _ := cmd.Flags().GetString("db-instance-identifier")
+
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return nil, fmt.Errorf("RDS: Init: %w", err)
+	}
+
+	reports, err := rdsInstance.BackupReports()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: BackupReports: %w", err)
+	}
+	return reports, nil
+}
+
+// gcpBackupReports retourne les rapports de l'instance Cloud SQL, ou de toutes les instances du projet
+func gcpBackupReports(cmd *cobra.Command) ([]backup.Report, error) {
+	projectID, _ := cmd.Flags().GetString("project-id")
+	instanceName, _ := cmd.Flags().GetString("instance-name")
+	credentialsFile, _ := cmd.Flags().GetString("credentials-file")
+
+	instanceNames := []string{instanceName}
+	if instanceName == "" {
+		var gcp gcpPkg.GCP
+		err := gcp.Init("", projectID, credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("GCP: Init: %w", err)
+		}
+		instances, err := gcp.ListInstances()
+		if err != nil {
+			return nil, fmt.Errorf("GCP: ListInstances: %w", err)
+		}
+		instanceNames = instanceNames[:0]
+		for _, instance := range instances {
+			instanceNames = append(instanceNames, instance.Name)
+		}
+	}
+
+	var reports []backup.Report
+	for _, name := range instanceNames {
+		var gcp gcpPkg.GCP
+		err := gcp.Init(name, projectID, credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("GCP: Init %s: %w", name, err)
+		}
+		report, err := gcp.BackupReport()
+		if err != nil {
+			return nil, fmt.Errorf("GCP: BackupReport %s: %w", name, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// azureBackupReports retourne les rapports du serveur Flexible Server, ou de tous les serveurs du groupe de ressources
+func azureBackupReports(cmd *cobra.Command) ([]backup.Report, error) {
+	resourceGroup, _ := cmd.Flags().GetString("resource-group")
+	serverName, _ := cmd.Flags().GetString("server-name")
+	subscription, _ := cmd.Flags().GetString("subscription")
+
+	// Sans --server-name, tous les serveurs, chacun dans son groupe de ressources
+	servers := []postgresflex.Server{{Name: serverName}}
+	if serverName == "" {
+		var pf postgresflex.PostgresFlex
+		err := pf.Init("", resourceGroup, subscription)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Init: %w", err)
+		}
+		servers, err = pf.ListServers()
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: ListServers: %w", err)
+		}
+	}
+
+	var reports []backup.Report
+	for _, server := range servers {
+		serverResourceGroup := resourceGroup
+		if server.ID != "" {
+			serverResourceGroup = server.ResourceGroup()
+		}
+
+		var pf postgresflex.PostgresFlex
+		err := pf.Init(server.Name, serverResourceGroup, subscription)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Init %s: %w", server.Name, err)
+		}
+		report, err := pf.BackupReport()
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: BackupReport %s: %w", server.Name, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func init() {
+	backupsCmd.Flags().StringSlice("provider", []string{"rds"}, "Fournisseurs à interroger (rds, gcp, azure)")
+	backupsCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	backupsCmd.Flags().Bool("snapshots", false, "Lister les sauvegardes de chaque instance")
+
+	// Politique de sauvegarde
+	backupsCmd.Flags().Int("min-retention", 7, "Rétention minimale des sauvegardes, en jours")
+	backupsCmd.Flags().Bool("require-pitr", true, "Exiger la restauration à un instant donné (PITR)")
+	backupsCmd.Flags().Bool("require-cross-region", false, "Exiger une copie des sauvegardes dans une autre région")
+
+	// Sélection des instances par fournisseur
+	backupsCmd.Flags().String("profile", "default", "Profil AWS à utiliser")
+	backupsCmd.Flags().String("db-instance-identifier", "", "Identifiant de l'instance RDS (par défaut : toutes les instances)")
+	backupsCmd.Flags().String("project-id", "", "Google Cloud Project ID")
+	backupsCmd.Flags().String("instance-name", "", "Cloud SQL Instance Name (par défaut : toutes les instances du projet)")
+	backupsCmd.Flags().String("credentials-file", "", "Path to credentials JSON file")
+	backupsCmd.Flags().String("resource-group", "", "Azure Resource Group")
+	backupsCmd.Flags().String("server-name", "", "PostgreSQL Flexible Server Name (par défaut : tous les serveurs)")
+	backupsCmd.Flags().String("subscription", "", "Azure Subscription ID")
+
+	// Ajout de la commande au CLI principal
+	rootCmd.AddCommand(backupsCmd)
+}
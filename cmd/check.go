@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	postgresflex "github.com/robinportigliatti/cloud_helper/internal/azure/postgresflex"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	ovhPkg "github.com/robinportigliatti/cloud_helper/internal/ovh"
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// checkTarget regroupe l'instance d'un fournisseur à vérifier et l'accès à ses faits pour les règles d'audit
+type checkTarget struct {
+	instance string
+	check    func(profile string) ([]check.Result, error)
+	facts    func() (rules.Instance, error)
+}
+
+// Déclaration de la commande check
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Vérification de la configuration des instances avec les profils de règles embarqués",
+	Long: `Vérifie la configuration des instances de chaque fournisseur demandé avec les profils de règles embarqués
+(pgbadger, quellog, security, performance). Les résultats de tous les fournisseurs sont regroupés dans un même rapport.
+La commande se termine avec un code non nul lorsqu'une règle de sévérité error n'est pas respectée.
+
+Ici, --profile désigne les profils de vérification et le profil AWS se passe avec --aws-profile.
+Les sous-commandes check de chaque fournisseur (rds check, gcp check...) utilisent --check-profile,
+car --profile y désigne déjà le profil AWS.
+
+Exemples d'utilisation:
+  # Instance RDS et instance Cloud SQL, profils pgbadger et security
+  cloud_helper check --provider rds,gcp --profile pgbadger,security --aws-profile prod --db-instance-identifier db1 --project-id my-project --instance-name pg1
+
+  # Cluster OVHcloud, rapport JUnit
+  cloud_helper check --provider ovh --service-name my-project --cluster-id my-cluster -F junit > check.xml`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+// Fonction d'exécution de la commande check
+func runCheck(cmd *cobra.Command, args []string) error {
+	providers, _ := cmd.Flags().GetStringSlice("provider")
+	profiles, _ := cmd.Flags().GetStringSlice("profile")
+	format, _ := cmd.Flags().GetString("format")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
+
+	var results []check.Result
+	for _, provider := range providers {
+		var target checkTarget
+		var err error
+		switch provider {
+		case "rds":
+			target, err = rdsCheckTarget(cmd)
+		case "gcp":
+			target, err = gcpCheckTarget(cmd)
+		case "azure":
+			target, err = azureCheckTarget(cmd)
+		case "ovh":
+			target, err = ovhCheckTarget(cmd)
+		default:
+			return fmt.Errorf("fournisseur %q non géré (rds, gcp, azure, ovh)", provider)
+		}
+		if err != nil {
+			return err
+		}
+
+		var targetResults []check.Result
+		for _, checkProfile := range profiles {
+			profileResults, err := target.check(checkProfile)
+			if err != nil {
+				return fmt.Errorf("%s: Check: %w", provider, err)
+			}
+			targetResults = append(targetResults, profileResults...)
+		}
+
+		// Règles d'audit évaluées sur le modèle commun aux fournisseurs
+		if len(rulesFiles) > 0 {
+			facts, err := target.facts()
+			if err != nil {
+				return fmt.Errorf("%s: Facts: %w", provider, err)
+			}
+			ruleResults, err := check.RunRules(rulesFiles, facts)
+			if err != nil {
+				return fmt.Errorf("%s: RunRules: %w", provider, err)
+			}
+			targetResults = append(targetResults, ruleResults...)
+		}
+
+		for i := range targetResults {
+			targetResults[i].Instance = provider + "/" + target.instance
+		}
+		results = append(results, targetResults...)
+	}
+
+	err := check.PrintResults(format, results)
+	if err != nil {
+		return err
+	}
+
+	if check.Failed(results) {
+		return check.ErrFailed
+	}
+	return nil
+}
+
+// rdsCheckTarget initialise l'instance RDS à vérifier
+func rdsCheckTarget(cmd *cobra.Command) (checkTarget, error) {
+	profile, _ := cmd.Flags().GetString("aws-profile")
+	dbInstanceIdentifier, _ := cmd.Flags().GetString("db-instance-identifier")
+	if dbInstanceIdentifier == "" {
+		return checkTarget{}, fmt.Errorf("le flag --db-instance-identifier est obligatoire avec le fournisseur rds")
+	}
+
+	var rdsInstance rds.RDS
+	err := rdsInstance.Init(dbInstanceIdentifier, profile)
+	if err != nil {
+		return checkTarget{}, fmt.Errorf("RDS: Init: %w", err)
+	}
+	facts := func() (rules.Instance, error) { return rdsInstance.Facts(), nil }
+	return checkTarget{instance: dbInstanceIdentifier, check: rdsInstance.Check, facts: facts}, nil
+}
+
+// gcpCheckTarget initialise l'instance Cloud SQL à vérifier
+func gcpCheckTarget(cmd *cobra.Command) (checkTarget, error) {
+	projectID, _ := cmd.Flags().GetString("project-id")
+	instanceName, _ := cmd.Flags().GetString("instance-name")
+	credentialsFile, _ := cmd.Flags().GetString("credentials-file")
+	if instanceName == "" {
+		return checkTarget{}, fmt.Errorf("le flag --instance-name est obligatoire avec le fournisseur gcp")
+	}
+
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return checkTarget{}, fmt.Errorf("GCP: Init: %w", err)
+	}
+	facts := func() (rules.Instance, error) { return gcp.Facts(), nil }
+	return checkTarget{instance: instanceName, check: gcp.Check, facts: facts}, nil
+}
+
+// azureCheckTarget initialise le serveur Flexible Server à vérifier
+func azureCheckTarget(cmd *cobra.Command) (checkTarget, error) {
+	resourceGroup, _ := cmd.Flags().GetString("resource-group")
+	serverName, _ := cmd.Flags().GetString("server-name")
+	subscription, _ := cmd.Flags().GetString("subscription")
+	if serverName == "" {
+		return checkTarget{}, fmt.Errorf("le flag --server-name est obligatoire avec le fournisseur azure")
+	}
+
+	var pf postgresflex.PostgresFlex
+	err := pf.Init(serverName, resourceGroup, subscription)
+	if err != nil {
+		return checkTarget{}, fmt.Errorf("PostgresFlex: Init: %w", err)
+	}
+	facts := func() (rules.Instance, error) { return pf.Facts(), nil }
+	return checkTarget{instance: serverName, check: pf.Check, facts: facts}, nil
+}
+
+// ovhCheckTarget initialise le cluster OVHcloud à vérifier
+func ovhCheckTarget(cmd *cobra.Command) (checkTarget, error) {
+	serviceName, _ := cmd.Flags().GetString("service-name")
+	clusterID, _ := cmd.Flags().GetString("cluster-id")
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	if serviceName == "" || clusterID == "" {
+		return checkTarget{}, fmt.Errorf("les flags --service-name et --cluster-id sont obligatoires avec le fournisseur ovh")
+	}
+
+	var ovhClient ovhPkg.OVHClient
+	err := ovhClient.Init(serviceName, clusterID, endpoint)
+	if err != nil {
+		return checkTarget{}, fmt.Errorf("OVH: Init: %w", err)
+	}
+	return checkTarget{instance: clusterID, check: ovhClient.Check, facts: ovhClient.Facts}, nil
+}
+
+func init() {
+	checkCmd.Flags().StringSlice("provider", []string{"rds"}, "Fournisseurs à interroger (rds, gcp, azure, ovh)")
+	checkCmd.Flags().StringSlice("profile", []string{"pgbadger"}, "Profils de vérification (pgbadger, quellog, security, performance)")
+	checkCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown, junit)")
+	checkCmd.Flags().StringSlice("rules", nil, "Fichiers de règles d'audit YAML ou JSON à évaluer en plus des profils")
+
+	// Sélection des instances par fournisseur
+	checkCmd.Flags().String("aws-profile", "default", "Profil AWS à utiliser")
+	checkCmd.Flags().String("db-instance-identifier", "", "Identifiant de l'instance RDS")
+	checkCmd.Flags().String("project-id", "", "Google Cloud Project ID")
+	checkCmd.Flags().String("instance-name", "", "Cloud SQL Instance Name")
+	checkCmd.Flags().String("credentials-file", "", "Path to credentials JSON file")
+	checkCmd.Flags().String("resource-group", "", "Azure Resource Group")
+	checkCmd.Flags().String("server-name", "", "PostgreSQL Flexible Server Name")
+	checkCmd.Flags().String("subscription", "", "Azure Subscription ID")
+	checkCmd.Flags().String("service-name", "", "OVHcloud Public Cloud Service Name (Project ID)")
+	checkCmd.Flags().String("cluster-id", "", "OVHcloud Database Cluster ID")
+	checkCmd.Flags().String("endpoint", "ovh-eu", "OVHcloud API endpoint (ovh-eu, ovh-ca, ovh-us)")
+
+	// Ajout de la commande au CLI principal
+	rootCmd.AddCommand(checkCmd)
+}
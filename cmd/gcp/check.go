@@ -0,0 +1,73 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+)
+
+// Fonction d'exécution de la commande check
+func runCheck(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetStringSlice("check-profile")
+	format, _ := cmd.Flags().GetString("format")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	var results []check.Result
+	for _, checkProfile := range profiles {
+		profileResults, err := gcp.Check(checkProfile)
+		if err != nil {
+			return fmt.Errorf("GCP: Check: %w", err)
+		}
+		results = append(results, profileResults...)
+	}
+
+	// Règles d'audit évaluées sur le modèle commun aux fournisseurs
+	if len(rulesFiles) > 0 {
+		ruleResults, err := check.RunRules(rulesFiles, gcp.Facts())
+		if err != nil {
+			return fmt.Errorf("GCP: RunRules: %w", err)
+		}
+		results = append(results, ruleResults...)
+	}
+
+	err = check.PrintResults(format, results)
+	if err != nil {
+		return err
+	}
+
+	if check.Failed(results) {
+		return check.ErrFailed
+	}
+	return nil
+}
+
+func CheckCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the database flags against embedded rule profiles",
+		Long: `Check the database flags of the Cloud SQL instance against embedded rule profiles (pgbadger, quellog, security, performance).
+The command exits with a non-zero status when a rule of severity error is not satisfied.
+The profiles are selected with --check-profile, because --profile is the AWS profile under rds.
+The top-level "cloud_helper check --profile" checks several providers at once.`,
+		Args: cobra.NoArgs,
+		RunE: selectorRunE(runCheck),
+	}
+	checkCmd.Flags().StringSlice("check-profile", []string{"pgbadger"}, "Profils de vérification (pgbadger, quellog, security, performance), équivalent de --profile de cloud_helper check")
+	checkCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown, junit)")
+	checkCmd.Flags().StringSlice("rules", nil, "Fichiers de règles d'audit YAML ou JSON à évaluer en plus des profils")
+	return checkCmd
+}
@@ -0,0 +1,178 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/restore"
+)
+
+func runClone(cmd *cobra.Command, args []string) error {
+	create, _ := cmd.Flags().GetBool("create")
+	wait, _ := cmd.Flags().GetBool("wait")
+	validateFile, _ := cmd.Flags().GetString("validate")
+	deleteClone, _ := cmd.Flags().GetBool("delete")
+	cloneName, _ := cmd.Flags().GetString("clone-name")
+	restoreTimeFlag, _ := cmd.Flags().GetString("restore-time")
+	extraLabels, _ := cmd.Flags().GetStringToString("label")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	reportFile, _ := cmd.Flags().GetString("report")
+	format, _ := cmd.Flags().GetString("format")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+	if instanceName == "" {
+		return fmt.Errorf("--instance-name est obligatoire")
+	}
+
+	if !create && !wait && validateFile == "" && !deleteClone {
+		return fmt.Errorf("aucune étape demandée (--create, --wait, --validate, --delete)")
+	}
+	if cloneName == "" {
+		if !create {
+			return fmt.Errorf("--clone-name est obligatoire sans --create")
+		}
+		cloneName = restore.GenerateName(instanceName, time.Now())
+	}
+
+	var restoreTime *time.Time
+	if restoreTimeFlag != "" {
+		parsed, err := time.Parse(time.RFC3339, restoreTimeFlag)
+		if err != nil {
+			return fmt.Errorf("--restore-time: %w", err)
+		}
+		restoreTime = &parsed
+	}
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	labels := restore.Tags(instanceName, extraLabels)
+	report := restore.NewReport("gcp", instanceName, cloneName, restoreTime, labels)
+
+	if create {
+		err = report.Run(restore.StepCreate, func() (string, error) {
+			if err := gcp.CloneInstance(cloneName, restoreTime); err != nil {
+				return "", fmt.Errorf("GCP: CloneInstance: %w", err)
+			}
+			return fmt.Sprintf("clonage de %s vers %s", instanceName, cloneName), nil
+		})
+	} else {
+		report.Skip(restore.StepCreate, "--create absent")
+	}
+
+	// Le clone n'accepte d'étiquettes qu'une fois disponible : elles sont posées à la fin de l'attente
+	if err == nil && wait {
+		err = report.Run(restore.StepWait, func() (string, error) {
+			detail, err := restore.Wait(func() (string, bool, error) { return gcp.InstanceState(cloneName) }, restore.PollInterval, timeout)
+			if err != nil {
+				return detail, err
+			}
+			if err := gcp.LabelClone(cloneName, labels); err != nil {
+				return "", fmt.Errorf("GCP: LabelClone: %w", err)
+			}
+			return detail, nil
+		})
+	} else {
+		report.Skip(restore.StepWait, "--wait absent ou étape précédente en échec")
+	}
+
+	if err == nil && validateFile != "" {
+		err = report.Run(restore.StepValidate, func() (string, error) {
+			var clone gcpPkg.GCP
+			if err := clone.Init(cloneName, projectID, credentialsFile); err != nil {
+				return "", fmt.Errorf("GCP: Init: %w", err)
+			}
+			connection, err := clone.GetConnection(username, database, password)
+			if err != nil {
+				return "", fmt.Errorf("GCP: GetConnection: %w", err)
+			}
+			return restore.Validate(context.Background(), connection, validateFile)
+		})
+	} else {
+		report.Skip(restore.StepValidate, "--validate absent ou étape précédente en échec")
+	}
+
+	// Le clone est supprimé même si la validation a échoué, mais pas si sa création ou l'attente a échoué
+	reason, deleteBlocked := report.DeleteBlocked()
+	switch {
+	case !deleteClone:
+		report.Skip(restore.StepDelete, "--delete absent")
+	case deleteBlocked:
+		slog.Warn("Suppression du clone ignorée", slog.String("clone", cloneName), slog.String("reason", reason))
+		report.Skip(restore.StepDelete, reason)
+	default:
+		deleteErr := report.Run(restore.StepDelete, func() (string, error) {
+			cloneLabels, err := gcp.InstanceLabels(cloneName)
+			if err != nil {
+				return "", fmt.Errorf("GCP: InstanceLabels: %w", err)
+			}
+			if err := restore.CheckDeletable(cloneName, instanceName, cloneLabels); err != nil {
+				return "", err
+			}
+			if err := gcp.DeleteInstance(cloneName); err != nil {
+				return "", fmt.Errorf("GCP: DeleteInstance: %w", err)
+			}
+			return fmt.Sprintf("%s supprimée", cloneName), nil
+		})
+		if err == nil {
+			err = deleteErr
+		}
+	}
+
+	if reportFile == "" {
+		reportFile = cloneName + "-restore-test.md"
+	}
+	if writeErr := restore.Write(reportFile, report); writeErr != nil {
+		return fmt.Errorf("restore: Write: %w", writeErr)
+	}
+	slog.Info("Rapport de restauration écrit", slog.String("file", reportFile))
+
+	if printErr := restore.Print(format, report); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func CloneCmd() *cobra.Command {
+	cloneCmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Clone the Cloud SQL instance to a point in time, validate the clone and delete it",
+		Long: `Test that backups can be restored. Each step runs only when its flag is given:
+  --create    clone the instance to a point in time (--restore-time, default current state) with a generated name
+  --wait      wait until the clone is RUNNABLE, with progress output, then label it and disable its deletion protection
+  --validate  run a SQL script on the clone
+  --delete    delete the clone; only a clone labelled by --wait can be deleted;
+              skipped when --wait failed or timed out
+The duration of each step and the restore time are written to a report (Markdown, or JSON with a .json extension).`,
+		Args: cobra.NoArgs,
+		RunE: runClone,
+	}
+	cloneCmd.Flags().Bool("create", false, "Créer le clone")
+	cloneCmd.Flags().Bool("wait", false, "Attendre que le clone soit disponible")
+	cloneCmd.Flags().String("validate", "", "Script SQL de validation exécuté sur le clone")
+	cloneCmd.Flags().Bool("delete", false, "Supprimer le clone à la fin")
+	cloneCmd.Flags().String("clone-name", "", "Nom du clone (par défaut : <instance>-rt-<date>)")
+	cloneCmd.Flags().String("restore-time", "", "Instant restauré, RFC3339 (par défaut : état courant)")
+	cloneCmd.Flags().StringToString("label", nil, "Étiquettes supplémentaires du clone (key=value)")
+	cloneCmd.Flags().Duration("timeout", 2*time.Hour, "Durée maximale d'attente du clone")
+	cloneCmd.Flags().String("report", "", "Fichier du rapport (par défaut : <clone>-restore-test.md)")
+	cloneCmd.Flags().String("username", "postgres", "Nom d'utilisateur PostgreSQL")
+	cloneCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	cloneCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	cloneCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return cloneCmd
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/events"
 	"github.com/robinportigliatti/cloud_helper/internal/gcp"
 )
 
@@ -41,11 +42,20 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		// TODO: Implement metrics download for GCP
 		slog.Info("Metrics download for GCP is not yet implemented")
 		return fmt.Errorf("metrics download for GCP is not yet implemented")
+	case "events":
+		err = downloadEvents(&gcpInstance, instanceName, startFlag, endFlag, dirFlag)
+		if err != nil {
+			return err
+		}
 	case "all":
 		err = gcpInstance.DownloadLogs(startFlag, dirFlag, endFlag)
 		if err != nil {
 			return fmt.Errorf("GCP: DownloadLogs: %w", err)
 		}
+		err = downloadEvents(&gcpInstance, instanceName, startFlag, endFlag, dirFlag)
+		if err != nil {
+			return err
+		}
 		// TODO: Implement metrics download for GCP
 		slog.Info("Metrics download for GCP is not yet implemented (only logs downloaded)")
 	}
@@ -60,14 +70,40 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// downloadEvents écrit les opérations Cloud SQL de l'instance sur la période au format CSV et JSON
+func downloadEvents(gcpInstance *gcp.GCP, instanceName string, startFlag string, endFlag string, directory string) error {
+	start, err := time.Parse("2006/01/02 15:04:00", startFlag)
+	if err != nil {
+		return fmt.Errorf("--start: %w", err)
+	}
+	end, err := time.Parse("2006/01/02 15:04:00", endFlag)
+	if err != nil {
+		return fmt.Errorf("--end: %w", err)
+	}
+
+	instanceEvents, err := gcpInstance.Events(start, end)
+	if err != nil {
+		return fmt.Errorf("GCP: Events: %w", err)
+	}
+
+	files, err := events.Write(directory, instanceName, instanceEvents)
+	if err != nil {
+		return fmt.Errorf("events: Write: %w", err)
+	}
+	slog.Info("Événements écrits", slog.Int("events", len(instanceEvents)), slog.Any("files", files))
+	return nil
+}
+
 func DownloadCmd() *cobra.Command {
 	downloadCmd := &cobra.Command{
 		Use:   "download",
-		Short: "Download GCP Cloud SQL logs or metrics",
+		Short: "Download GCP Cloud SQL logs, metrics or operations",
+		Long: `Download Cloud SQL logs or operations (restarts, failovers, flag changes, maintenance) between --start and --end.
+Operations are written as CSV and JSON under <directory>/events.`,
 		Args:  cobra.MinimumNArgs(0),
-		RunE:  runDownload,
+		RunE:  selectorRunE(runDownload),
 	}
-	downloadCmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, metrics, all)")
+	downloadCmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, metrics, events, all)")
 	downloadCmd.Flags().String("start", time.Now().AddDate(0, 0, -1).Format("2006/01/02 15:04:00"), "Date de début")
 	downloadCmd.Flags().String("end", time.Now().Format("2006/01/02 15:04:00"), "Date de fin")
 	downloadCmd.Flags().String("directory", "./", "Répertoire de destination")
@@ -0,0 +1,61 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/drift"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+)
+
+// Fonction d'exécution de la commande drift
+func runDrift(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	var expected []drift.Expected
+	for _, flag := range gcp.GetInstance().Settings.DatabaseFlags {
+		expected = append(expected, drift.Expected{Name: flag.Name, Value: flag.Value})
+	}
+
+	connection, err := gcp.GetConnection(username, database, password)
+	if err != nil {
+		return fmt.Errorf("GCP: GetConnection: %w", err)
+	}
+
+	items, err := drift.Report(context.Background(), connection, expected)
+	if err != nil {
+		return fmt.Errorf("drift: Report: %w", err)
+	}
+
+	return drift.PrintItems(format, items)
+}
+
+func DriftCmd() *cobra.Command {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare the database flags with the live pg_settings of the Cloud SQL instance",
+		Args:  cobra.NoArgs,
+		RunE:  runDrift,
+	}
+	driftCmd.Flags().String("username", "postgres", "Nom d'utilisateur PostgreSQL")
+	driftCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	driftCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	driftCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json)")
+	return driftCmd
+}
@@ -0,0 +1,57 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+)
+
+// Fonction d'exécution de la commande forecast
+func runForecast(cmd *cobra.Command, args []string) error {
+	storage, _ := cmd.Flags().GetBool("storage")
+	days, _ := cmd.Flags().GetInt("days")
+	horizon, _ := cmd.Flags().GetInt("horizon")
+	format, _ := cmd.Flags().GetString("format")
+
+	if !storage {
+		return fmt.Errorf("précisez la ressource à prévoir (--storage)")
+	}
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	storageForecast, err := gcp.ForecastStorage(days, horizon)
+	if err != nil {
+		return fmt.Errorf("GCP: ForecastStorage: %w", err)
+	}
+
+	return forecast.Print(format, storageForecast)
+}
+
+func ForecastCmd() *cobra.Command {
+	forecastCmd := &cobra.Command{
+		Use:   "forecast",
+		Short: "Forecast storage growth of the Cloud SQL instance",
+		Long: `Fit a linear trend to the used disk space (Cloud Monitoring database/disk/bytes_used) over the last days and estimate
+the days until the disk is full and until the automatic storage increase limit (storageAutoResizeLimit) is reached.
+A disk size is recommended to absorb the growth until the horizon.`,
+		Args: cobra.NoArgs,
+		RunE: runForecast,
+	}
+	forecastCmd.Flags().Bool("storage", false, "Prévoir l'occupation du stockage")
+	forecastCmd.Flags().Int("days", 30, "Nombre de jours d'historique utilisés pour la tendance")
+	forecastCmd.Flags().Int("horizon", 90, "Nombre de jours couverts par la recommandation")
+	forecastCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return forecastCmd
+}
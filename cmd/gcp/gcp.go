@@ -86,6 +86,7 @@ func init() {
 	GcpCmd.PersistentFlags().StringVar(&instanceName, "instance-name", "", "Cloud SQL Instance Name")
 	GcpCmd.PersistentFlags().StringVar(&credentialsFile, "credentials-file", "", "Path to credentials JSON file")
 	GcpCmd.PersistentFlags().BoolVar(&list, "list", false, "List all Cloud SQL instances")
+	GcpCmd.PersistentFlags().String("selector", "", "Select instances by user labels (env=prod,team=payments) for download and check")
 
 	err := viper.BindPFlag("project-id", GcpCmd.PersistentFlags().Lookup("project-id"))
 	if err != nil {
@@ -108,4 +109,14 @@ func init() {
 	GcpCmd.AddCommand(ListCmd())
 	GcpCmd.AddCommand(PsqlCmd())
 	GcpCmd.AddCommand(DownloadCmd())
+	GcpCmd.AddCommand(DriftCmd())
+	GcpCmd.AddCommand(ShowCmd())
+	GcpCmd.AddCommand(SetCmd())
+	GcpCmd.AddCommand(CheckCmd())
+	GcpCmd.AddCommand(SecurityCmd())
+	GcpCmd.AddCommand(ForecastCmd())
+	GcpCmd.AddCommand(RecommendCmd())
+	GcpCmd.AddCommand(UpgradePlanCmd())
+	GcpCmd.AddCommand(CloneCmd())
+	GcpCmd.AddCommand(TopologyCmd())
 }
@@ -0,0 +1,57 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/sizing"
+)
+
+// Fonction d'exécution de la commande recommend
+func runRecommend(cmd *cobra.Command, args []string) error {
+	sizingFlag, _ := cmd.Flags().GetBool("sizing")
+	window, _ := cmd.Flags().GetInt("window")
+	format, _ := cmd.Flags().GetString("format")
+
+	if !sizingFlag {
+		return fmt.Errorf("précisez la recommandation souhaitée (--sizing)")
+	}
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	recommendation, err := gcp.RecommendSizing(window)
+	if err != nil {
+		return fmt.Errorf("GCP: RecommendSizing: %w", err)
+	}
+
+	return sizing.Print(format, recommendation)
+}
+
+func RecommendCmd() *cobra.Command {
+	recommendCmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Recommend a right-sized machine type",
+		Long: `Analyse p95 and max of CPU utilization, memory usage, PostgreSQL backends and disk read/write operations (Cloud Monitoring)
+over the window, then suggest a machine type with the reasons and the resulting default max_connections:
+custom types (db-custom-<vCPU>-<memory>) get half or twice the vCPUs with the same memory per vCPU, predefined types
+(db-n1-standard-*, db-n1-highmem-*, Enterprise Plus db-perf-optimized-N-*) get the previous or next size of their series.
+Shared-core (db-f1-micro, db-g1-small) and memory-optimized types are not handled.`,
+		Args: cobra.NoArgs,
+		RunE: runRecommend,
+	}
+	recommendCmd.Flags().Bool("sizing", false, "Recommander un type de machine")
+	recommendCmd.Flags().Int("window", 14, "Nombre de jours analysés")
+	recommendCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return recommendCmd
+}
@@ -0,0 +1,48 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// Fonction d'exécution de la commande security
+func runSecurity(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	expiryDays, _ := cmd.Flags().GetInt("expiry-days")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	findings, err := gcp.SecurityFindings(expiryDays)
+	if err != nil {
+		return fmt.Errorf("GCP: SecurityFindings: %w", err)
+	}
+
+	return security.PrintFindings(format, findings)
+}
+
+func SecurityCmd() *cobra.Command {
+	securityCmd := &cobra.Command{
+		Use:   "security",
+		Short: "Audit the security posture of the Cloud SQL instance",
+		Long: `Audit the security posture of the Cloud SQL instance: authorized networks open to 0.0.0.0/0, TLS enforcement (sslMode),
+password-only authentication (cloudsql.iam_authentication), server CA certificate expiry and deletion protection.`,
+		Args: cobra.NoArgs,
+		RunE: runSecurity,
+	}
+	securityCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	securityCmd.Flags().Int("expiry-days", 90, "Signaler les autorités de certification qui expirent dans ce nombre de jours")
+	return securityCmd
+}
@@ -0,0 +1,44 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// selectorRunE exécute la commande pour chaque instance dont les étiquettes (userLabels) satisfont
+// --selector, avec un résumé par instance à la fin. Sans sélecteur, la commande est exécutée une seule fois
+func selectorRunE(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		expression, _ := cmd.Flags().GetString("selector")
+		if expression == "" {
+			return run(cmd, args)
+		}
+		if viper.GetString("instance-name") != "" {
+			return fmt.Errorf("--instance-name et --selector sont incompatibles")
+		}
+
+		sel, err := selector.Parse(expression)
+		if err != nil {
+			return fmt.Errorf("selector: Parse: %w", err)
+		}
+
+		var gcp gcpPkg.GCP
+		err = gcp.Init("", viper.GetString("project-id"), viper.GetString("credentials-file"))
+		if err != nil {
+			return fmt.Errorf("GCP: Init: %w", err)
+		}
+		names, err := gcp.SelectInstances(sel)
+		if err != nil {
+			return fmt.Errorf("GCP: SelectInstances: %w", err)
+		}
+
+		return selector.ForEach(names, func(instanceName string) error {
+			viper.Set("instance-name", instanceName)
+			return run(cmd, args)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package gcp
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// Fonction d'exécution de la commande set
+func runSet(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	assignments, err := pgsettings.ParseAssignments(args)
+	if err != nil {
+		return err
+	}
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err = gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	changes, err := gcp.PlanFlagChanges(assignments)
+	if err != nil {
+		return fmt.Errorf("GCP: PlanFlagChanges: %w", err)
+	}
+
+	if len(changes) == 0 {
+		slog.Info("Aucune modification : les flags sont déjà à la valeur demandée")
+		return nil
+	}
+
+	restart := false
+	columns := []string{"Flag", "Current", "New", "RequiresRestart"}
+	rows := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, []string{change.Name, change.CurrentValue, change.Value, strconv.FormatBool(change.RequiresRestart)})
+		restart = restart || change.RequiresRestart
+	}
+	err = output.PrintRows("default", columns, rows)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if !force {
+		question := "Appliquer ces modifications ? (y/n): "
+		if restart {
+			// Cloud SQL redémarre l'instance automatiquement pour ces flags
+			question = "Ces modifications redémarrent l'instance. Appliquer ? (y/n): "
+		}
+		fmt.Print(question)
+		var response string
+		_, err = fmt.Scanln(&response)
+		if err != nil {
+			return fmt.Errorf("scanln: %w", err)
+		}
+
+		if strings.ToLower(response) != "y" {
+			return nil
+		}
+	}
+
+	err = gcp.ApplyFlagChanges(changes)
+	if err != nil {
+		return fmt.Errorf("GCP: ApplyFlagChanges: %w", err)
+	}
+
+	if restart {
+		slog.Warn("Modifications appliquées, l'instance redémarre", slog.String("instance", instanceName))
+	} else {
+		slog.Info("Modifications appliquées, aucun redémarrage nécessaire", slog.String("instance", instanceName))
+	}
+
+	return nil
+}
+
+func SetCmd() *cobra.Command {
+	setCmd := &cobra.Command{
+		Use:   "set name=value [name=value...]",
+		Short: "Modify database flags of a Cloud SQL instance",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSet,
+	}
+	setCmd.Flags().Bool("dry-run", false, "Afficher les modifications sans les appliquer")
+	setCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation avant d'appliquer les modifications")
+	return setCmd
+}
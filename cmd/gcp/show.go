@@ -0,0 +1,89 @@
+package gcp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+var outputFormat string
+var force bool
+var nonDefault bool
+
+// Déclaration de la commande show
+var showCmd = &cobra.Command{
+	Use:   "show [flags...]",
+	Short: "Show database flags of a Cloud SQL instance",
+	Args:  cobra.MinimumNArgs(0),
+	RunE:  runShow,
+}
+
+// Fonction d'exécution de la commande show
+func runShow(cmd *cobra.Command, args []string) error {
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err := gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	if nonDefault {
+		// Cloud SQL ne conserve que les flags positionnés explicitement
+		parameters := make([]output.Setting, 0)
+		for _, flag := range gcp.GetInstance().Settings.DatabaseFlags {
+			if len(args) > 0 && !slices.Contains(args, flag.Name) {
+				continue
+			}
+			parameters = append(parameters, output.Setting{Name: flag.Name, Value: flag.Value})
+		}
+
+		return output.PrintSettings(outputFormat, parameters)
+	}
+
+	if len(args) == 0 {
+		if !force {
+			fmt.Print("Voulez-vous récupérer tous les paramètres ? (y/n): ")
+			var response string
+			_, err = fmt.Scanln(&response)
+			if err != nil {
+				return fmt.Errorf("scanln: %w", err)
+			}
+
+			if strings.ToLower(response) != "y" {
+				return nil
+			}
+		}
+		// Récupérer tous les paramètres
+		args, err = gcp.GetAllFlagNames()
+		if err != nil {
+			return fmt.Errorf("GCP: GetAllFlagNames: %w", err)
+		}
+	}
+
+	parameters := make([]output.Setting, 0)
+	for _, setting := range args {
+		value, err := gcp.GetFlagValueByName(setting)
+		if err != nil {
+			return fmt.Errorf("GCP: GetFlagValueByName: %w", err)
+		}
+		parameters = append(parameters, output.Setting{Name: setting, Value: value})
+	}
+
+	return output.PrintSettings(outputFormat, parameters)
+}
+
+func ShowCmd() *cobra.Command {
+	showCmd.Flags().StringVarP(&outputFormat, "format", "F", "default", "Format de sortie (default, csv, json)")
+	showCmd.Flags().BoolVarP(&force, "force", "f", false, "Ne pas demander confirmation pour récupérer tous les paramètres")
+	showCmd.Flags().BoolVar(&nonDefault, "non-default", false, "Afficher uniquement les flags positionnés sur l'instance")
+	return showCmd
+}
@@ -0,0 +1,54 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+func runTopology(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	// Toutes les instances du projet sont listées pour relier primaires et réplicas
+	var gcp gcpPkg.GCP
+	err := gcp.Init("", projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	instanceTopology, err := gcp.Topology()
+	if err != nil {
+		return fmt.Errorf("GCP: Topology: %w", err)
+	}
+
+	if instanceName != "" {
+		instanceTopology = instanceTopology.Family(instanceName)
+		if len(instanceTopology.Nodes) == 0 {
+			return fmt.Errorf("instance %s introuvable", instanceName)
+		}
+	}
+
+	return topology.Print(format, instanceTopology)
+}
+
+func TopologyCmd() *cobra.Command {
+	topologyCmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Show the replication topology of the Cloud SQL instances",
+		Long: `Show primaries and read replicas of the project (masterInstanceName, replicaNames), with cross-region replicas.
+Replicas show the maximum replica_lag over the last hour, primaries the maximum replica_byte_lag of their replicas.
+With --instance-name, only the topology containing the instance is shown.
+Output as a tree (default), Graphviz (dot), Mermaid (mermaid), csv, markdown or json.`,
+		Args: cobra.NoArgs,
+		RunE: runTopology,
+	}
+	topologyCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, dot, mermaid, csv, json, markdown)")
+	return topologyCmd
+}
@@ -0,0 +1,72 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	gcpPkg "github.com/robinportigliatti/cloud_helper/internal/gcp"
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+func runUpgradePlan(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	extensions, _ := cmd.Flags().GetBool("extensions")
+	username, _ := cmd.Flags().GetString("username")
+	password, _ := cmd.Flags().GetString("password")
+	database, _ := cmd.Flags().GetString("dbname")
+
+	projectID := viper.GetString("project-id")
+	instanceName := viper.GetString("instance-name")
+	credentialsFile := viper.GetString("credentials-file")
+
+	calendar, err := upgrade.LoadCalendar()
+	if err != nil {
+		return fmt.Errorf("upgrade: LoadCalendar: %w", err)
+	}
+
+	// Initialisation de la connexion à GCP
+	var gcp gcpPkg.GCP
+	err = gcp.Init(instanceName, projectID, credentialsFile)
+	if err != nil {
+		return fmt.Errorf("GCP: Init: %w", err)
+	}
+
+	plan, err := gcp.UpgradePlan(calendar)
+	if err != nil {
+		return fmt.Errorf("GCP: UpgradePlan: %w", err)
+	}
+
+	if extensions {
+		connection, err := gcp.GetConnection(username, database, password)
+		if err != nil {
+			return fmt.Errorf("GCP: GetConnection: %w", err)
+		}
+		err = plan.CheckExtensions(context.Background(), calendar, connection, nil)
+		if err != nil {
+			return fmt.Errorf("upgrade: CheckExtensions: %w", err)
+		}
+	}
+
+	return upgrade.Print(format, []upgrade.Plan{plan})
+}
+
+func UpgradePlanCmd() *cobra.Command {
+	upgradePlanCmd := &cobra.Command{
+		Use:   "upgrade-plan",
+		Short: "Plan the engine upgrade of the Cloud SQL instance",
+		Long: `List the maintenance versions (availableMaintenanceVersions) and the major versions (upgradableDatabaseVersions)
+offered for the instance, and flag a version close to its end of support from the embedded calendar.
+With --extensions, the installed extensions are checked against the latest major version. Cloud SQL does not publish
+its list of supported extensions through the API, so the plan carries a note to check it for the target version.`,
+		Args: cobra.NoArgs,
+		RunE: runUpgradePlan,
+	}
+	upgradePlanCmd.Flags().Bool("extensions", false, "Vérifier les extensions installées (connexion à l'instance)")
+	upgradePlanCmd.Flags().String("username", "postgres", "Nom d'utilisateur PostgreSQL")
+	upgradePlanCmd.Flags().String("password", "", "Mot de passe PostgreSQL (par défaut : PGPASSWORD ou .pgpass)")
+	upgradePlanCmd.Flags().String("dbname", "postgres", "Nom de la base de données")
+	upgradePlanCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, markdown, json)")
+	return upgradePlanCmd
+}
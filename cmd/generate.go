@@ -8,7 +8,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	rdsCmd "github.com/robinportigliatti/cloud_helper/cmd/aws/rds"
 	"github.com/robinportigliatti/cloud_helper/internal/aws/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/pgpass"
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
 )
 
 // Déclaration de la structure DBInstance
@@ -18,13 +21,15 @@ type DBInstance struct {
 	DBParameters                 rds.DescribeDBParametersResult
 	InstanceType                 rds.InstanceType
 	ValidDBInstanceModifications rds.ValidDBInstanceModificationsMessage
+	Facts                        rules.Instance
+	Findings                     []rules.Result
 }
 
 // Déclaration de la commande generate
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Génère des fichiers à partir de la configuration RDS",
-	RunE:  runGenerate,
+	RunE:  rdsCmd.SelectorRunE(runGenerate),
 }
 
 // Fonction d'exécution de la commande generate
@@ -33,10 +38,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	fileFlag, _ := cmd.Flags().GetString("file")
 	templateFlag, _ := cmd.Flags().GetString("template")
 	templateName, _ := cmd.Flags().GetString("template-name")
+	all, _ := cmd.Flags().GetBool("all")
+	write, _ := cmd.Flags().GetBool("write")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
 
 	// Récupération des variables globales
 	dbInstanceIdentifier := viper.GetString("db-instance-identifier")
 	profile := viper.GetString("profile")
+	if all {
+		// Toutes les instances retournées par DescribeDbInstances
+		dbInstanceIdentifier = ""
+	}
 	var err error
 	var str string
 	// Initialisation de la connexion à RDS
@@ -61,11 +73,35 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("RDS: GetDBParameterGroupInformations: %w", err)
 		}
 	case ".pgpass":
+		if all || write {
+			// Mode --all : écriture de toutes les entrées dans ~/.pgpass, même si des secrets sont illisibles
+			entries, entriesErr := r.GetPgPassEntries("postgres")
+			if entriesErr != nil && len(entries) == 0 {
+				return fmt.Errorf("RDS: GetPgPassEntries: %w", entriesErr)
+			}
+
+			path, err := pgpass.DefaultPath()
+			if err != nil {
+				return fmt.Errorf("pgpass: DefaultPath: %w", err)
+			}
+
+			err = pgpass.Write(path, entries)
+			if err != nil {
+				return fmt.Errorf("pgpass: Write: %w", err)
+			}
+			if entriesErr != nil {
+				return fmt.Errorf("RDS: GetPgPassEntries: %w", entriesErr)
+			}
+			break
+		}
+
 		str, err := r.GenPgPass()
+		if str != "" {
+			fmt.Println(str)
+		}
 		if err != nil {
 			return fmt.Errorf("RDS: GenPgPass: %w", err)
 		}
-		fmt.Println(str)
 	case "psql":
 		str, err = r.GenPsql()
 		if err != nil {
@@ -80,6 +116,16 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			InstanceType:                 r.GetInstanceType(),
 			DefaultVCpus:                 r.GetDefaultVCpus(),
 			ValidDBInstanceModifications: r.GetValidDBInstanceModifications(),
+			Facts:                        r.Facts(),
+		}
+
+		// Évaluation des règles de bonnes pratiques, accessibles dans le template via .Findings
+		if len(rulesFiles) > 0 {
+			auditRules, err := rules.LoadFiles(rulesFiles)
+			if err != nil {
+				return fmt.Errorf("rules: LoadFiles: %w", err)
+			}
+			p.Findings = rules.Evaluate(auditRules, p.Facts)
 		}
 
 		funcMap := template.FuncMap{
@@ -110,11 +156,12 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 
 		str, err = r.GenPgPass()
+		if str != "" {
+			fmt.Println(str)
+		}
 		if err != nil {
 			return fmt.Errorf("RDS: GenPgPass: %w", err)
 		}
-
-		fmt.Println(str)
 	}
 
 	slog.Info("Génération terminée",
@@ -131,6 +178,11 @@ func init() {
 	generateCmd.Flags().String("file", "", "Fichier à générer (postgresql.conf, pg_hba.conf, .pgpass, audit, all)")
 	generateCmd.Flags().String("template", "", "Template à utiliser pour la génération")
 	generateCmd.Flags().String("template-name", "audit.md", "Nom du template à utiliser pour la génération")
+	generateCmd.Flags().Bool("all", false, "Générer le .pgpass de toutes les instances et l'écrire dans ~/.pgpass")
+	generateCmd.Flags().StringSlice("rules", nil, "Fichiers de règles YAML ou JSON évaluées pour --file=audit (résultats dans .Findings)")
+	generateCmd.Flags().Bool("write", false, "Écrire les entrées .pgpass dans ~/.pgpass (mode 0600) au lieu de les afficher")
+	generateCmd.Flags().String("selector", "", "Générer pour chaque instance dont les étiquettes satisfont le sélecteur (env=prod,team=payments)")
+	generateCmd.MarkFlagsMutuallyExclusive("all", "selector")
 
 	// Ajout de la commande au CLI principal
 	rootCmd.AddCommand(generateCmd)
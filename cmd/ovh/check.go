@@ -0,0 +1,70 @@
+package ovh
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+)
+
+// Fonction d'exécution de la commande check
+func runCheck(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetStringSlice("check-profile")
+	format, _ := cmd.Flags().GetString("format")
+	rulesFiles, _ := cmd.Flags().GetStringSlice("rules")
+
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	var results []check.Result
+	for _, checkProfile := range profiles {
+		profileResults, err := ovhClient.Check(checkProfile)
+		if err != nil {
+			return fmt.Errorf("OVH: Check: %w", err)
+		}
+		results = append(results, profileResults...)
+	}
+
+	// Règles d'audit évaluées sur le modèle commun aux fournisseurs
+	if len(rulesFiles) > 0 {
+		facts, err := ovhClient.Facts()
+		if err != nil {
+			return fmt.Errorf("OVH: Facts: %w", err)
+		}
+		ruleResults, err := check.RunRules(rulesFiles, facts)
+		if err != nil {
+			return fmt.Errorf("OVH: RunRules: %w", err)
+		}
+		results = append(results, ruleResults...)
+	}
+
+	err = check.PrintResults(format, results)
+	if err != nil {
+		return err
+	}
+
+	if check.Failed(results) {
+		return check.ErrFailed
+	}
+	return nil
+}
+
+func CheckCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the advanced configuration against embedded rule profiles",
+		Long: `Check the advanced configuration of the OVHcloud cluster against embedded rule profiles (pgbadger, quellog, security, performance).
+Parameters not exposed by OVHcloud are evaluated with the PostgreSQL default value.
+The command exits with a non-zero status when a rule of severity error is not satisfied.
+The profiles are selected with --check-profile, because --profile is the AWS profile under rds.
+The top-level "cloud_helper check --profile" checks several providers at once.`,
+		Args: cobra.NoArgs,
+		RunE: runCheck,
+	}
+	checkCmd.Flags().StringSlice("check-profile", []string{"pgbadger"}, "Profils de vérification (pgbadger, quellog, security, performance), équivalent de --profile de cloud_helper check")
+	checkCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown, junit)")
+	checkCmd.Flags().StringSlice("rules", nil, "Fichiers de règles d'audit YAML ou JSON à évaluer en plus des profils")
+	return checkCmd
+}
@@ -13,16 +13,18 @@ import (
 // Déclaration de la commande download
 var downloadCmd = &cobra.Command{
 	Use:   "download",
-	Short: "Download PostgreSQL logs from OVHcloud Database",
-	Long: `Download PostgreSQL logs from OVHcloud Database service.
+	Short: "Download PostgreSQL logs or metrics from OVHcloud Database",
+	Long: `Download PostgreSQL logs or metrics from OVHcloud Database service.
 Note: OVHcloud databases primarily use logs forwarding to Logs Data Platform.
-This command provides an interface for downloading logs when available via API.`,
+This command provides an interface for downloading logs when available via API.
+Metrics (cpu, memory, disk, connections...) are exported as CSV, PNG and HTML.`,
 	RunE: runDownload,
 }
 
 // Fonction d'exécution de la commande download
 func runDownload(cmd *cobra.Command, args []string) error {
 	// Récupération des flags
+	typeFlag, _ := cmd.Flags().GetString("type")
 	serviceName := viper.GetString("service-name")
 	clusterID := viper.GetString("cluster-id")
 	endpoint := viper.GetString("endpoint")
@@ -45,6 +47,35 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("OVH: Init: %w", err)
 	}
 
+	// Exécution en fonction du type de téléchargement
+	switch typeFlag {
+	case "logs":
+		downloadLogs(serviceName, clusterID, startFlag, endFlag, dirFlag)
+	case "metrics":
+		err = ovhClient.DownloadMetrics(startFlag, endFlag, dirFlag)
+		if err != nil {
+			return fmt.Errorf("OVH: DownloadMetrics: %w", err)
+		}
+	case "all":
+		downloadLogs(serviceName, clusterID, startFlag, endFlag, dirFlag)
+
+		err = ovhClient.DownloadMetrics(startFlag, endFlag, dirFlag)
+		if err != nil {
+			return fmt.Errorf("OVH: DownloadMetrics: %w", err)
+		}
+	}
+
+	slog.Info("Téléchargement terminé",
+		slog.String("type", typeFlag),
+		slog.String("start", startFlag),
+		slog.String("end", endFlag),
+		slog.String("directory", dirFlag),
+	)
+
+	return nil
+}
+
+func downloadLogs(serviceName string, clusterID string, startFlag string, endFlag string, dirFlag string) {
 	slog.Info("Téléchargement des logs OVH",
 		slog.String("serviceName", serviceName),
 		slog.String("clusterID", clusterID),
@@ -61,11 +92,10 @@ func runDownload(cmd *cobra.Command, args []string) error {
 	slog.Warn("Le téléchargement de logs OVHcloud n'est pas encore complètement implémenté")
 	slog.Info("OVHcloud recommande d'utiliser le système de logs forwarding vers Logs Data Platform")
 	slog.Info("Consultez la documentation: https://help.ovhcloud.com/csm/en-public-cloud-databases-logs-to-customers")
-
-	return nil
 }
 
 func DownloadCmd() *cobra.Command {
+	downloadCmd.Flags().String("type", "logs", "Type de fichier à télécharger (logs, metrics, all)")
 	downloadCmd.Flags().String("start-time", time.Now().AddDate(0, 0, -1).Format("2006-01-02T15:04:05"), "Date de début (format: YYYY-MM-DDTHH:MM:SS)")
 	downloadCmd.Flags().String("end-time", time.Now().Format("2006-01-02T15:04:05"), "Date de fin (format: YYYY-MM-DDTHH:MM:SS)")
 	downloadCmd.Flags().String("directory", "./", "Répertoire de destination")
@@ -0,0 +1,70 @@
+package ovh
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+)
+
+// Déclaration de la commande generate
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Génère des fichiers à partir de la configuration OVHcloud",
+	RunE:  runGenerate,
+}
+
+// Fonction d'exécution de la commande generate
+func runGenerate(cmd *cobra.Command, args []string) error {
+	fileFlag, _ := cmd.Flags().GetString("file")
+	username, _ := cmd.Flags().GetString("username")
+	database, _ := cmd.Flags().GetString("database")
+	force, _ := cmd.Flags().GetBool("force")
+
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	switch fileFlag {
+	case "postgresql.conf":
+		str, err := ovhClient.GenPostgreSQLConf()
+		if err != nil {
+			return fmt.Errorf("OVH: GenPostgreSQLConf: %w", err)
+		}
+
+		fmt.Println(str)
+	case ".pgpass":
+		// OVHcloud ne restitue pas les mots de passe : il faut les réinitialiser
+		if !force {
+			ok, err := confirm(fmt.Sprintf("La génération du .pgpass réinitialise le mot de passe de %s. Continuer ?", username))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		str, err := ovhClient.GenPgPass(username, database)
+		if err != nil {
+			return fmt.Errorf("OVH: GenPgPass: %w", err)
+		}
+
+		fmt.Println(str)
+	default:
+		return fmt.Errorf("fichier %s non supporté (postgresql.conf, .pgpass)", fileFlag)
+	}
+
+	slog.Info("Génération terminée", slog.String("file", fileFlag))
+
+	return nil
+}
+
+func GenerateCmd() *cobra.Command {
+	generateCmd.Flags().String("file", "", "Fichier à générer (postgresql.conf, .pgpass)")
+	generateCmd.Flags().String("username", "avnadmin", "Nom d'utilisateur PostgreSQL")
+	generateCmd.Flags().String("database", "*", "Nom de la base de données")
+	generateCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation avant la réinitialisation du mot de passe")
+	return generateCmd
+}
@@ -104,4 +104,9 @@ func init() {
 	OvhCmd.AddCommand(ListCmd())
 	OvhCmd.AddCommand(PsqlCmd())
 	OvhCmd.AddCommand(DownloadCmd())
+	OvhCmd.AddCommand(ShowCmd())
+	OvhCmd.AddCommand(UserCmd())
+	OvhCmd.AddCommand(GenerateCmd())
+	OvhCmd.AddCommand(CheckCmd())
+	OvhCmd.AddCommand(SecurityCmd())
 }
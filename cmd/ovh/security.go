@@ -0,0 +1,38 @@
+package ovh
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// Fonction d'exécution de la commande security
+func runSecurity(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	findings, err := ovhClient.SecurityFindings()
+	if err != nil {
+		return fmt.Errorf("OVH: SecurityFindings: %w", err)
+	}
+
+	return security.PrintFindings(format, findings)
+}
+
+func SecurityCmd() *cobra.Command {
+	securityCmd := &cobra.Command{
+		Use:   "security",
+		Short: "Audit the IP restrictions of the OVHcloud cluster",
+		Long: `Audit the IP restrictions of the OVHcloud cluster (0.0.0.0/0 exposure).
+OVHcloud always enforces TLS and encrypts storage.`,
+		Args: cobra.NoArgs,
+		RunE: runSecurity,
+	}
+	securityCmd.Flags().StringP("format", "F", "default", "Format de sortie (default, csv, json, markdown)")
+	return securityCmd
+}
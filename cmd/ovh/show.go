@@ -0,0 +1,88 @@
+package ovh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	ovhPkg "github.com/robinportigliatti/cloud_helper/internal/ovh"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+var outputFormat string
+var force bool
+
+// Déclaration de la commande show
+var showCmd = &cobra.Command{
+	Use:   "show [settings...]",
+	Short: "Show PostgreSQL advanced configuration of an OVHcloud cluster",
+	Args:  cobra.MinimumNArgs(0),
+	RunE:  runShow,
+}
+
+// Fonction d'exécution de la commande show
+func runShow(cmd *cobra.Command, args []string) error {
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	configuration, err := ovhClient.GetAdvancedConfiguration()
+	if err != nil {
+		return fmt.Errorf("OVH: GetAdvancedConfiguration: %w", err)
+	}
+
+	if len(args) == 0 {
+		if !force {
+			fmt.Print("Voulez-vous récupérer tous les paramètres ? (y/n): ")
+			var response string
+			_, err = fmt.Scanln(&response)
+			if err != nil {
+				return fmt.Errorf("scanln: %w", err)
+			}
+
+			if strings.ToLower(response) != "y" {
+				return nil
+			}
+		}
+		// Récupérer tous les paramètres
+		args = configuration.Names()
+	}
+
+	var capabilities []ovhPkg.AdvancedConfigurationCapability
+	parameters := make([]output.Setting, 0)
+	for _, setting := range args {
+		value, ok := configuration.GetValueByName(setting)
+		if !ok {
+			// Le paramètre n'est pas surchargé : vérifier qu'il fait partie des clés autorisées
+			if capabilities == nil {
+				capabilities, err = ovhClient.GetAdvancedConfigurationCapabilities()
+				if err != nil {
+					return fmt.Errorf("OVH: GetAdvancedConfigurationCapabilities: %w", err)
+				}
+			}
+			if !isAllowedSetting(capabilities, setting) {
+				return fmt.Errorf("OVH: paramètre %s inconnu de la configuration avancée", setting)
+			}
+		}
+		parameters = append(parameters, output.Setting{Name: setting, Value: value})
+	}
+
+	return output.PrintSettings(outputFormat, parameters)
+}
+
+// isAllowedSetting indique si un paramètre fait partie des capacités du cluster
+func isAllowedSetting(capabilities []ovhPkg.AdvancedConfigurationCapability, setting string) bool {
+	for _, capability := range capabilities {
+		if capability.Name == setting || capability.Name == "pg."+setting {
+			return true
+		}
+	}
+	return false
+}
+
+func ShowCmd() *cobra.Command {
+	showCmd.Flags().StringVarP(&outputFormat, "format", "F", "default", "Format de sortie (default, csv, json)")
+	showCmd.Flags().BoolVarP(&force, "force", "f", false, "Ne pas demander confirmation pour récupérer tous les paramètres")
+	return showCmd
+}
@@ -0,0 +1,140 @@
+package ovh
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	ovhPkg "github.com/robinportigliatti/cloud_helper/internal/ovh"
+)
+
+// Déclaration de la commande user
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage PostgreSQL users of an OVHcloud cluster",
+}
+
+// Déclaration de la commande user list
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List PostgreSQL users of an OVHcloud cluster",
+	RunE:  runUserList,
+}
+
+// Déclaration de la commande user reset-password
+var userResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "Reset the password of a PostgreSQL user",
+	RunE:  runUserResetPassword,
+}
+
+// initClusterClient initialise le client OVH après vérification des flags obligatoires
+func initClusterClient() (*ovhPkg.OVHClient, error) {
+	serviceName := viper.GetString("service-name")
+	clusterID := viper.GetString("cluster-id")
+	endpoint := viper.GetString("endpoint")
+
+	if serviceName == "" {
+		return nil, fmt.Errorf("le flag --service-name est obligatoire")
+	}
+
+	if clusterID == "" {
+		return nil, fmt.Errorf("le flag --cluster-id est obligatoire")
+	}
+
+	var ovhClient ovhPkg.OVHClient
+	err := ovhClient.Init(serviceName, clusterID, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Init: %w", err)
+	}
+
+	return &ovhClient, nil
+}
+
+// confirm demande une confirmation explicite à l'utilisateur
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s (y/n): ", question)
+	var response string
+	_, err := fmt.Scanln(&response)
+	if err != nil {
+		return false, fmt.Errorf("scanln: %w", err)
+	}
+
+	return strings.ToLower(response) == "y", nil
+}
+
+// Fonction d'exécution de la commande user list
+func runUserList(cmd *cobra.Command, args []string) error {
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	users, err := ovhClient.ListUsers()
+	if err != nil {
+		return fmt.Errorf("OVH: ListUsers: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(w, "User ID |\t Username |\t Status |\t Created At |")
+
+	for _, user := range users {
+		_, _ = fmt.Fprintf(w, "%s |\t %s |\t %s |\t %s |\n",
+			user.ID,
+			user.Username,
+			user.Status,
+			user.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	_ = w.Flush()
+	return nil
+}
+
+// Fonction d'exécution de la commande user reset-password
+func runUserResetPassword(cmd *cobra.Command, args []string) error {
+	username, _ := cmd.Flags().GetString("username")
+	force, _ := cmd.Flags().GetBool("force")
+
+	ovhClient, err := initClusterClient()
+	if err != nil {
+		return err
+	}
+
+	user, err := ovhClient.GetUserByName(username)
+	if err != nil {
+		return fmt.Errorf("OVH: GetUserByName: %w", err)
+	}
+
+	if !force {
+		ok, err := confirm(fmt.Sprintf("Le mot de passe actuel de %s ne sera plus valide. Réinitialiser ?", username))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	user, err = ovhClient.ResetUserPassword(user.ID)
+	if err != nil {
+		return fmt.Errorf("OVH: ResetUserPassword: %w", err)
+	}
+
+	slog.Info("Mot de passe réinitialisé", slog.String("username", user.Username))
+	fmt.Println(user.Password)
+
+	return nil
+}
+
+func UserCmd() *cobra.Command {
+	userResetPasswordCmd.Flags().String("username", "avnadmin", "Nom d'utilisateur PostgreSQL")
+	userResetPasswordCmd.Flags().BoolP("force", "f", false, "Ne pas demander confirmation avant la réinitialisation")
+
+	userCmd.AddCommand(userListCmd)
+	userCmd.AddCommand(userResetPasswordCmd)
+	return userCmd
+}
@@ -0,0 +1,47 @@
+package rds
+
+import "time"
+
+type DBClusterMember struct {
+	DBInstanceIdentifier          string `json:"DBInstanceIdentifier"`
+	IsClusterWriter               bool   `json:"IsClusterWriter"`
+	DBClusterParameterGroupStatus string `json:"DBClusterParameterGroupStatus"`
+	PromotionTier                 int    `json:"PromotionTier"`
+}
+
+type DBCluster struct {
+	DBClusterIdentifier     string            `json:"DBClusterIdentifier"`
+	DBClusterArn            string            `json:"DBClusterArn"`
+	Engine                  string            `json:"Engine"`
+	EngineVersion           string            `json:"EngineVersion"`
+	EngineMode              string            `json:"EngineMode"`
+	Status                  string            `json:"Status"`
+	MasterUsername          string            `json:"MasterUsername"`
+	MasterUserSecret        *MasterUserSecret `json:"MasterUserSecret,omitempty"`
+	Endpoint                string            `json:"Endpoint"`
+	ReaderEndpoint          string            `json:"ReaderEndpoint"`
+	Port                    int               `json:"Port"`
+	MultiAZ                 bool              `json:"MultiAZ"`
+	DBClusterParameterGroup string            `json:"DBClusterParameterGroup"`
+	DBClusterMembers        []DBClusterMember `json:"DBClusterMembers"`
+	BackupRetentionPeriod   int               `json:"BackupRetentionPeriod"`
+	PreferredBackupWindow   string            `json:"PreferredBackupWindow"`
+	EarliestRestorableTime  time.Time         `json:"EarliestRestorableTime"`
+	LatestRestorableTime    time.Time         `json:"LatestRestorableTime"`
+	ReadReplicaIdentifiers  []string          `json:"ReadReplicaIdentifiers"`
+	GlobalClusterIdentifier string            `json:"GlobalClusterIdentifier,omitempty"`
+}
+
+type DescribeDBClustersResult struct {
+	DBClusters []DBCluster `json:"DBClusters"`
+}
+
+// GetMember retourne le membre du cluster correspondant à l'instance
+func (c *DBCluster) GetMember(dbInstanceIdentifier string) (DBClusterMember, bool) {
+	for _, member := range c.DBClusterMembers {
+		if member.DBInstanceIdentifier == dbInstanceIdentifier {
+			return member, true
+		}
+	}
+	return DBClusterMember{}, false
+}
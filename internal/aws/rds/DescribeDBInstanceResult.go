@@ -4,12 +4,27 @@ import (
 	"time"
 )
 
+// MasterUserSecret référence le secret Secrets Manager contenant le mot de passe maître (ManageMasterUserPassword)
+type MasterUserSecret struct {
+	SecretArn    string `json:"SecretArn"`
+	SecretStatus string `json:"SecretStatus"`
+	KmsKeyID     string `json:"KmsKeyId"`
+}
+
+// Tag est une étiquette clé / valeur d'une ressource RDS
+type Tag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
 type DBInstance struct {
-	DBInstanceIdentifier string `json:"DBInstanceIdentifier"`
-	DBInstanceClass      string `json:"DBInstanceClass"`
-	Engine               string `json:"Engine"`
-	DBInstanceStatus     string `json:"DBInstanceStatus"`
-	MasterUsername       string `json:"MasterUsername"`
+	DBInstanceIdentifier string            `json:"DBInstanceIdentifier"`
+	DBInstanceClass      string            `json:"DBInstanceClass"`
+	Engine               string            `json:"Engine"`
+	DBInstanceStatus     string            `json:"DBInstanceStatus"`
+	DBClusterIdentifier  string            `json:"DBClusterIdentifier,omitempty"`
+	MasterUsername       string            `json:"MasterUsername"`
+	MasterUserSecret     *MasterUserSecret `json:"MasterUserSecret,omitempty"`
 	Endpoint             struct {
 		Address      string `json:"Address"`
 		Port         int    `json:"Port"`
@@ -46,23 +61,29 @@ type DBInstance struct {
 	} `json:"DBSubnetGroup"`
 	PreferredMaintenanceWindow string `json:"PreferredMaintenanceWindow"`
 	PendingModifiedValues      struct {
+		CACertificateIdentifier string `json:"CACertificateIdentifier,omitempty"`
 	} `json:"PendingModifiedValues"`
-	LatestRestorableTime             time.Time     `json:"LatestRestorableTime"`
-	MultiAZ                          bool          `json:"MultiAZ"`
-	EngineVersion                    string        `json:"EngineVersion"`
-	AutoMinorVersionUpgrade          bool          `json:"AutoMinorVersionUpgrade"`
-	ReadReplicaDBInstanceIdentifiers []interface{} `json:"ReadReplicaDBInstanceIdentifiers"`
-	LicenseModel                     string        `json:"LicenseModel"`
+	LatestRestorableTime             time.Time `json:"LatestRestorableTime"`
+	MultiAZ                          bool      `json:"MultiAZ"`
+	EngineVersion                    string    `json:"EngineVersion"`
+	AutoMinorVersionUpgrade          bool      `json:"AutoMinorVersionUpgrade"`
+	ReadReplicaDBInstanceIdentifiers []string  `json:"ReadReplicaDBInstanceIdentifiers"`
+	LicenseModel                     string    `json:"LicenseModel"`
 	OptionGroupMemberships           []struct {
 		OptionGroupName string `json:"OptionGroupName"`
 		Status          string `json:"Status"`
 	} `json:"OptionGroupMemberships"`
-	PubliclyAccessible                 bool          `json:"PubliclyAccessible"`
-	StorageType                        string        `json:"StorageType"`
-	DbInstancePort                     int           `json:"DbInstancePort"`
-	StorageEncrypted                   bool          `json:"StorageEncrypted"`
-	DbiResourceID                      string        `json:"DbiResourceId"`
-	CACertificateIdentifier            string        `json:"CACertificateIdentifier"`
+	PubliclyAccessible      bool   `json:"PubliclyAccessible"`
+	StorageType             string `json:"StorageType"`
+	Iops                    int    `json:"Iops,omitempty"`
+	DbInstancePort          int    `json:"DbInstancePort"`
+	StorageEncrypted        bool   `json:"StorageEncrypted"`
+	DbiResourceID           string `json:"DbiResourceId"`
+	CACertificateIdentifier string `json:"CACertificateIdentifier"`
+	CertificateDetails      struct {
+		CAIdentifier string    `json:"CAIdentifier"`
+		ValidTill    time.Time `json:"ValidTill"`
+	} `json:"CertificateDetails"`
 	DomainMemberships                  []interface{} `json:"DomainMemberships"`
 	CopyTagsToSnapshot                 bool          `json:"CopyTagsToSnapshot"`
 	MonitoringInterval                 int           `json:"MonitoringInterval"`
@@ -74,10 +95,25 @@ type DBInstance struct {
 	DeletionProtection                 bool          `json:"DeletionProtection"`
 	AssociatedRoles                    []interface{} `json:"AssociatedRoles"`
 	MaxAllocatedStorage                int           `json:"MaxAllocatedStorage,omitempty"`
-	TagList                            []interface{} `json:"TagList"`
+	TagList                            []Tag         `json:"TagList"`
 	CustomerOwnedIPEnabled             bool          `json:"CustomerOwnedIpEnabled"`
 	ActivityStreamStatus               string        `json:"ActivityStreamStatus"`
 	BackupTarget                       string        `json:"BackupTarget"`
+	// ARN des sauvegardes automatiques répliquées dans d'autres régions
+	DBInstanceAutomatedBackupsReplications []string `json:"DBInstanceAutomatedBackupsReplications,omitempty"`
+	// Source d'un réplica en lecture : identifiant dans la même région, ARN dans une autre région
+	// (de même que les réplicas de ReadReplicaDBInstanceIdentifiers)
+	ReadReplicaSourceDBInstanceIdentifier string `json:"ReadReplicaSourceDBInstanceIdentifier,omitempty"`
+	ReplicaMode                           string `json:"ReplicaMode,omitempty"`
+}
+
+// Tags retourne les étiquettes de l'instance indexées par clé
+func (instance DBInstance) Tags() map[string]string {
+	tags := make(map[string]string, len(instance.TagList))
+	for _, tag := range instance.TagList {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
 }
 
 type DescribeDBInstanceResult struct {
@@ -2,7 +2,10 @@ package rds
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
 	"golang.org/x/exp/slices"
 )
 
@@ -21,14 +24,187 @@ type Parameter struct {
 	ParameterValue       string `json:"ParameterValue,omitempty"`
 	AllowedValues        string `json:"AllowedValues,omitempty"`
 	MinimumEngineVersion string `json:"MinimumEngineVersion,omitempty"`
+	Level                string `json:"Level,omitempty"` // cluster ou instance (Aurora)
 }
 
 func (d *DescribeDBParametersResult) GetParameterValueByParameterName(parameterName string) (string, error) {
+	parameter, err := d.GetParameterByParameterName(parameterName)
+	if err != nil {
+		return "", err
+	}
+
+	return parameter.ParameterValue, nil
+}
+
+func (d *DescribeDBParametersResult) GetParameterByParameterName(parameterName string) (Parameter, error) {
 	idx := slices.IndexFunc(d.Parameters, func(c Parameter) bool { return c.ParameterName == parameterName })
 
 	if idx == -1 {
-		return "", fmt.Errorf("IndexFunc: parameter %s not found", parameterName)
+		return Parameter{}, fmt.Errorf("IndexFunc: parameter %s not found", parameterName)
+	}
+
+	return d.Parameters[idx], nil
+}
+
+// Merge fusionne les paramètres de cluster et d'instance (Aurora) : une valeur modifiée au niveau de
+// l'instance (Source user) l'emporte sur celle du cluster. Le groupe d'instance retourne aussi les valeurs
+// par défaut du moteur (engine-default, system), qui ne doivent pas masquer une valeur du cluster
+func (d *DescribeDBParametersResult) Merge(clusterParameters DescribeDBParametersResult) DescribeDBParametersResult {
+	var merged DescribeDBParametersResult
+	for _, parameter := range clusterParameters.Parameters {
+		parameter.Level = "cluster"
+		merged.Parameters = append(merged.Parameters, parameter)
+	}
+
+	for _, parameter := range d.Parameters {
+		parameter.Level = "instance"
+		idx := slices.IndexFunc(merged.Parameters, func(c Parameter) bool { return c.ParameterName == parameter.ParameterName })
+		if idx == -1 {
+			merged.Parameters = append(merged.Parameters, parameter)
+		} else if parameter.Source == "user" {
+			merged.Parameters[idx] = parameter
+		}
+	}
+
+	return merged
+}
+
+// ParameterDiff représente une différence entre deux ensembles de paramètres
+type ParameterDiff struct {
+	ParameterName string `json:"ParameterName"`
+	Change        string `json:"Change"` // added, removed ou changed
+	Value         string `json:"Value,omitempty"`
+	AgainstValue  string `json:"AgainstValue,omitempty"`
+	Source        string `json:"Source,omitempty"`
+	AgainstSource string `json:"AgainstSource,omitempty"`
+	ApplyType     string `json:"ApplyType,omitempty"`
+	Level         string `json:"Level,omitempty"` // cluster ou instance (Aurora)
+}
+
+// Diff compare les paramètres valorisés avec ceux d'un autre ensemble :
+// added (défini uniquement ici), removed (défini uniquement dans against), changed (valeurs différentes
+// une fois normalisées, 1 et on sont équivalents)
+func (d *DescribeDBParametersResult) Diff(against DescribeDBParametersResult) []ParameterDiff {
+	var diffs []ParameterDiff
+
+	for _, parameter := range d.Parameters {
+		if parameter.ParameterValue == "" {
+			continue
+		}
+
+		other, err := against.GetParameterByParameterName(parameter.ParameterName)
+		if err != nil || other.ParameterValue == "" {
+			diffs = append(diffs, ParameterDiff{
+				ParameterName: parameter.ParameterName,
+				Change:        "added",
+				Value:         parameter.ParameterValue,
+				Source:        parameter.Source,
+				AgainstSource: other.Source,
+				ApplyType:     parameter.ApplyType,
+				Level:         parameter.Level,
+			})
+			continue
+		}
+
+		if !pgsettings.Equivalent(parameter.ParameterValue, other.ParameterValue, "") {
+			diffs = append(diffs, ParameterDiff{
+				ParameterName: parameter.ParameterName,
+				Change:        "changed",
+				Value:         parameter.ParameterValue,
+				AgainstValue:  other.ParameterValue,
+				Source:        parameter.Source,
+				AgainstSource: other.Source,
+				ApplyType:     parameter.ApplyType,
+				Level:         parameter.Level,
+			})
+		}
+	}
+
+	for _, other := range against.Parameters {
+		if other.ParameterValue == "" {
+			continue
+		}
+
+		parameter, err := d.GetParameterByParameterName(other.ParameterName)
+		if err != nil || parameter.ParameterValue == "" {
+			applyType := parameter.ApplyType
+			if applyType == "" {
+				applyType = other.ApplyType
+			}
+			level := parameter.Level
+			if level == "" {
+				level = other.Level
+			}
+			diffs = append(diffs, ParameterDiff{
+				ParameterName: other.ParameterName,
+				Change:        "removed",
+				AgainstValue:  other.ParameterValue,
+				Source:        parameter.Source,
+				AgainstSource: other.Source,
+				ApplyType:     applyType,
+				Level:         level,
+			})
+		}
 	}
 
-	return d.Parameters[idx].ParameterValue, nil
+	slices.SortFunc(diffs, func(a, b ParameterDiff) int {
+		return strings.Compare(a.ParameterName, b.ParameterName)
+	})
+
+	return diffs
+}
+
+// Validate vérifie qu'une valeur est acceptable pour le paramètre (IsModifiable, DataType, AllowedValues)
+// et retourne la valeur à transmettre à RDS (les booléens sont convertis en 0/1)
+func (p Parameter) Validate(value string) (string, error) {
+	if !p.IsModifiable {
+		return "", fmt.Errorf("le paramètre %s n'est pas modifiable", p.ParameterName)
+	}
+
+	// Les formules RDS ({DBInstanceClassMemory/32768}...) ne peuvent pas être vérifiées localement
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		return value, nil
+	}
+
+	switch p.DataType {
+	case "boolean":
+		normalized, ok := pgsettings.NormalizeBool(value)
+		if !ok {
+			return "", fmt.Errorf("%s attend un booléen (valeur : %s)", p.ParameterName, value)
+		}
+		if normalized == "on" {
+			return "1", nil
+		}
+		return "0", nil
+	case "integer", "float":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || (p.DataType == "integer" && number != float64(int64(number))) {
+			return "", fmt.Errorf("%s attend une valeur de type %s (valeur : %s)", p.ParameterName, p.DataType, value)
+		}
+		for _, allowed := range strings.Split(p.AllowedValues, ",") {
+			if pgsettings.InRange(allowed, number) {
+				return value, nil
+			}
+		}
+		if p.AllowedValues != "" {
+			return "", fmt.Errorf("%s doit être compris dans %s (valeur : %s)", p.ParameterName, p.AllowedValues, value)
+		}
+		return value, nil
+	}
+
+	// string et list : les valeurs autorisées sont énumérées (sauf expressions régulières)
+	if p.AllowedValues == "" || strings.ContainsAny(p.AllowedValues, "^$*[") {
+		return value, nil
+	}
+	allowedValues := strings.Split(p.AllowedValues, ",")
+	values := []string{value}
+	if p.DataType == "list" {
+		values = strings.Split(value, ",")
+	}
+	for _, v := range values {
+		if !slices.ContainsFunc(allowedValues, func(allowed string) bool { return strings.EqualFold(strings.TrimSpace(allowed), strings.TrimSpace(v)) }) {
+			return "", fmt.Errorf("%s n'accepte que %s (valeur : %s)", p.ParameterName, p.AllowedValues, v)
+		}
+	}
+	return value, nil
 }
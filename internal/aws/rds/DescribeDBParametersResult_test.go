@@ -0,0 +1,82 @@
+package rds
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	cluster := DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "log_min_duration_statement", ParameterValue: "1000", Source: "user"},
+		{ParameterName: "work_mem", ParameterValue: "4096", Source: "engine-default"},
+	}}
+	instance := DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "log_min_duration_statement", ParameterValue: "-1", Source: "engine-default"},
+		{ParameterName: "work_mem", ParameterValue: "65536", Source: "user"},
+		{ParameterName: "shared_buffers", ParameterValue: "{DBInstanceClassMemory/32768}", Source: "system"},
+	}}
+
+	merged := instance.Merge(cluster)
+
+	tests := []struct {
+		name  string
+		value string
+		level string
+	}{
+		// Une valeur par défaut du groupe d'instance ne masque pas la valeur du cluster
+		{"log_min_duration_statement", "1000", "cluster"},
+		{"work_mem", "65536", "instance"},
+		{"shared_buffers", "{DBInstanceClassMemory/32768}", "instance"},
+	}
+	for _, tt := range tests {
+		parameter, err := merged.GetParameterByParameterName(tt.name)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if parameter.ParameterValue != tt.value || parameter.Level != tt.level {
+			t.Errorf("%s = %q (%s), attendu %q (%s)", tt.name, parameter.ParameterValue, parameter.Level, tt.value, tt.level)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	// Instance Aurora comparée aux valeurs par défaut des groupes d'instance et de cluster
+	instance := DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "log_min_duration_statement", ParameterValue: "1000", Source: "user"},
+		{ParameterName: "work_mem", ParameterValue: "4096", Source: "engine-default"},
+	}}
+	parameters := instance.Merge(DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "rds.force_ssl", ParameterValue: "on", Source: "user"},
+		{ParameterName: "log_statement", ParameterValue: "ddl", Source: "user"},
+		{ParameterName: "wal_level", ParameterValue: "logical", Source: "user"},
+	}})
+	instanceDefaults := DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "log_min_duration_statement", ParameterValue: "-1", Source: "engine-default"},
+		{ParameterName: "work_mem", ParameterValue: "4096", Source: "engine-default"},
+		{ParameterName: "random_page_cost", ParameterValue: "4", Source: "engine-default"},
+	}}
+	defaults := instanceDefaults.Merge(DescribeDBParametersResult{Parameters: []Parameter{
+		{ParameterName: "rds.force_ssl", ParameterValue: "1", Source: "engine-default"},
+		{ParameterName: "log_statement", ParameterValue: "none", Source: "engine-default"},
+	}})
+
+	want := map[string]ParameterDiff{
+		"log_min_duration_statement": {Change: "changed", Level: "instance"},
+		"log_statement":              {Change: "changed", Level: "cluster"},
+		"random_page_cost":           {Change: "removed", Level: "instance"},
+		"wal_level":                  {Change: "added", Level: "cluster"},
+	}
+
+	diffs := parameters.Diff(defaults)
+	if len(diffs) != len(want) {
+		t.Errorf("%d différences, attendu %d : %+v", len(diffs), len(want), diffs)
+	}
+	for _, diff := range diffs {
+		expected, ok := want[diff.ParameterName]
+		if !ok {
+			// rds.force_ssl : 1 et on sont équivalents
+			t.Errorf("%s: différence inattendue %+v", diff.ParameterName, diff)
+			continue
+		}
+		if diff.Change != expected.Change || diff.Level != expected.Level {
+			t.Errorf("%s = %s (%s), attendu %s (%s)", diff.ParameterName, diff.Change, diff.Level, expected.Change, expected.Level)
+		}
+	}
+}
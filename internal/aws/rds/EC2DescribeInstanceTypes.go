@@ -28,6 +28,10 @@ type InstanceType struct {
 		EbsOptimizedSupport string `json:"EbsOptimizedSupport"`
 		EncryptionSupport   string `json:"EncryptionSupport"`
 		NvmeSupport         string `json:"NvmeSupport"`
+		EbsOptimizedInfo    struct {
+			BaselineIops int `json:"BaselineIops"`
+			MaximumIops  int `json:"MaximumIops"`
+		} `json:"EbsOptimizedInfo"`
 	} `json:"EbsInfo"`
 	NetworkInfo struct {
 		NetworkPerformance       string `json:"NetworkPerformance"`
@@ -0,0 +1,120 @@
+package rds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+	"github.com/robinportigliatti/cloud_helper/internal/pgpass"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// URL du bundle des autorités de certification RDS (toutes régions)
+const caBundleURL = "https://truststore.pki.rds.amazonaws.com/global/global-bundle.pem"
+
+// BuildAuthToken génère localement un jeton d'authentification IAM (signature SigV4, valable 15 minutes)
+func (rds RDS) BuildAuthToken(username string) (string, error) {
+	instance := rds.GetdbInstance()
+	if !instance.IAMDatabaseAuthenticationEnabled {
+		return "", fmt.Errorf("RDS: l'authentification IAM n'est pas activée sur %s", instance.DBInstanceIdentifier)
+	}
+
+	endpoint := fmt.Sprintf("%s:%d", instance.Endpoint.Address, instance.Endpoint.Port)
+	token, err := auth.BuildAuthToken(rds.ctx, endpoint, rds.awsConfig.Region, username, rds.awsConfig.Credentials)
+	if err != nil {
+		return "", fmt.Errorf("RDS: BuildAuthToken: %w", err)
+	}
+
+	return token, nil
+}
+
+// DownloadCABundle télécharge le bundle des autorités de certification RDS s'il n'est pas déjà présent
+// et retourne son chemin
+func DownloadCABundle() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserCacheDir: %w", err)
+	}
+
+	directory := filepath.Join(cacheDir, "cloud_helper")
+	err = os.MkdirAll(directory, 0o700)
+	if err != nil {
+		return "", fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	caFile := filepath.Join(directory, "rds-global-bundle.pem")
+	if _, err := os.Stat(caFile); err == nil {
+		return caFile, nil
+	}
+
+	err = DownloadFile(caFile, caBundleURL)
+	if err != nil {
+		return "", fmt.Errorf("DownloadFile: %w", err)
+	}
+
+	return caFile, nil
+}
+
+// GetIAMConnection retourne les paramètres de connexion de l'instance avec un jeton IAM comme mot de passe
+func (rds RDS) GetIAMConnection(username string, database string) (postgres.Connection, error) {
+	token, err := rds.BuildAuthToken(username)
+	if err != nil {
+		return postgres.Connection{}, err
+	}
+
+	caFile, err := DownloadCABundle()
+	if err != nil {
+		return postgres.Connection{}, fmt.Errorf("RDS: DownloadCABundle: %w", err)
+	}
+
+	instance := rds.GetdbInstance()
+	return postgres.Connection{
+		Host:        instance.Endpoint.Address,
+		Port:        instance.Endpoint.Port,
+		Database:    database,
+		Username:    username,
+		Password:    token,
+		SSLMode:     "verify-full",
+		SSLRootCert: caFile,
+	}, nil
+}
+
+// GetConnection retourne les paramètres de connexion de l'instance avec un mot de passe fourni
+// Sans mot de passe, psql et pgx utilisent PGPASSWORD ou le fichier .pgpass
+func (rds RDS) GetConnection(username string, database string, password string) (postgres.Connection, error) {
+	caFile, err := DownloadCABundle()
+	if err != nil {
+		return postgres.Connection{}, fmt.Errorf("RDS: DownloadCABundle: %w", err)
+	}
+
+	instance := rds.GetdbInstance()
+	return postgres.Connection{
+		Host:        instance.Endpoint.Address,
+		Port:        instance.Endpoint.Port,
+		Database:    database,
+		Username:    username,
+		Password:    password,
+		SSLMode:     "verify-full",
+		SSLRootCert: caFile,
+	}, nil
+}
+
+// GenPgPassIAM retourne une ligne .pgpass utilisant un jeton IAM comme mot de passe
+func (rds RDS) GenPgPassIAM(username string, database string) (string, error) {
+	token, err := rds.BuildAuthToken(username)
+	if err != nil {
+		return "", err
+	}
+
+	instance := rds.GetdbInstance()
+	entry := pgpass.Entry{
+		Hostname: instance.Endpoint.Address,
+		Port:     instance.Endpoint.Port,
+		Database: database,
+		Username: username,
+		Password: token,
+	}
+
+	return entry.String(), nil
+}
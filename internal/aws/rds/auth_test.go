@@ -0,0 +1,71 @@
+package rds
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// testRDS retourne une connexion sans accès réseau : identifiants statiques, région et instance fixes
+func testRDS(iamEnabled bool) RDS {
+	instance := DBInstance{DBInstanceIdentifier: "prod", IAMDatabaseAuthenticationEnabled: iamEnabled}
+	instance.Endpoint.Address = "prod.abc123.eu-west-1.rds.amazonaws.com"
+	instance.Endpoint.Port = 5432
+
+	return RDS{
+		ctx: context.Background(),
+		awsConfig: aws.Config{
+			Region:      "eu-west-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		},
+		dbInstances: DescribeDBInstanceResult{DBInstances: []DBInstance{instance}},
+	}
+}
+
+func TestBuildAuthToken(t *testing.T) {
+	token, err := testRDS(true).BuildAuthToken("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"prod.abc123.eu-west-1.rds.amazonaws.com:5432?",
+		"Action=connect",
+		"DBUser=app",
+		"X-Amz-Credential=AKIDEXAMPLE%2F",
+		"%2Feu-west-1%2Frds-db%2Faws4_request",
+		"X-Amz-Signature=",
+	} {
+		if !strings.Contains(token, expected) {
+			t.Errorf("jeton %q : %q absent", token, expected)
+		}
+	}
+	if strings.HasPrefix(token, "https://") {
+		t.Errorf("le jeton ne doit pas contenir de schéma : %q", token)
+	}
+}
+
+func TestBuildAuthTokenDisabled(t *testing.T) {
+	_, err := testRDS(false).BuildAuthToken("app")
+	if err == nil {
+		t.Fatal("une erreur est attendue quand l'authentification IAM est désactivée")
+	}
+}
+
+func TestGenPgPassIAM(t *testing.T) {
+	line, err := testRDS(true).GenPgPassIAM("app", "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := "prod.abc123.eu-west-1.rds.amazonaws.com:5432:postgres:app:"
+	if !strings.HasPrefix(line, prefix) {
+		t.Errorf("ligne .pgpass %q, préfixe attendu %q", line, prefix)
+	}
+	if !strings.Contains(line, "Action=connect") {
+		t.Errorf("ligne .pgpass sans jeton IAM : %q", line)
+	}
+}
@@ -0,0 +1,193 @@
+package rds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+
+	"github.com/robinportigliatti/cloud_helper/internal/backup"
+)
+
+// snapshotType ramène le type de snapshot RDS (automated, manual, awsbackup...) aux types du rapport
+func snapshotType(snapshotType string) string {
+	if snapshotType == "automated" {
+		return backup.TypeAutomated
+	}
+	return backup.TypeManual
+}
+
+// InstanceSnapshots retourne les snapshots automatiques et manuels d'une instance (DescribeDBSnapshots)
+func (rds RDS) InstanceSnapshots(dbInstanceIdentifier string) ([]backup.Snapshot, error) {
+	var snapshots []backup.Snapshot
+	paginator := awsRds.NewDescribeDBSnapshotsPaginator(rds.rdsClient, &awsRds.DescribeDBSnapshotsInput{
+		DBInstanceIdentifier: aws.String(dbInstanceIdentifier),
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeDBSnapshots SDK call: %w", err)
+		}
+		for _, snapshot := range result.DBSnapshots {
+			snapshots = append(snapshots, backup.Snapshot{
+				Name:     aws.ToString(snapshot.DBSnapshotIdentifier),
+				Type:     snapshotType(aws.ToString(snapshot.SnapshotType)),
+				Status:   aws.ToString(snapshot.Status),
+				Created:  aws.ToTime(snapshot.SnapshotCreateTime),
+				Location: aws.ToString(snapshot.AvailabilityZone),
+			})
+		}
+	}
+	return snapshots, nil
+}
+
+// ClusterSnapshots retourne les snapshots automatiques et manuels d'un cluster Aurora (DescribeDBClusterSnapshots)
+func (rds RDS) ClusterSnapshots(dbClusterIdentifier string) ([]backup.Snapshot, error) {
+	var snapshots []backup.Snapshot
+	paginator := awsRds.NewDescribeDBClusterSnapshotsPaginator(rds.rdsClient, &awsRds.DescribeDBClusterSnapshotsInput{
+		DBClusterIdentifier: aws.String(dbClusterIdentifier),
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeDBClusterSnapshots SDK call: %w", err)
+		}
+		for _, snapshot := range result.DBClusterSnapshots {
+			snapshots = append(snapshots, backup.Snapshot{
+				Name:    aws.ToString(snapshot.DBClusterSnapshotIdentifier),
+				Type:    snapshotType(aws.ToString(snapshot.SnapshotType)),
+				Status:  aws.ToString(snapshot.Status),
+				Created: aws.ToTime(snapshot.SnapshotCreateTime),
+			})
+		}
+	}
+	return snapshots, nil
+}
+
+// earliestRestorableTime retourne le début de la fenêtre de restauration d'une instance
+// (RestoreWindow de DescribeDBInstanceAutomatedBackups)
+func (rds RDS) earliestRestorableTime(dbiResourceID string) (time.Time, error) {
+	result, err := rds.rdsClient.DescribeDBInstanceAutomatedBackups(rds.ctx, &awsRds.DescribeDBInstanceAutomatedBackupsInput{
+		DbiResourceId: aws.String(dbiResourceID),
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("RDS: DescribeDBInstanceAutomatedBackups SDK call: %w", err)
+	}
+
+	var earliest time.Time
+	for _, automatedBackup := range result.DBInstanceAutomatedBackups {
+		if automatedBackup.RestoreWindow == nil || automatedBackup.RestoreWindow.EarliestTime == nil {
+			continue
+		}
+		if earliest.IsZero() || automatedBackup.RestoreWindow.EarliestTime.Before(earliest) {
+			earliest = *automatedBackup.RestoreWindow.EarliestTime
+		}
+	}
+	return earliest, nil
+}
+
+// clusterCrossRegion indique si un cluster Aurora a un réplica dans une autre région, ou fait partie d'une base
+// globale dont un membre est dans une autre région : ce cluster conserve ses propres sauvegardes
+func (rds RDS) clusterCrossRegion(cluster DBCluster) (bool, error) {
+	region := arnRegion(cluster.DBClusterArn)
+	for _, replica := range cluster.ReadReplicaIdentifiers {
+		if replicaRegion := arnRegion(replica); replicaRegion != "" && replicaRegion != region {
+			return true, nil
+		}
+	}
+	if cluster.GlobalClusterIdentifier == "" {
+		return false, nil
+	}
+
+	result, err := rds.rdsClient.DescribeGlobalClusters(rds.ctx, &awsRds.DescribeGlobalClustersInput{
+		GlobalClusterIdentifier: aws.String(cluster.GlobalClusterIdentifier),
+	})
+	if err != nil {
+		return false, fmt.Errorf("RDS: DescribeGlobalClusters SDK call: %w", err)
+	}
+	for _, globalCluster := range result.GlobalClusters {
+		for _, member := range globalCluster.GlobalClusterMembers {
+			if memberRegion := arnRegion(aws.ToString(member.DBClusterArn)); memberRegion != "" && memberRegion != region {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// BackupReports retourne la configuration de sauvegarde de chaque instance, ou de chaque cluster Aurora
+// pour ses membres : rétention, fenêtre de restauration, réplication des sauvegardes et snapshots
+func (rds RDS) BackupReports() ([]backup.Report, error) {
+	var reports []backup.Report
+	region := rds.GetRegion()
+
+	clusters := make(map[string]bool)
+	for _, instance := range rds.dbInstances.DBInstances {
+		if instance.DBClusterIdentifier != "" {
+			clusters[instance.DBClusterIdentifier] = true
+			continue
+		}
+
+		report := backup.Report{
+			Provider:      "rds",
+			Resource:      instance.DBInstanceIdentifier,
+			Region:        region,
+			RetentionDays: instance.BackupRetentionPeriod,
+			BackupWindow:  instance.PreferredBackupWindow,
+			PITR:          instance.BackupRetentionPeriod > 0,
+		}
+		// Les copies manuelles de snapshots sont enregistrées dans la région de destination et ne sont pas
+		// visibles depuis celle de l'instance : sans réplication des sauvegardes automatiques, la copie est inconnue
+		if len(instance.DBInstanceAutomatedBackupsReplications) > 0 {
+			crossRegion := true
+			report.CrossRegion = &crossRegion
+		}
+
+		snapshots, err := rds.InstanceSnapshots(instance.DBInstanceIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err)
+		}
+		report.SetSnapshots(snapshots)
+
+		if report.PITR {
+			earliest, err := rds.earliestRestorableTime(instance.DbiResourceID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err)
+			}
+			report.SetWindow(earliest, instance.LatestRestorableTime)
+		}
+		reports = append(reports, report)
+	}
+
+	for _, cluster := range rds.dbClusters.DBClusters {
+		if !clusters[cluster.DBClusterIdentifier] {
+			continue
+		}
+
+		report := backup.Report{
+			Provider:      "rds",
+			Resource:      cluster.DBClusterIdentifier,
+			Region:        region,
+			RetentionDays: cluster.BackupRetentionPeriod,
+			BackupWindow:  cluster.PreferredBackupWindow,
+			PITR:          cluster.BackupRetentionPeriod > 0,
+		}
+		crossRegion, err := rds.clusterCrossRegion(cluster)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cluster.DBClusterIdentifier, err)
+		}
+		if crossRegion {
+			report.CrossRegion = &crossRegion
+		}
+
+		snapshots, err := rds.ClusterSnapshots(cluster.DBClusterIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cluster.DBClusterIdentifier, err)
+		}
+		report.SetSnapshots(snapshots)
+		report.SetWindow(cluster.EarliestRestorableTime, cluster.LatestRestorableTime)
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,232 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// Certificate représente une autorité de certification RDS (rds-ca-rsa2048-g1...)
+type Certificate struct {
+	CertificateIdentifier     string    `json:"CertificateIdentifier"`
+	CertificateType           string    `json:"CertificateType"`
+	ValidFrom                 time.Time `json:"ValidFrom"`
+	ValidTill                 time.Time `json:"ValidTill"`
+	CustomerOverride          bool      `json:"CustomerOverride"`
+	CustomerOverrideValidTill time.Time `json:"CustomerOverrideValidTill,omitempty"`
+	DefaultForNewLaunches     bool      `json:"DefaultForNewLaunches"`
+}
+
+// DescribeCertificates retourne les autorités de certification disponibles dans la région
+func (rds *RDS) DescribeCertificates() ([]Certificate, error) {
+	var certificates []Certificate
+
+	paginator := awsRds.NewDescribeCertificatesPaginator(rds.rdsClient, &awsRds.DescribeCertificatesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeCertificates SDK call: %w", err)
+		}
+
+		defaultCertificate := aws.ToString(result.DefaultCertificateForNewLaunches)
+		for _, sdkCertificate := range result.Certificates {
+			certificates = append(certificates, Certificate{
+				CertificateIdentifier:     aws.ToString(sdkCertificate.CertificateIdentifier),
+				CertificateType:           aws.ToString(sdkCertificate.CertificateType),
+				ValidFrom:                 aws.ToTime(sdkCertificate.ValidFrom),
+				ValidTill:                 aws.ToTime(sdkCertificate.ValidTill),
+				CustomerOverride:          aws.ToBool(sdkCertificate.CustomerOverride),
+				CustomerOverrideValidTill: aws.ToTime(sdkCertificate.CustomerOverrideValidTill),
+				DefaultForNewLaunches:     defaultCertificate != "" && aws.ToString(sdkCertificate.CertificateIdentifier) == defaultCertificate,
+			})
+		}
+	}
+
+	return certificates, nil
+}
+
+// GetCertificate retourne l'autorité de certification correspondant à l'identifiant
+func GetCertificate(certificates []Certificate, certificateIdentifier string) (Certificate, bool) {
+	for _, certificate := range certificates {
+		if certificate.CertificateIdentifier == certificateIdentifier {
+			return certificate, true
+		}
+	}
+	return Certificate{}, false
+}
+
+// Statuts du certificat d'une instance
+const (
+	CertificateStatusOK       = "ok"
+	CertificateStatusPending  = "pending"
+	CertificateStatusExpiring = "expiring"
+	CertificateStatusExpired  = "expired"
+	CertificateStatusUnknown  = "unknown"
+)
+
+// CertificateStatus décrit l'autorité de certification d'une instance et sa rotation
+type CertificateStatus struct {
+	Region                     string    `json:"Region"`
+	DBInstanceIdentifier       string    `json:"DBInstanceIdentifier"`
+	CACertificateIdentifier    string    `json:"CACertificateIdentifier"`
+	CAValidTill                time.Time `json:"CAValidTill"`
+	ServerCertificateValidTill time.Time `json:"ServerCertificateValidTill"`
+	DaysLeft                   int       `json:"DaysLeft"`
+	NewerCA                    string    `json:"NewerCA,omitempty"`
+	PendingCA                  string    `json:"PendingCA,omitempty"`
+	Status                     string    `json:"Status"`
+}
+
+// newerCertificate retourne une autorité de certification plus récente que celle de l'instance,
+// de préférence celle utilisée par défaut pour les nouvelles instances
+func newerCertificate(certificates []Certificate, current Certificate) (Certificate, bool) {
+	var newer Certificate
+	found := false
+	for _, certificate := range certificates {
+		if certificate.CertificateIdentifier == current.CertificateIdentifier || !certificate.ValidTill.After(current.ValidTill) {
+			continue
+		}
+		if certificate.DefaultForNewLaunches {
+			return certificate, true
+		}
+		if !found || certificate.ValidTill.After(newer.ValidTill) {
+			newer = certificate
+			found = true
+		}
+	}
+	return newer, found
+}
+
+// CertificateReport rapproche les instances de la région des autorités de certification disponibles.
+// L'expiration retenue est celle du certificat serveur lorsqu'elle est connue, sinon celle de l'autorité
+func (rds *RDS) CertificateReport(expiryDays int) ([]CertificateStatus, error) {
+	certificates, err := rds.DescribeCertificates()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeCertificates: %w", err)
+	}
+
+	now := time.Now()
+	var report []CertificateStatus
+	for _, instance := range rds.dbInstances.DBInstances {
+		status := CertificateStatus{
+			Region:                     rds.GetRegion(),
+			DBInstanceIdentifier:       instance.DBInstanceIdentifier,
+			CACertificateIdentifier:    instance.CACertificateIdentifier,
+			ServerCertificateValidTill: instance.CertificateDetails.ValidTill,
+			PendingCA:                  instance.PendingModifiedValues.CACertificateIdentifier,
+			Status:                     CertificateStatusUnknown,
+		}
+
+		certificate, ok := GetCertificate(certificates, instance.CACertificateIdentifier)
+		if ok {
+			status.CAValidTill = certificate.ValidTill
+			if newer, ok := newerCertificate(certificates, certificate); ok {
+				status.NewerCA = newer.CertificateIdentifier
+			}
+		}
+
+		validTill := status.ServerCertificateValidTill
+		if validTill.IsZero() {
+			validTill = status.CAValidTill
+		}
+		if !validTill.IsZero() {
+			status.DaysLeft = int(math.Floor(validTill.Sub(now).Hours() / 24))
+			switch {
+			case !validTill.After(now):
+				status.Status = CertificateStatusExpired
+			case status.PendingCA != "":
+				status.Status = CertificateStatusPending
+			case status.DaysLeft <= expiryDays:
+				status.Status = CertificateStatusExpiring
+			default:
+				status.Status = CertificateStatusOK
+			}
+		}
+
+		report = append(report, status)
+	}
+	return report, nil
+}
+
+// CertificateReports produit le rapport des régions en parallèle, au plus concurrency à la fois.
+// Avec un identifiant d'instance, les régions où l'instance n'existe pas sont ignorées, et une erreur
+// n'est retournée que si elle n'est trouvée dans aucune région. Une région en erreur n'interrompt pas
+// les autres : les instances trouvées sont retournées avec les erreurs regroupées
+func CertificateReports(dbInstanceIdentifier string, profile string, regions []string, expiryDays int, concurrency int) ([]CertificateStatus, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		report   []CertificateStatus
+		errs     []error
+		notFound int
+	)
+	semaphore := make(chan struct{}, concurrency)
+	for _, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			regionReport, err := regionCertificateReport(dbInstanceIdentifier, profile, region, expiryDays)
+			mu.Lock()
+			defer mu.Unlock()
+			var instanceNotFound *rdsTypes.DBInstanceNotFoundFault
+			switch {
+			case err != nil && dbInstanceIdentifier != "" && errors.As(err, &instanceNotFound):
+				notFound++
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", region, err))
+			default:
+				report = append(report, regionReport...)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if notFound == len(regions) {
+		return nil, fmt.Errorf("RDS: instance %s introuvable dans les régions interrogées", dbInstanceIdentifier)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Region != report[j].Region {
+			return report[i].Region < report[j].Region
+		}
+		return report[i].DBInstanceIdentifier < report[j].DBInstanceIdentifier
+	})
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return report, errors.Join(errs...)
+}
+
+// regionCertificateReport initialise la connexion dans une région et produit son rapport
+func regionCertificateReport(dbInstanceIdentifier string, profile string, region string, expiryDays int) ([]CertificateStatus, error) {
+	var rds RDS
+	rds.dbInstanceIdentifier = dbInstanceIdentifier
+	err := rds.connect(profile, region, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Seules les instances sont nécessaires : les paramètres et types d'instance chargés par Init sont inutiles
+	rds.dbInstances, err = rds.DescribeDbInstances()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDbInstances: %w", err)
+	}
+
+	report, err := rds.CertificateReport(expiryDays)
+	if err != nil {
+		return nil, fmt.Errorf("RDS: CertificateReport: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,209 @@
+package rds
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// DefaultCloneNameTemplate est le modèle de nom par défaut des groupes clonés (ex: ma-base-postgres16)
+const DefaultCloneNameTemplate = "{{.Identifier}}-{{.Family}}"
+
+// OverrideProfiles regroupe les jeux de paramètres applicables lors d'un clonage
+var OverrideProfiles = map[string][]pgsettings.Assignment{
+	// Journalisation nécessaire à un rapport pgbadger complet
+	"pgbadger-ready": {
+		{Name: "log_min_duration_statement", Value: "0"},
+		{Name: "log_autovacuum_min_duration", Value: "0"},
+		{Name: "log_checkpoints", Value: "1"},
+		{Name: "log_connections", Value: "1"},
+		{Name: "log_disconnections", Value: "1"},
+		{Name: "log_lock_waits", Value: "1"},
+		{Name: "log_temp_files", Value: "0"},
+		{Name: "track_io_timing", Value: "1"},
+	},
+}
+
+// cloneNameData contient les variables disponibles dans le modèle de nom
+type cloneNameData struct {
+	Identifier string
+	Family     string
+	Source     string
+}
+
+// CloneParameterGroupName calcule le nom du groupe cloné à partir du modèle
+func (rds *RDS) CloneParameterGroupName(nameTemplate string) (string, error) {
+	family, err := rds.GetDBParameterGroupFamily()
+	if err != nil {
+		return "", fmt.Errorf("RDS: GetDBParameterGroupFamily: %w", err)
+	}
+
+	return cloneName(nameTemplate, rds.GetdbInstance().DBInstanceIdentifier, family, rds.GetDBParameterGroupName())
+}
+
+// CloneClusterParameterGroupName calcule le nom du groupe de paramètres de cluster cloné (Aurora)
+func (rds *RDS) CloneClusterParameterGroupName(nameTemplate string) (string, error) {
+	cluster := rds.GetDbCluster()
+	if cluster == nil {
+		return "", fmt.Errorf("RDS: %s n'appartient à aucun cluster", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+
+	family, err := rds.GetDBClusterParameterGroupFamily()
+	if err != nil {
+		return "", fmt.Errorf("RDS: GetDBClusterParameterGroupFamily: %w", err)
+	}
+
+	return cloneName(nameTemplate, cluster.DBClusterIdentifier, family, cluster.DBClusterParameterGroup)
+}
+
+func cloneName(nameTemplate string, identifier string, family string, source string) (string, error) {
+	tmpl, err := template.New("name").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("template.Parse: %w", err)
+	}
+
+	var name strings.Builder
+	err = tmpl.Execute(&name, cloneNameData{
+		Identifier: identifier,
+		Family:     strings.ReplaceAll(family, ".", "-"),
+		Source:     strings.ReplaceAll(source, ".", "-"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("template.Execute: %w", err)
+	}
+
+	return name.String(), nil
+}
+
+// ParameterGroupLevels indique si les affectations visent le groupe de paramètres d'instance,
+// celui du cluster (paramètres de niveau cluster sur Aurora), ou les deux
+func (rds *RDS) ParameterGroupLevels(assignments []pgsettings.Assignment) (instance bool, cluster bool, err error) {
+	for _, assignment := range assignments {
+		parameter, err := rds.dbParameterGroups.GetParameterByParameterName(assignment.Name)
+		if err != nil {
+			return false, false, fmt.Errorf("RDS: paramètre %s inconnu: %w", assignment.Name, err)
+		}
+
+		if parameter.Level == "cluster" {
+			cluster = true
+		} else {
+			instance = true
+		}
+	}
+	return instance, cluster, nil
+}
+
+// ValidateAssignments vérifie les valeurs demandées sans tenir compte du groupe de paramètres cible
+func (rds *RDS) ValidateAssignments(assignments []pgsettings.Assignment) error {
+	for _, assignment := range assignments {
+		parameter, err := rds.dbParameterGroups.GetParameterByParameterName(assignment.Name)
+		if err != nil {
+			return fmt.Errorf("RDS: paramètre %s inconnu: %w", assignment.Name, err)
+		}
+
+		_, err = parameter.Validate(assignment.Value)
+		if err != nil {
+			return fmt.Errorf("RDS: Validate: %w", err)
+		}
+	}
+	return nil
+}
+
+// CopyParameterGroup copie le groupe de paramètres attaché à l'instance (DBParameterGroups) sous un nouveau nom
+func (rds *RDS) CopyParameterGroup(targetName string) error {
+	sourceName := rds.GetDBParameterGroupName()
+	if sourceName == "" {
+		return fmt.Errorf("RDS: aucun groupe de paramètres pour %s", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+
+	_, err := rds.rdsClient.CopyDBParameterGroup(rds.ctx, &awsRds.CopyDBParameterGroupInput{
+		SourceDBParameterGroupIdentifier:  aws.String(sourceName),
+		TargetDBParameterGroupIdentifier:  aws.String(targetName),
+		TargetDBParameterGroupDescription: aws.String(fmt.Sprintf("Copie de %s pour %s", sourceName, rds.GetdbInstance().DBInstanceIdentifier)),
+	})
+	if err != nil {
+		return fmt.Errorf("RDS: CopyDBParameterGroup SDK call: %w", err)
+	}
+
+	return nil
+}
+
+// CopyClusterParameterGroup copie le groupe de paramètres du cluster Aurora de l'instance sous un nouveau nom
+func (rds *RDS) CopyClusterParameterGroup(targetName string) error {
+	cluster := rds.GetDbCluster()
+	if cluster == nil || cluster.DBClusterParameterGroup == "" {
+		return fmt.Errorf("RDS: aucun groupe de paramètres de cluster pour %s", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+
+	_, err := rds.rdsClient.CopyDBClusterParameterGroup(rds.ctx, &awsRds.CopyDBClusterParameterGroupInput{
+		SourceDBClusterParameterGroupIdentifier:  aws.String(cluster.DBClusterParameterGroup),
+		TargetDBClusterParameterGroupIdentifier:  aws.String(targetName),
+		TargetDBClusterParameterGroupDescription: aws.String(fmt.Sprintf("Copie de %s pour %s", cluster.DBClusterParameterGroup, cluster.DBClusterIdentifier)),
+	})
+	if err != nil {
+		return fmt.Errorf("RDS: CopyDBClusterParameterGroup SDK call: %w", err)
+	}
+
+	return nil
+}
+
+// UseParameterGroup recharge les paramètres à partir d'un autre groupe d'instance, pour planifier des
+// modifications sur celui-ci. Sur Aurora, les paramètres du cluster restent fusionnés
+func (rds *RDS) UseParameterGroup(name string) error {
+	if len(rds.dbInstances.DBInstances) > 0 && len(rds.dbInstances.DBInstances[0].DBParameterGroups) > 0 {
+		rds.dbInstances.DBInstances[0].DBParameterGroups[0].DBParameterGroupName = name
+	}
+
+	return rds.loadParameterGroups()
+}
+
+// UseClusterParameterGroup recharge les paramètres à partir d'un autre groupe de paramètres de cluster
+// (Aurora), fusionné avec le groupe d'instance
+func (rds *RDS) UseClusterParameterGroup(name string) error {
+	cluster := rds.GetDbCluster()
+	if cluster == nil {
+		return fmt.Errorf("RDS: %s n'appartient à aucun cluster", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+	cluster.DBClusterParameterGroup = name
+
+	return rds.loadParameterGroups()
+}
+
+// AttachParameterGroup attache le groupe de paramètres à l'instance via ModifyDBInstance.
+// Le nouveau groupe n'est pris en compte qu'après un redémarrage de l'instance
+func (rds *RDS) AttachParameterGroup(name string) error {
+	_, err := rds.rdsClient.ModifyDBInstance(rds.ctx, &awsRds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(rds.GetdbInstance().DBInstanceIdentifier),
+		DBParameterGroupName: aws.String(name),
+		ApplyImmediately:     aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("RDS: ModifyDBInstance SDK call: %w", err)
+	}
+
+	return nil
+}
+
+// AttachClusterParameterGroup attache le groupe de paramètres de cluster via ModifyDBCluster.
+// Les paramètres statiques ne sont pris en compte qu'après un redémarrage des instances du cluster
+func (rds *RDS) AttachClusterParameterGroup(name string) error {
+	cluster := rds.GetDbCluster()
+	if cluster == nil {
+		return fmt.Errorf("RDS: %s n'appartient à aucun cluster", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+
+	_, err := rds.rdsClient.ModifyDBCluster(rds.ctx, &awsRds.ModifyDBClusterInput{
+		DBClusterIdentifier:         aws.String(cluster.DBClusterIdentifier),
+		DBClusterParameterGroupName: aws.String(name),
+		ApplyImmediately:            aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("RDS: ModifyDBCluster SDK call: %w", err)
+	}
+
+	return nil
+}
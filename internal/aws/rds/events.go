@@ -0,0 +1,75 @@
+package rds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	"github.com/robinportigliatti/cloud_helper/internal/events"
+)
+
+// Events retourne les événements RDS de l'instance entre start et end (DescribeEvents) : redémarrages,
+// bascules, maintenance, ainsi que les événements de son cluster Aurora et de ses groupes de paramètres.
+// RDS ne conserve les événements que 14 jours
+func (rds RDS) Events(start time.Time, end time.Time) ([]events.Event, error) {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return nil, fmt.Errorf("no instance found")
+	}
+	instance := rds.dbInstances.DBInstances[0]
+
+	sources := map[string]rdsTypes.SourceType{
+		instance.DBInstanceIdentifier: rdsTypes.SourceTypeDbInstance,
+	}
+	if instance.DBClusterIdentifier != "" {
+		sources[instance.DBClusterIdentifier] = rdsTypes.SourceTypeDbCluster
+	}
+	for _, parameterGroup := range instance.DBParameterGroups {
+		sources[parameterGroup.DBParameterGroupName] = rdsTypes.SourceTypeDbParameterGroup
+	}
+
+	var result []events.Event
+	for sourceIdentifier, sourceType := range sources {
+		sourceEvents, err := rds.describeEvents(sourceIdentifier, sourceType, start, end)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sourceEvents...)
+	}
+	events.Sort(result)
+	return result, nil
+}
+
+// describeEvents retourne les événements d'une source RDS entre start et end
+func (rds RDS) describeEvents(sourceIdentifier string, sourceType rdsTypes.SourceType, start time.Time, end time.Time) ([]events.Event, error) {
+	var result []events.Event
+	paginator := awsRds.NewDescribeEventsPaginator(rds.rdsClient, &awsRds.DescribeEventsInput{
+		SourceIdentifier: aws.String(sourceIdentifier),
+		SourceType:       sourceType,
+		StartTime:        aws.Time(start),
+		EndTime:          aws.Time(end),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeEvents SDK call: %w", err)
+		}
+		for _, event := range page.Events {
+			category := strings.Join(event.EventCategories, ",")
+			if category == "" {
+				category = string(event.SourceType)
+			}
+			result = append(result, events.Event{
+				Time:     aws.ToTime(event.Date),
+				Provider: "rds",
+				Source:   aws.ToString(event.SourceIdentifier),
+				Category: category,
+				Message:  aws.ToString(event.Message),
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package rds
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// Facts retourne le modèle commun aux fournisseurs utilisé par le moteur de règles.
+// Les formules du groupe de paramètres ({DBInstanceClassMemory/32768}...) sont évaluées
+// avec la mémoire et les vCPU de la classe d'instance
+func (rds RDS) Facts() rules.Instance {
+	facts := rules.Instance{
+		Provider:   "rds",
+		Parameters: make(map[string]rules.Parameter),
+	}
+
+	if len(rds.dbInstances.DBInstances) > 0 {
+		dbInstance := rds.dbInstances.DBInstances[0]
+		facts.Identifier = dbInstance.DBInstanceIdentifier
+		facts.EngineVersion = dbInstance.EngineVersion
+		facts.MachineType = dbInstance.DBInstanceClass
+		facts.StorageGB = dbInstance.AllocatedStorage
+		facts.HighAvailability = dbInstance.MultiAZ
+		facts.BackupRetentionDays = &dbInstance.BackupRetentionPeriod
+	}
+	if cluster := rds.GetDbCluster(); cluster != nil && len(cluster.DBClusterMembers) > 1 {
+		facts.HighAvailability = true
+	}
+	if len(rds.describeInstanceTypes.InstanceTypes) > 0 {
+		facts.VCPU = rds.GetDefaultVCpus()
+		facts.MemoryBytes = int64(rds.GetMemoryInfo().SizeInMiB) * 1024 * 1024
+	}
+
+	variables := map[string]float64{
+		"DBInstanceClassMemory": float64(facts.MemoryBytes),
+		"DBInstanceVCPU":        float64(facts.VCPU),
+		"AllocatedStorage":      float64(facts.StorageGB) * 1024 * 1024 * 1024,
+	}
+	for _, parameter := range rds.dbParameterGroups.Parameters {
+		value := parameter.ParameterValue
+		if strings.Contains(value, "{") {
+			if number, err := rules.EvalFormula(value, variables); err == nil {
+				value = strconv.FormatFloat(number, 'f', -1, 64)
+			}
+		}
+		facts.Parameters[parameter.ParameterName] = rules.Parameter{Value: value}
+	}
+	return facts
+}
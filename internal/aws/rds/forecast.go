@@ -0,0 +1,171 @@
+package rds
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+)
+
+// metricPeriod retourne la période d'agrégation CloudWatch couvrant l'intervalle en 1440 points au plus.
+// CloudWatch ne conserve les points à la minute que 15 jours et à 5 minutes que 63 jours : au-delà,
+// la période doit être un multiple de 300 puis de 3600 secondes, sinon les points anciens sont omis
+func metricPeriod(startTime time.Time, endTime time.Time, now time.Time) int32 {
+	multiple := 60.0
+	switch age := now.Sub(startTime); {
+	case age > 63*24*time.Hour:
+		multiple = 3600
+	case age > 15*24*time.Hour:
+		multiple = 300
+	}
+
+	period := math.Ceil(endTime.Sub(startTime).Seconds()/1440/multiple) * multiple
+	return int32(max(period, multiple))
+}
+
+// metricSamples retourne une statistique CloudWatch de l'instance sur la période.
+// GetMetricStatistics étant limité à 1440 points, la période d'agrégation est élargie avec la durée analysée
+func (rds RDS) metricSamples(metricName string, statistic cwTypes.Statistic, startTime time.Time, endTime time.Time) ([]forecast.Sample, error) {
+	period := metricPeriod(startTime, endTime, time.Now())
+
+	statsInput := &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/RDS"),
+		MetricName: aws.String(metricName),
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int32(period),
+		Statistics: []cwTypes.Statistic{statistic},
+		Dimensions: []cwTypes.Dimension{{
+			Name:  aws.String("DBInstanceIdentifier"),
+			Value: aws.String(rds.dbInstanceIdentifier),
+		}},
+	}
+
+	statsResult, err := rds.cloudwatchClient.GetMetricStatistics(rds.ctx, statsInput)
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch: GetMetricStatistics SDK call: %w", err)
+	}
+
+	samples := make([]forecast.Sample, 0, len(statsResult.Datapoints))
+	for _, datapoint := range statsResult.Datapoints {
+		var value *float64
+		switch statistic {
+		case cwTypes.StatisticMinimum:
+			value = datapoint.Minimum
+		case cwTypes.StatisticMaximum:
+			value = datapoint.Maximum
+		default:
+			value = datapoint.Average
+		}
+		samples = append(samples, forecast.Sample{Timestamp: aws.ToTime(datapoint.Timestamp), Value: aws.ToFloat64(value)})
+	}
+	return samples, nil
+}
+
+// toRanges convertit les plages de DescribeValidDBInstanceModifications
+func toRanges(ranges []struct {
+	From int `json:"From"`
+	To   int `json:"To"`
+	Step int `json:"Step"`
+}) []forecast.Range {
+	result := make([]forecast.Range, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, forecast.Range{From: r.From, To: r.To, Step: r.Step})
+	}
+	return result
+}
+
+// ForecastStorage ajuste une tendance à l'espace utilisé (AllocatedStorage - FreeStorageSpace) sur
+// les derniers jours, estime l'échéance du disque plein et du plafond d'autoscaling (MaxAllocatedStorage),
+// et recommande une taille et des IOPS dans les plages de DescribeValidDBInstanceModifications
+func (rds RDS) ForecastStorage(historyDays int, horizonDays int) (forecast.StorageForecast, error) {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return forecast.StorageForecast{}, fmt.Errorf("no instance found")
+	}
+	instance := rds.dbInstances.DBInstances[0]
+
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -historyDays)
+
+	// Le minimum d'espace libre sur chaque période correspond au pic d'occupation
+	free, err := rds.metricSamples("FreeStorageSpace", cwTypes.StatisticMinimum, startTime, endTime)
+	if err != nil {
+		return forecast.StorageForecast{}, fmt.Errorf("FreeStorageSpace: %w", err)
+	}
+
+	// Une modification de taille pendant l'historique fausserait l'espace utilisé : seule la taille
+	// actuelle est connue, ce qui reste une bonne approximation pour une tendance
+	used := make([]forecast.Sample, 0, len(free))
+	for _, sample := range free {
+		used = append(used, forecast.Sample{Timestamp: sample.Timestamp, Value: float64(instance.AllocatedStorage)*forecast.GiB - sample.Value})
+	}
+
+	storageForecast, err := forecast.Storage(used, instance.AllocatedStorage, instance.MaxAllocatedStorage, historyDays, horizonDays)
+	if err != nil {
+		return storageForecast, fmt.Errorf("forecast.Storage: %w", err)
+	}
+	storageForecast.Provider = "rds"
+	storageForecast.Resource = instance.DBInstanceIdentifier
+	storageForecast.Iops = instance.Iops
+
+	validModifications := rds.GetValidDBInstanceModifications()
+	idx := slices.IndexFunc(validModifications.Storage, func(s Storage) bool { return s.StorageType == instance.StorageType })
+	if idx == -1 {
+		storageForecast.RecommendedStorageGB = max(storageForecast.TargetStorageGB(), instance.AllocatedStorage)
+		storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("aucune plage de modification pour le type de stockage %s", instance.StorageType))
+		return storageForecast, nil
+	}
+	storage := validModifications.Storage[idx]
+
+	storageForecast.RecommendStorage(toRanges(storage.StorageSize))
+	if instance.MaxAllocatedStorage == 0 && storage.SupportsStorageAutoscaling {
+		storageForecast.Notes = append(storageForecast.Notes, "l'autoscaling du stockage est disponible mais désactivé (MaxAllocatedStorage)")
+	}
+	if storageForecast.DaysUntilMaxStorage != nil && *storageForecast.DaysUntilMaxStorage <= horizonDays {
+		storageForecast.Notes = append(storageForecast.Notes, "le plafond d'autoscaling sera atteint avant l'horizon, augmenter MaxAllocatedStorage")
+	}
+
+	// Les IOPS ne sont modifiables que pour les types de stockage provisionnés (io1, io2, gp3)
+	if len(storage.ProvisionedIops) == 0 {
+		return storageForecast, nil
+	}
+
+	readIops, err := rds.metricSamples("ReadIOPS", cwTypes.StatisticMaximum, startTime, endTime)
+	if err != nil {
+		return storageForecast, fmt.Errorf("ReadIOPS: %w", err)
+	}
+	writeIops, err := rds.metricSamples("WriteIOPS", cwTypes.StatisticMaximum, startTime, endTime)
+	if err != nil {
+		return storageForecast, fmt.Errorf("WriteIOPS: %w", err)
+	}
+	storageForecast.PeakIops = math.Round(forecast.Peak(forecast.Sum(readIops, writeIops)))
+
+	target := int(math.Ceil(storageForecast.PeakIops * forecast.IopsMargin))
+
+	// Le ratio IOPS / stockage borne les IOPS possibles pour la taille recommandée
+	for _, ratio := range storage.IopsToStorageRatio {
+		if ratio.From > 0 {
+			target = max(target, int(math.Ceil(ratio.From*float64(storageForecast.RecommendedStorageGB))))
+		}
+		if ratio.To > 0 {
+			maximum := int(ratio.To * float64(storageForecast.RecommendedStorageGB))
+			if target > maximum {
+				storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("%d IOPS nécessitent un stockage plus grand (ratio maximal %.0f IOPS/GB)", target, ratio.To))
+				target = maximum
+			}
+		}
+	}
+
+	recommended, ok := forecast.RoundUp(toRanges(storage.ProvisionedIops), target)
+	if !ok {
+		storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("%d IOPS dépassent le maximum provisionnable", target))
+	}
+	storageForecast.RecommendedIops = recommended
+	return storageForecast, nil
+}
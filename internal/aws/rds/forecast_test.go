@@ -0,0 +1,37 @@
+package rds
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricPeriod(t *testing.T) {
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		days   int
+		period int32
+	}{
+		// Moins de 15 jours : multiple de 60 secondes
+		{1, 60},
+		{7, 420},
+		{14, 840},
+		// Entre 15 et 63 jours : multiple de 300 secondes
+		{16, 1200},
+		{30, 1800},
+		{63, 3900},
+		// Au-delà de 63 jours : multiple de 3600 secondes
+		{90, 7200},
+		{365, 25200},
+	}
+	for _, tt := range tests {
+		startTime := now.AddDate(0, 0, -tt.days)
+		period := metricPeriod(startTime, now, now)
+		if period != tt.period {
+			t.Errorf("%d jours = %d, attendu %d", tt.days, period, tt.period)
+		}
+		if points := now.Sub(startTime).Seconds() / float64(period); points > 1440 {
+			t.Errorf("%d jours: %.0f points, maximum 1440", tt.days, points)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Target est un couple profil / région à inventorier. RoleARN, s'il est renseigné,
+// est le rôle IAM assumé avec les identifiants du profil pour accéder à un autre compte
+type Target struct {
+	Profile string
+	Region  string
+	RoleARN string
+}
+
+// String décrit la cible dans les messages d'erreur
+func (t Target) String() string {
+	if t.RoleARN != "" {
+		return fmt.Sprintf("%s/%s (%s)", t.Profile, t.Region, t.RoleARN)
+	}
+	return fmt.Sprintf("%s/%s", t.Profile, t.Region)
+}
+
+// InventoryEntry est une instance de l'inventaire, avec le compte et la région où elle a été trouvée
+type InventoryEntry struct {
+	Account              string `json:"account"`
+	Profile              string `json:"profile"`
+	Region               string `json:"region"`
+	DBClusterIdentifier  string `json:"db_cluster_identifier,omitempty"`
+	DBInstanceIdentifier string `json:"db_instance_identifier"`
+	Role                 string `json:"role"`
+	Engine               string `json:"engine"`
+	EngineVersion        string `json:"engine_version"`
+	DBInstanceClass      string `json:"db_instance_class"`
+	Status               string `json:"status"`
+	Endpoint             string `json:"endpoint"`
+	Port                 int    `json:"port"`
+}
+
+// ScanTarget liste les instances et clusters Aurora d'une cible, sans charger les
+// paramètres ni les types d'instance comme le fait Init
+func ScanTarget(target Target) ([]InventoryEntry, error) {
+	var rds RDS
+	err := rds.connect(target.Profile, target.Region, target.RoleARN)
+	if err != nil {
+		return nil, err
+	}
+
+	rds.dbInstances, err = rds.DescribeDbInstances()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDbInstances: %w", err)
+	}
+	rds.dbClusters, err = rds.DescribeDbClusters()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDbClusters: %w", err)
+	}
+
+	entries := make([]InventoryEntry, 0, len(rds.dbInstances.DBInstances))
+	for _, instance := range rds.dbInstances.DBInstances {
+		role := "primary"
+		switch {
+		case instance.DBClusterIdentifier != "":
+			role = "reader"
+			cluster := rds.findCluster(instance.DBClusterIdentifier)
+			member, _ := cluster.GetMember(instance.DBInstanceIdentifier)
+			if member.IsClusterWriter {
+				role = "writer"
+			}
+		case instance.ReadReplicaSourceDBInstanceIdentifier != "":
+			role = "replica"
+		}
+
+		entries = append(entries, InventoryEntry{
+			Account:              arnAccount(instance.DBInstanceArn),
+			Profile:              target.Profile,
+			Region:               rds.GetRegion(),
+			DBClusterIdentifier:  instance.DBClusterIdentifier,
+			DBInstanceIdentifier: instance.DBInstanceIdentifier,
+			Role:                 role,
+			Engine:               instance.Engine,
+			EngineVersion:        instance.EngineVersion,
+			DBInstanceClass:      instance.DBInstanceClass,
+			Status:               instance.DBInstanceStatus,
+			Endpoint:             instance.Endpoint.Address,
+			Port:                 instance.Endpoint.Port,
+		})
+	}
+	return entries, nil
+}
+
+// Inventory inventorie les cibles en parallèle, au plus concurrency à la fois, et fusionne les résultats
+// triés par compte, région, cluster et instance. Une cible en erreur n'interrompt pas les autres :
+// les instances trouvées sont retournées avec les erreurs regroupées
+func Inventory(targets []Target, concurrency int) ([]InventoryEntry, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		entries []InventoryEntry
+		errs    []error
+	)
+	semaphore := make(chan struct{}, concurrency)
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			targetEntries, err := ScanTarget(target)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", target, err))
+				return
+			}
+			entries = append(entries, targetEntries...)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.DBClusterIdentifier != b.DBClusterIdentifier {
+			return a.DBClusterIdentifier < b.DBClusterIdentifier
+		}
+		if a.DBInstanceIdentifier != b.DBInstanceIdentifier {
+			return a.DBInstanceIdentifier < b.DBInstanceIdentifier
+		}
+		return a.Profile < b.Profile
+	})
+	// Un même compte peut être atteint par plusieurs profils : chaque instance n'est gardée qu'une fois
+	merged := make([]InventoryEntry, 0, len(entries))
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].Account == entry.Account && entries[i-1].Region == entry.Region &&
+			entries[i-1].DBInstanceIdentifier == entry.DBInstanceIdentifier {
+			continue
+		}
+		merged = append(merged, entry)
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return merged, errors.Join(errs...)
+}
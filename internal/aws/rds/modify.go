@@ -0,0 +1,183 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// ModifyDBParameterGroup accepte au plus 20 paramètres par appel
+const maxParametersPerCall = 20
+
+// ErrDefaultParameterGroup est retournée lorsqu'une modification cible un groupe de paramètres par défaut
+var ErrDefaultParameterGroup = errors.New("les groupes de paramètres par défaut ne sont pas modifiables")
+
+// ParameterChange représente une modification de paramètre planifiée
+type ParameterChange struct {
+	ParameterName  string `json:"ParameterName"`
+	CurrentValue   string `json:"CurrentValue"`
+	ParameterValue string `json:"ParameterValue"`
+	ApplyType      string `json:"ApplyType"`
+	ApplyMethod    string `json:"ApplyMethod"`
+	Level          string `json:"Level,omitempty"`
+	ParameterGroup string `json:"ParameterGroup"`
+}
+
+// IsDefaultParameterGroup indique si le nom correspond à un groupe de paramètres géré par AWS
+func IsDefaultParameterGroup(name string) bool {
+	return strings.HasPrefix(name, "default.") || strings.HasPrefix(name, "default:")
+}
+
+// GetDBParameterGroupName retourne le groupe de paramètres attaché à l'instance
+func (rds RDS) GetDBParameterGroupName() string {
+	instance := rds.GetdbInstance()
+	if len(instance.DBParameterGroups) == 0 {
+		return ""
+	}
+	return instance.DBParameterGroups[0].DBParameterGroupName
+}
+
+// PlanParameterChanges valide les affectations et retourne les modifications à appliquer,
+// sans rien modifier. Les paramètres déjà à la valeur demandée sont ignorés
+func (rds *RDS) PlanParameterChanges(assignments []pgsettings.Assignment) ([]ParameterChange, error) {
+	var changes []ParameterChange
+
+	for _, assignment := range assignments {
+		parameter, err := rds.dbParameterGroups.GetParameterByParameterName(assignment.Name)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: paramètre %s inconnu: %w", assignment.Name, err)
+		}
+
+		value, err := parameter.Validate(assignment.Value)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: Validate: %w", err)
+		}
+
+		if pgsettings.Equivalent(parameter.ParameterValue, value, "") {
+			continue
+		}
+
+		groupName := rds.GetDBParameterGroupName()
+		if parameter.Level == "cluster" {
+			groupName = rds.GetDbCluster().DBClusterParameterGroup
+		}
+		if IsDefaultParameterGroup(groupName) {
+			return nil, fmt.Errorf("RDS: %s: %w", groupName, ErrDefaultParameterGroup)
+		}
+
+		applyMethod := string(rdsTypes.ApplyMethodImmediate)
+		if parameter.ApplyType == "static" {
+			applyMethod = string(rdsTypes.ApplyMethodPendingReboot)
+		}
+
+		changes = append(changes, ParameterChange{
+			ParameterName:  parameter.ParameterName,
+			CurrentValue:   parameter.ParameterValue,
+			ParameterValue: value,
+			ApplyType:      parameter.ApplyType,
+			ApplyMethod:    applyMethod,
+			Level:          parameter.Level,
+			ParameterGroup: groupName,
+		})
+	}
+
+	return changes, nil
+}
+
+// ApplyParameterChanges applique les modifications via ModifyDBParameterGroup
+// (ou ModifyDBClusterParameterGroup pour les paramètres de cluster Aurora)
+func (rds *RDS) ApplyParameterChanges(changes []ParameterChange) error {
+	byGroup := make(map[string][]ParameterChange)
+	var groups []string
+	for _, change := range changes {
+		if _, ok := byGroup[change.ParameterGroup]; !ok {
+			groups = append(groups, change.ParameterGroup)
+		}
+		byGroup[change.ParameterGroup] = append(byGroup[change.ParameterGroup], change)
+	}
+
+	for _, group := range groups {
+		groupChanges := byGroup[group]
+		for start := 0; start < len(groupChanges); start += maxParametersPerCall {
+			end := min(start+maxParametersPerCall, len(groupChanges))
+
+			var parameters []rdsTypes.Parameter
+			for _, change := range groupChanges[start:end] {
+				parameters = append(parameters, rdsTypes.Parameter{
+					ParameterName:  aws.String(change.ParameterName),
+					ParameterValue: aws.String(change.ParameterValue),
+					ApplyMethod:    rdsTypes.ApplyMethod(change.ApplyMethod),
+				})
+			}
+
+			var err error
+			if groupChanges[start].Level == "cluster" {
+				_, err = rds.rdsClient.ModifyDBClusterParameterGroup(rds.ctx, &awsRds.ModifyDBClusterParameterGroupInput{
+					DBClusterParameterGroupName: aws.String(group),
+					Parameters:                  parameters,
+				})
+			} else {
+				_, err = rds.rdsClient.ModifyDBParameterGroup(rds.ctx, &awsRds.ModifyDBParameterGroupInput{
+					DBParameterGroupName: aws.String(group),
+					Parameters:           parameters,
+				})
+			}
+			if err != nil {
+				return fmt.Errorf("RDS: ModifyDBParameterGroup SDK call: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetInstancesNeedingReboot retourne les instances utilisant les groupes modifiés qui devront
+// redémarrer : paramètres statiques (ApplyMethod pending-reboot) ou statut pending-reboot
+func (rds *RDS) GetInstancesNeedingReboot(changes []ParameterChange) ([]string, error) {
+	modified := make(map[string]bool)
+	static := make(map[string]bool)
+	for _, change := range changes {
+		modified[change.ParameterGroup] = true
+		if change.ApplyMethod == string(rdsTypes.ApplyMethodPendingReboot) {
+			static[change.ParameterGroup] = true
+		}
+	}
+
+	// Groupes de paramètres de cluster modifiés, par cluster Aurora
+	clusterGroups := make(map[string]string)
+	for _, cluster := range rds.dbClusters.DBClusters {
+		clusterGroups[cluster.DBClusterIdentifier] = cluster.DBClusterParameterGroup
+	}
+
+	var instances []string
+	paginator := awsRds.NewDescribeDBInstancesPaginator(rds.rdsClient, &awsRds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeDBInstances SDK call: %w", err)
+		}
+
+		for _, sdkInstance := range result.DBInstances {
+			needsReboot := false
+			for _, group := range sdkInstance.DBParameterGroups {
+				name := aws.ToString(group.DBParameterGroupName)
+				if modified[name] && (static[name] || aws.ToString(group.ParameterApplyStatus) == "pending-reboot") {
+					needsReboot = true
+				}
+			}
+			if clusterGroup, ok := clusterGroups[aws.ToString(sdkInstance.DBClusterIdentifier)]; ok && static[clusterGroup] {
+				needsReboot = true
+			}
+			if needsReboot {
+				instances = append(instances, aws.ToString(sdkInstance.DBInstanceIdentifier))
+			}
+		}
+	}
+
+	return instances, nil
+}
@@ -2,14 +2,11 @@ package rds
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
-	"image/color"
 	"io"
-	"math"
+	"log/slog"
 	"os"
-	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,17 +14,17 @@ import (
 	"net/http"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
 	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
-	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
-	"gonum.org/v1/plot/vg"
-	"gonum.org/v1/plot/vg/draw"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+	"github.com/robinportigliatti/cloud_helper/internal/metrics"
+	"github.com/robinportigliatti/cloud_helper/internal/pgpass"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
 )
 
 type RDS struct {
@@ -37,10 +34,12 @@ type RDS struct {
 	rdsClient            *awsRds.Client
 	ec2Client            *ec2.Client
 	cloudwatchClient     *cloudwatch.Client
+	secretsClient        *secretsmanager.Client
 	ctx                  context.Context
 
 	// Cached data
 	dbInstances                               DescribeDBInstanceResult
+	dbClusters                                DescribeDBClustersResult
 	dbParameterGroups                         DescribeDBParametersResult
 	describeInstanceTypes                     DescribeInstanceTypes
 	validDBInstanceModificationsMessageResult ValidDBInstanceModificationsMessageResult
@@ -50,39 +49,40 @@ func (rds RDS) GetDbInstances() ([]DBInstance, error) {
 	return rds.dbInstances.DBInstances, nil
 }
 
+func (rds RDS) GetDbClusters() ([]DBCluster, error) {
+	return rds.dbClusters.DBClusters, nil
+}
+
 type DBParameter struct {
 	ParameterName  string
 	ParameterValue string
 }
 
 func (rds *RDS) Init(dbInstanceIdentifier string, profile string) error {
+	return rds.InitRegion(dbInstanceIdentifier, profile, "")
+}
+
+// InitRegion initialise la connexion dans une région donnée ; une région vide
+// conserve la région du profil AWS
+func (rds *RDS) InitRegion(dbInstanceIdentifier string, profile string, region string) error {
 	var err error
 	rds.dbInstanceIdentifier = dbInstanceIdentifier
-	rds.profile = profile
-	rds.ctx = context.Background()
-
-	// Load AWS configuration with optional profile
-	var cfgOptions []func(*config.LoadOptions) error
-	if profile != "" && profile != "none" {
-		cfgOptions = append(cfgOptions, config.WithSharedConfigProfile(profile))
-	}
-
-	rds.awsConfig, err = config.LoadDefaultConfig(rds.ctx, cfgOptions...)
+	err = rds.connect(profile, region, "")
 	if err != nil {
-		return fmt.Errorf("RDS: failed to load AWS config: %w", err)
+		return err
 	}
 
-	// Initialize AWS service clients
-	rds.rdsClient = awsRds.NewFromConfig(rds.awsConfig)
-	rds.ec2Client = ec2.NewFromConfig(rds.awsConfig)
-	rds.cloudwatchClient = cloudwatch.NewFromConfig(rds.awsConfig)
-
 	// Fetch initial data
 	rds.dbInstances, err = rds.DescribeDbInstances()
 	if err != nil {
 		return fmt.Errorf("RDS: DescribeDbInstances: %w", err)
 	}
 
+	rds.dbClusters, err = rds.DescribeDbClusters()
+	if err != nil {
+		return fmt.Errorf("RDS: DescribeDbClusters: %w", err)
+	}
+
 	err = rds.GetDBParameterGroupInformations()
 	if err != nil {
 		return fmt.Errorf("RDS: GetDBParameterGroupInformations: %w", err)
@@ -101,6 +101,25 @@ func (rds *RDS) Init(dbInstanceIdentifier string, profile string) error {
 	return nil
 }
 
+// connect charge la configuration AWS et initialise les clients des services, sans interroger RDS
+func (rds *RDS) connect(profile string, region string, roleARN string) error {
+	var err error
+	rds.profile = profile
+	rds.ctx = context.Background()
+
+	rds.awsConfig, err = loadConfig(rds.ctx, profile, region, roleARN)
+	if err != nil {
+		return err
+	}
+
+	// Initialize AWS service clients
+	rds.rdsClient = awsRds.NewFromConfig(rds.awsConfig)
+	rds.ec2Client = ec2.NewFromConfig(rds.awsConfig)
+	rds.cloudwatchClient = cloudwatch.NewFromConfig(rds.awsConfig)
+	rds.secretsClient = secretsmanager.NewFromConfig(rds.awsConfig)
+	return nil
+}
+
 func (rds RDS) GetdbInstance() DBInstance {
 	return rds.dbInstances.DBInstances[0]
 }
@@ -109,6 +128,20 @@ func (rds RDS) GetDBParameters() DescribeDBParametersResult {
 	return rds.dbParameterGroups
 }
 
+// GetDbCluster retourne le cluster Aurora de l'instance, ou nil si l'instance n'appartient à aucun cluster
+func (rds RDS) GetDbCluster() *DBCluster {
+	if len(rds.dbInstances.DBInstances) == 0 || rds.dbInstances.DBInstances[0].DBClusterIdentifier == "" {
+		return nil
+	}
+
+	for i := range rds.dbClusters.DBClusters {
+		if rds.dbClusters.DBClusters[i].DBClusterIdentifier == rds.dbInstances.DBInstances[0].DBClusterIdentifier {
+			return &rds.dbClusters.DBClusters[i]
+		}
+	}
+	return nil
+}
+
 func (rds RDS) GetInstanceType() InstanceType {
 	return rds.describeInstanceTypes.InstanceTypes[0]
 }
@@ -121,20 +154,102 @@ func (rds *RDS) DescribeDbInstances() (DescribeDBInstanceResult, error) {
 		input.DBInstanceIdentifier = aws.String(rds.dbInstanceIdentifier)
 	}
 
-	result, err := rds.rdsClient.DescribeDBInstances(rds.ctx, input)
-	if err != nil {
-		return dbInstances, fmt.Errorf("RDS: DescribeDBInstances SDK call: %w", err)
-	}
+	// Sans identifiant, toutes les instances de la région sont retournées (résultats paginés)
+	dbInstances.DBInstances = []DBInstance{}
+	paginator := awsRds.NewDescribeDBInstancesPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return dbInstances, fmt.Errorf("RDS: DescribeDBInstances SDK call: %w", err)
+		}
 
-	// Convert AWS SDK types to our internal types
-	dbInstances.DBInstances = make([]DBInstance, len(result.DBInstances))
-	for i, sdkInstance := range result.DBInstances {
-		dbInstances.DBInstances[i] = convertSDKDBInstanceToInternal(sdkInstance)
+		// Convert AWS SDK types to our internal types
+		for _, sdkInstance := range result.DBInstances {
+			dbInstances.DBInstances = append(dbInstances.DBInstances, convertSDKDBInstanceToInternal(sdkInstance))
+		}
 	}
 
 	return dbInstances, nil
 }
 
+// DescribeDbClusters récupère les clusters Aurora : tous les clusters en mode liste,
+// sinon uniquement le cluster de l'instance
+func (rds *RDS) DescribeDbClusters() (DescribeDBClustersResult, error) {
+	var dbClusters DescribeDBClustersResult
+
+	input := &awsRds.DescribeDBClustersInput{}
+	if rds.dbInstanceIdentifier != "" {
+		if len(rds.dbInstances.DBInstances) == 0 || rds.dbInstances.DBInstances[0].DBClusterIdentifier == "" {
+			return dbClusters, nil
+		}
+		input.DBClusterIdentifier = aws.String(rds.dbInstances.DBInstances[0].DBClusterIdentifier)
+	}
+
+	paginator := awsRds.NewDescribeDBClustersPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return dbClusters, fmt.Errorf("RDS: DescribeDBClusters SDK call: %w", err)
+		}
+
+		for _, sdkCluster := range result.DBClusters {
+			dbClusters.DBClusters = append(dbClusters.DBClusters, convertSDKDBClusterToInternal(sdkCluster))
+		}
+	}
+
+	return dbClusters, nil
+}
+
+// Helper function to convert AWS SDK DBCluster to internal DBCluster
+func convertSDKDBClusterToInternal(sdkCluster rdsTypes.DBCluster) DBCluster {
+	cluster := DBCluster{
+		DBClusterIdentifier:     aws.ToString(sdkCluster.DBClusterIdentifier),
+		DBClusterArn:            aws.ToString(sdkCluster.DBClusterArn),
+		Engine:                  aws.ToString(sdkCluster.Engine),
+		EngineVersion:           aws.ToString(sdkCluster.EngineVersion),
+		EngineMode:              aws.ToString(sdkCluster.EngineMode),
+		Status:                  aws.ToString(sdkCluster.Status),
+		MasterUsername:          aws.ToString(sdkCluster.MasterUsername),
+		Endpoint:                aws.ToString(sdkCluster.Endpoint),
+		ReaderEndpoint:          aws.ToString(sdkCluster.ReaderEndpoint),
+		Port:                    int(aws.ToInt32(sdkCluster.Port)),
+		MultiAZ:                 aws.ToBool(sdkCluster.MultiAZ),
+		DBClusterParameterGroup: aws.ToString(sdkCluster.DBClusterParameterGroup),
+		BackupRetentionPeriod:   int(aws.ToInt32(sdkCluster.BackupRetentionPeriod)),
+		PreferredBackupWindow:   aws.ToString(sdkCluster.PreferredBackupWindow),
+		EarliestRestorableTime:  aws.ToTime(sdkCluster.EarliestRestorableTime),
+		LatestRestorableTime:    aws.ToTime(sdkCluster.LatestRestorableTime),
+		ReadReplicaIdentifiers:  sdkCluster.ReadReplicaIdentifiers,
+		GlobalClusterIdentifier: aws.ToString(sdkCluster.GlobalClusterIdentifier),
+	}
+
+	cluster.MasterUserSecret = convertSDKMasterUserSecretToInternal(sdkCluster.MasterUserSecret)
+
+	for _, sdkMember := range sdkCluster.DBClusterMembers {
+		cluster.DBClusterMembers = append(cluster.DBClusterMembers, DBClusterMember{
+			DBInstanceIdentifier:          aws.ToString(sdkMember.DBInstanceIdentifier),
+			IsClusterWriter:               aws.ToBool(sdkMember.IsClusterWriter),
+			DBClusterParameterGroupStatus: aws.ToString(sdkMember.DBClusterParameterGroupStatus),
+			PromotionTier:                 int(aws.ToInt32(sdkMember.PromotionTier)),
+		})
+	}
+
+	return cluster
+}
+
+// Helper function to convert AWS SDK MasterUserSecret to internal MasterUserSecret
+func convertSDKMasterUserSecretToInternal(sdkSecret *rdsTypes.MasterUserSecret) *MasterUserSecret {
+	if sdkSecret == nil || sdkSecret.SecretArn == nil {
+		return nil
+	}
+
+	return &MasterUserSecret{
+		SecretArn:    aws.ToString(sdkSecret.SecretArn),
+		SecretStatus: aws.ToString(sdkSecret.SecretStatus),
+		KmsKeyID:     aws.ToString(sdkSecret.KmsKeyId),
+	}
+}
+
 // Helper function to convert AWS SDK DBInstance to internal DBInstance
 func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance {
 	instance := DBInstance{}
@@ -151,9 +266,13 @@ func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance
 	if sdkInstance.DBInstanceStatus != nil {
 		instance.DBInstanceStatus = *sdkInstance.DBInstanceStatus
 	}
+	if sdkInstance.DBClusterIdentifier != nil {
+		instance.DBClusterIdentifier = *sdkInstance.DBClusterIdentifier
+	}
 	if sdkInstance.MasterUsername != nil {
 		instance.MasterUsername = *sdkInstance.MasterUsername
 	}
+	instance.MasterUserSecret = convertSDKMasterUserSecretToInternal(sdkInstance.MasterUserSecret)
 
 	// Convert Endpoint
 	if sdkInstance.Endpoint != nil {
@@ -195,6 +314,16 @@ func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance
 		}
 	}
 
+	// Convert VpcSecurityGroups
+	instance.VpcSecurityGroups = make([]struct {
+		VpcSecurityGroupID string `json:"VpcSecurityGroupId"`
+		Status             string `json:"Status"`
+	}, len(sdkInstance.VpcSecurityGroups))
+	for i, sg := range sdkInstance.VpcSecurityGroups {
+		instance.VpcSecurityGroups[i].VpcSecurityGroupID = aws.ToString(sg.VpcSecurityGroupId)
+		instance.VpcSecurityGroups[i].Status = aws.ToString(sg.Status)
+	}
+
 	if sdkInstance.AvailabilityZone != nil {
 		instance.AvailabilityZone = *sdkInstance.AvailabilityZone
 	}
@@ -222,6 +351,9 @@ func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance
 	if sdkInstance.StorageType != nil {
 		instance.StorageType = *sdkInstance.StorageType
 	}
+	if sdkInstance.Iops != nil {
+		instance.Iops = int(*sdkInstance.Iops)
+	}
 	if sdkInstance.DbInstancePort != nil {
 		instance.DbInstancePort = int(*sdkInstance.DbInstancePort)
 	}
@@ -234,6 +366,13 @@ func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance
 	if sdkInstance.CACertificateIdentifier != nil {
 		instance.CACertificateIdentifier = *sdkInstance.CACertificateIdentifier
 	}
+	if sdkInstance.CertificateDetails != nil {
+		instance.CertificateDetails.CAIdentifier = aws.ToString(sdkInstance.CertificateDetails.CAIdentifier)
+		instance.CertificateDetails.ValidTill = aws.ToTime(sdkInstance.CertificateDetails.ValidTill)
+	}
+	if sdkInstance.PendingModifiedValues != nil {
+		instance.PendingModifiedValues.CACertificateIdentifier = aws.ToString(sdkInstance.PendingModifiedValues.CACertificateIdentifier)
+	}
 	if sdkInstance.CopyTagsToSnapshot != nil {
 		instance.CopyTagsToSnapshot = *sdkInstance.CopyTagsToSnapshot
 	}
@@ -270,6 +409,15 @@ func convertSDKDBInstanceToInternal(sdkInstance rdsTypes.DBInstance) DBInstance
 	if sdkInstance.BackupTarget != nil {
 		instance.BackupTarget = *sdkInstance.BackupTarget
 	}
+	instance.ReadReplicaDBInstanceIdentifiers = sdkInstance.ReadReplicaDBInstanceIdentifiers
+	instance.ReadReplicaSourceDBInstanceIdentifier = aws.ToString(sdkInstance.ReadReplicaSourceDBInstanceIdentifier)
+	instance.ReplicaMode = string(sdkInstance.ReplicaMode)
+	for _, tag := range sdkInstance.TagList {
+		instance.TagList = append(instance.TagList, Tag{Key: aws.ToString(tag.Key), Value: aws.ToString(tag.Value)})
+	}
+	for _, replication := range sdkInstance.DBInstanceAutomatedBackupsReplications {
+		instance.DBInstanceAutomatedBackupsReplications = append(instance.DBInstanceAutomatedBackupsReplications, aws.ToString(replication.DBInstanceAutomatedBackupsArn))
+	}
 
 	return instance
 }
@@ -351,6 +499,12 @@ func (rds *RDS) GetEC2Information() error {
 				it.MemoryInfo.SizeInMiB = int(*sdkType.MemoryInfo.SizeInMiB)
 			}
 
+			// EbsInfo - used by RecommendSizing()
+			if sdkType.EbsInfo != nil && sdkType.EbsInfo.EbsOptimizedInfo != nil {
+				it.EbsInfo.EbsOptimizedInfo.BaselineIops = int(aws.ToInt32(sdkType.EbsInfo.EbsOptimizedInfo.BaselineIops))
+				it.EbsInfo.EbsOptimizedInfo.MaximumIops = int(aws.ToInt32(sdkType.EbsInfo.EbsOptimizedInfo.MaximumIops))
+			}
+
 			rds.describeInstanceTypes.InstanceTypes[i] = it
 		}
 	}
@@ -364,56 +518,254 @@ func (rds *RDS) GetDBParameterGroupInformations() error {
 			return nil
 		}
 
-		parameterGroupName := rds.dbInstances.DBInstances[0].DBParameterGroups[0].DBParameterGroupName
+		return rds.loadParameterGroups()
+	}
+
+	return nil
+}
 
-		input := &awsRds.DescribeDBParametersInput{
-			DBParameterGroupName: aws.String(parameterGroupName),
+// loadParameterGroups charge les paramètres du groupe attaché à l'instance et, pour Aurora,
+// les fusionne avec ceux du groupe de paramètres du cluster
+func (rds *RDS) loadParameterGroups() error {
+	parameters, err := rds.DescribeDBParameters(rds.GetDBParameterGroupName())
+	if err != nil {
+		return fmt.Errorf("RDS: DescribeDBParameters: %w", err)
+	}
+	rds.dbParameterGroups = parameters
+
+	// Aurora : fusionner avec le groupe de paramètres du cluster
+	cluster := rds.GetDbCluster()
+	if cluster != nil && cluster.DBClusterParameterGroup != "" {
+		clusterParameters, err := rds.DescribeDBClusterParameters(cluster.DBClusterParameterGroup)
+		if err != nil {
+			return fmt.Errorf("RDS: DescribeDBClusterParameters: %w", err)
 		}
+		rds.dbParameterGroups = rds.dbParameterGroups.Merge(clusterParameters)
+	}
 
-		result, err := rds.rdsClient.DescribeDBParameters(rds.ctx, input)
+	return nil
+}
+
+// DescribeDBParameters récupère tous les paramètres d'un groupe de paramètres d'instance
+func (rds *RDS) DescribeDBParameters(parameterGroupName string) (DescribeDBParametersResult, error) {
+	var parameters DescribeDBParametersResult
+
+	input := &awsRds.DescribeDBParametersInput{
+		DBParameterGroupName: aws.String(parameterGroupName),
+	}
+
+	paginator := awsRds.NewDescribeDBParametersPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
 		if err != nil {
-			return fmt.Errorf("RDS: DescribeDBParameters SDK call: %w", err)
+			return parameters, fmt.Errorf("RDS: DescribeDBParameters SDK call: %w", err)
 		}
 
-		// Convert AWS SDK types to our internal types
-		rds.dbParameterGroups.Parameters = make([]Parameter, len(result.Parameters))
-		for i, sdkParam := range result.Parameters {
-			param := Parameter{}
-			if sdkParam.ParameterName != nil {
-				param.ParameterName = *sdkParam.ParameterName
-			}
-			if sdkParam.Description != nil {
-				param.Description = *sdkParam.Description
-			}
-			if sdkParam.Source != nil {
-				param.Source = *sdkParam.Source
-			}
-			if sdkParam.ApplyType != nil {
-				param.ApplyType = *sdkParam.ApplyType
-			}
-			if sdkParam.DataType != nil {
-				param.DataType = *sdkParam.DataType
-			}
-			if sdkParam.IsModifiable != nil {
-				param.IsModifiable = *sdkParam.IsModifiable
-			}
-			if sdkParam.ApplyMethod != "" {
-				param.ApplyMethod = string(sdkParam.ApplyMethod)
-			}
-			if sdkParam.ParameterValue != nil {
-				param.ParameterValue = *sdkParam.ParameterValue
-			}
-			if sdkParam.AllowedValues != nil {
-				param.AllowedValues = *sdkParam.AllowedValues
-			}
-			if sdkParam.MinimumEngineVersion != nil {
-				param.MinimumEngineVersion = *sdkParam.MinimumEngineVersion
-			}
-			rds.dbParameterGroups.Parameters[i] = param
+		for _, sdkParam := range result.Parameters {
+			parameters.Parameters = append(parameters.Parameters, convertSDKParameterToInternal(sdkParam))
 		}
 	}
 
-	return nil
+	return parameters, nil
+}
+
+// DescribeDBClusterParameters récupère tous les paramètres d'un groupe de paramètres de cluster Aurora
+func (rds *RDS) DescribeDBClusterParameters(clusterParameterGroupName string) (DescribeDBParametersResult, error) {
+	var parameters DescribeDBParametersResult
+
+	input := &awsRds.DescribeDBClusterParametersInput{
+		DBClusterParameterGroupName: aws.String(clusterParameterGroupName),
+	}
+
+	paginator := awsRds.NewDescribeDBClusterParametersPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return parameters, fmt.Errorf("RDS: DescribeDBClusterParameters SDK call: %w", err)
+		}
+
+		for _, sdkParam := range result.Parameters {
+			parameters.Parameters = append(parameters.Parameters, convertSDKParameterToInternal(sdkParam))
+		}
+	}
+
+	return parameters, nil
+}
+
+// GetDBParameterGroupFamily retourne la famille (ex: postgres16) du groupe de paramètres de l'instance
+func (rds *RDS) GetDBParameterGroupFamily() (string, error) {
+	instance := rds.GetdbInstance()
+	if len(instance.DBParameterGroups) == 0 {
+		return "", fmt.Errorf("RDS: aucun groupe de paramètres pour %s", instance.DBInstanceIdentifier)
+	}
+
+	result, err := rds.rdsClient.DescribeDBParameterGroups(rds.ctx, &awsRds.DescribeDBParameterGroupsInput{
+		DBParameterGroupName: aws.String(instance.DBParameterGroups[0].DBParameterGroupName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("RDS: DescribeDBParameterGroups SDK call: %w", err)
+	}
+
+	if len(result.DBParameterGroups) == 0 {
+		return "", fmt.Errorf("RDS: groupe de paramètres %s introuvable", instance.DBParameterGroups[0].DBParameterGroupName)
+	}
+
+	return aws.ToString(result.DBParameterGroups[0].DBParameterGroupFamily), nil
+}
+
+// GetDBClusterParameterGroupFamily retourne la famille (ex: aurora-postgresql16) du groupe de paramètres
+// du cluster Aurora de l'instance
+func (rds *RDS) GetDBClusterParameterGroupFamily() (string, error) {
+	cluster := rds.GetDbCluster()
+	if cluster == nil || cluster.DBClusterParameterGroup == "" {
+		return "", fmt.Errorf("RDS: aucun groupe de paramètres de cluster pour %s", rds.GetdbInstance().DBInstanceIdentifier)
+	}
+
+	result, err := rds.rdsClient.DescribeDBClusterParameterGroups(rds.ctx, &awsRds.DescribeDBClusterParameterGroupsInput{
+		DBClusterParameterGroupName: aws.String(cluster.DBClusterParameterGroup),
+	})
+	if err != nil {
+		return "", fmt.Errorf("RDS: DescribeDBClusterParameterGroups SDK call: %w", err)
+	}
+
+	if len(result.DBClusterParameterGroups) == 0 {
+		return "", fmt.Errorf("RDS: groupe de paramètres de cluster %s introuvable", cluster.DBClusterParameterGroup)
+	}
+
+	return aws.ToString(result.DBClusterParameterGroups[0].DBParameterGroupFamily), nil
+}
+
+// DescribeEngineDefaultParameters récupère les paramètres par défaut d'une famille de groupes de paramètres
+func (rds *RDS) DescribeEngineDefaultParameters(family string) (DescribeDBParametersResult, error) {
+	var parameters DescribeDBParametersResult
+
+	input := &awsRds.DescribeEngineDefaultParametersInput{
+		DBParameterGroupFamily: aws.String(family),
+	}
+
+	paginator := awsRds.NewDescribeEngineDefaultParametersPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return parameters, fmt.Errorf("RDS: DescribeEngineDefaultParameters SDK call: %w", err)
+		}
+
+		if result.EngineDefaults == nil {
+			continue
+		}
+		for _, sdkParam := range result.EngineDefaults.Parameters {
+			parameters.Parameters = append(parameters.Parameters, convertSDKParameterToInternal(sdkParam))
+		}
+	}
+
+	return parameters, nil
+}
+
+// DescribeEngineDefaultClusterParameters récupère les paramètres par défaut des groupes de paramètres
+// de cluster (Aurora) d'une famille
+func (rds *RDS) DescribeEngineDefaultClusterParameters(family string) (DescribeDBParametersResult, error) {
+	var parameters DescribeDBParametersResult
+
+	input := &awsRds.DescribeEngineDefaultClusterParametersInput{
+		DBParameterGroupFamily: aws.String(family),
+	}
+	for {
+		result, err := rds.rdsClient.DescribeEngineDefaultClusterParameters(rds.ctx, input)
+		if err != nil {
+			return parameters, fmt.Errorf("RDS: DescribeEngineDefaultClusterParameters SDK call: %w", err)
+		}
+		if result.EngineDefaults == nil {
+			break
+		}
+
+		for _, sdkParam := range result.EngineDefaults.Parameters {
+			parameters.Parameters = append(parameters.Parameters, convertSDKParameterToInternal(sdkParam))
+		}
+		if aws.ToString(result.EngineDefaults.Marker) == "" {
+			break
+		}
+		input.Marker = result.EngineDefaults.Marker
+	}
+
+	return parameters, nil
+}
+
+// GetNonDefaultParameters retourne les paramètres personnalisés (Source user) dont la valeur
+// diffère réellement de la valeur par défaut de la famille. Sur Aurora, les paramètres du cluster
+// sont comparés aux valeurs par défaut des groupes de paramètres de cluster
+func (rds *RDS) GetNonDefaultParameters() ([]Parameter, error) {
+	family, err := rds.GetDBParameterGroupFamily()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: GetDBParameterGroupFamily: %w", err)
+	}
+
+	defaults, err := rds.DescribeEngineDefaultParameters(family)
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeEngineDefaultParameters: %w", err)
+	}
+
+	var clusterDefaults DescribeDBParametersResult
+	if rds.GetDbCluster() != nil {
+		clusterDefaults, err = rds.DescribeEngineDefaultClusterParameters(family)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeEngineDefaultClusterParameters: %w", err)
+		}
+	}
+
+	var parameters []Parameter
+	for _, parameter := range rds.dbParameterGroups.Parameters {
+		if parameter.Source != "user" || parameter.ParameterValue == "" {
+			continue
+		}
+
+		levelDefaults := defaults
+		if parameter.Level == "cluster" {
+			levelDefaults = clusterDefaults
+		}
+		defaultValue, err := levelDefaults.GetParameterValueByParameterName(parameter.ParameterName)
+		if err == nil && pgsettings.Equivalent(parameter.ParameterValue, defaultValue, "") {
+			continue
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
+}
+
+// Helper function to convert AWS SDK Parameter to internal Parameter
+func convertSDKParameterToInternal(sdkParam rdsTypes.Parameter) Parameter {
+	param := Parameter{}
+	if sdkParam.ParameterName != nil {
+		param.ParameterName = *sdkParam.ParameterName
+	}
+	if sdkParam.Description != nil {
+		param.Description = *sdkParam.Description
+	}
+	if sdkParam.Source != nil {
+		param.Source = *sdkParam.Source
+	}
+	if sdkParam.ApplyType != nil {
+		param.ApplyType = *sdkParam.ApplyType
+	}
+	if sdkParam.DataType != nil {
+		param.DataType = *sdkParam.DataType
+	}
+	if sdkParam.IsModifiable != nil {
+		param.IsModifiable = *sdkParam.IsModifiable
+	}
+	if sdkParam.ApplyMethod != "" {
+		param.ApplyMethod = string(sdkParam.ApplyMethod)
+	}
+	if sdkParam.ParameterValue != nil {
+		param.ParameterValue = *sdkParam.ParameterValue
+	}
+	if sdkParam.AllowedValues != nil {
+		param.AllowedValues = *sdkParam.AllowedValues
+	}
+	if sdkParam.MinimumEngineVersion != nil {
+		param.MinimumEngineVersion = *sdkParam.MinimumEngineVersion
+	}
+	return param
 }
 
 func (rds *RDS) DescribeValidDBInstanceModifications() error {
@@ -606,7 +958,9 @@ func (rds RDS) DownloadLogs(start string, directory string, end string) error {
 	return nil
 }
 
-func (rds RDS) DownloadMetrics(start string, end string, directory string) error {
+// DownloadMetrics télécharge les métriques CloudWatch de l'instance au format CSV, PNG et HTML.
+// Les repères (événements de l'instance) sont tracés sur les graphes et listés dans la page HTML
+func (rds RDS) DownloadMetrics(start string, end string, directory string, markers ...metrics.Marker) error {
 	// List CloudWatch metrics using SDK
 	listInput := &cloudwatch.ListMetricsInput{
 		Namespace: aws.String("AWS/RDS"),
@@ -618,8 +972,8 @@ func (rds RDS) DownloadMetrics(start string, end string, directory string) error
 	}
 
 	// Convert to internal type
-	metrics := &ListMetricsResult{}
-	metrics.Metrics = make([]struct {
+	metricsList := &ListMetricsResult{}
+	metricsList.Metrics = make([]struct {
 		Namespace  string `json:"Namespace"`
 		MetricName string `json:"MetricName"`
 		Dimensions []struct {
@@ -630,21 +984,21 @@ func (rds RDS) DownloadMetrics(start string, end string, directory string) error
 
 	for i, sdkMetric := range listResult.Metrics {
 		if sdkMetric.Namespace != nil {
-			metrics.Metrics[i].Namespace = *sdkMetric.Namespace
+			metricsList.Metrics[i].Namespace = *sdkMetric.Namespace
 		}
 		if sdkMetric.MetricName != nil {
-			metrics.Metrics[i].MetricName = *sdkMetric.MetricName
+			metricsList.Metrics[i].MetricName = *sdkMetric.MetricName
 		}
-		metrics.Metrics[i].Dimensions = make([]struct {
+		metricsList.Metrics[i].Dimensions = make([]struct {
 			Name  string `json:"Name"`
 			Value string `json:"Value"`
 		}, len(sdkMetric.Dimensions))
 		for j, dim := range sdkMetric.Dimensions {
 			if dim.Name != nil {
-				metrics.Metrics[i].Dimensions[j].Name = *dim.Name
+				metricsList.Metrics[i].Dimensions[j].Name = *dim.Name
 			}
 			if dim.Value != nil {
-				metrics.Metrics[i].Dimensions[j].Value = *dim.Value
+				metricsList.Metrics[i].Dimensions[j].Value = *dim.Value
 			}
 		}
 	}
@@ -657,11 +1011,11 @@ func (rds RDS) DownloadMetrics(start string, end string, directory string) error
 		endTime = time.Now()
 		startTime = endTime.AddDate(0, 0, -1)
 	} else {
-		startTime, err = time.Parse("2006/01/02 15:04:05.000000000", start)
+		startTime, err = time.Parse("2006/01/02 15:04:00", start)
 		if err != nil {
 			return fmt.Errorf("time.Parse start: %w", err)
 		}
-		endTime, err = time.Parse("2006/01/02 15:04:05.000000000", end)
+		endTime, err = time.Parse("2006/01/02 15:04:00", end)
 		if err != nil {
 			return fmt.Errorf("time.Parse end: %w", err)
 		}
@@ -679,11 +1033,11 @@ func (rds RDS) DownloadMetrics(start string, end string, directory string) error
 		return fmt.Errorf("os.MkdirAll: %w", err)
 	}
 
-	for i := 0; i < len(metrics.Metrics); i++ {
-		metricName := metrics.Metrics[i].MetricName
-		for j := 0; j < len(metrics.Metrics[i].Dimensions); j++ {
-			dimensionName := metrics.Metrics[i].Dimensions[j].Name
-			dimensionValue := metrics.Metrics[i].Dimensions[j].Value
+	for i := 0; i < len(metricsList.Metrics); i++ {
+		metricName := metricsList.Metrics[i].MetricName
+		for j := 0; j < len(metricsList.Metrics[i].Dimensions); j++ {
+			dimensionName := metricsList.Metrics[i].Dimensions[j].Name
+			dimensionValue := metricsList.Metrics[i].Dimensions[j].Value
 			if dimensionName != "DBInstanceIdentifier" || dimensionValue != rds.dbInstanceIdentifier {
 				continue
 			}
@@ -771,61 +1125,32 @@ func (rds RDS) DownloadMetrics(start string, end string, directory string) error
 				}
 
 				filePath := fmt.Sprintf("%s/%s.%s.%s.%s.csv", metricPath, dimensionName, dimensionValue, metricName, statisticNames[k])
-				var file, err2 = os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
-				if err2 != nil {
-					return fmt.Errorf("os.OpenFile: %w", err2)
-				}
-
-				title := fmt.Sprintf("\"%s\";\"%s\";\"%s\";\r\n", "Timestamp", statistics[k], "Unit")
-				_, err = file.WriteString(title)
-				if err != nil {
-					return fmt.Errorf("file.WriteString: %w", err)
-				}
-
+				points := make([]metrics.Point, len(datapoints.Datapoints))
 				for l := 0; l < len(datapoints.Datapoints); l++ {
-					data := fmt.Sprintf("\"%s\";\"%s\";\"%s\";\r\n", datapoints.Datapoints[l].Timestamp, datapoints.Datapoints[l].GetField(statisticNames[k]), datapoints.Datapoints[l].Unit)
-					_, err := file.WriteString(data)
-					if err != nil {
-						return fmt.Errorf("file.WriteString: %w", err)
+					points[l] = metrics.Point{
+						Timestamp: datapoints.Datapoints[l].Timestamp,
+						Value:     datapoints.Datapoints[l].GetField(statisticNames[k]),
+						Unit:      datapoints.Datapoints[l].Unit,
 					}
-
 				}
 
-				func() { _ = file.Close() }()
-				file, err = os.Open(filePath)
+				err = metrics.WriteCSV(filePath, string(statistics[k]), points)
 				if err != nil {
-					return fmt.Errorf("os.Open: %w", err)
+					return fmt.Errorf("WriteCSV: %w", err)
 				}
-				func() { _ = file.Close() }()
-				file, err = os.Open(filePath)
-				if err != nil {
-					return fmt.Errorf("os.Open: %w", err)
-				}
-				defer func() { _ = file.Close() }()
-
-				data, yAxisLabel, err := readCSV(file)
-				if err != nil {
-					return fmt.Errorf("readCSV: %w", err)
-				}
-
-				baseFilename := filepath.Base(filePath)
-				outputFilename := strings.Replace(baseFilename, ".csv", ".png", 1)
-				outputDir := filepath.Dir(filePath)
-				outputPath := filepath.Join(outputDir, outputFilename)
-
-				err = createPNGGraph(data, yAxisLabel, outputPath)
 
+				outputFilename, err := metrics.CreatePNGFromCSV(filePath, markers...)
 				if err != nil {
-					return fmt.Errorf("createPNGGraph: %w", err)
+					return fmt.Errorf("CreatePNGFromCSV: %w", err)
 				}
 
 				pngFiles = append(pngFiles, outputFilename)
 			}
 
 			// Création du fichier HTML
-			err = createMetricsHTML(pngFiles, rds.dbInstanceIdentifier)
+			err = metrics.CreateMetricsHTML(pngFiles, rds.dbInstanceIdentifier, markers...)
 			if err != nil {
-				return fmt.Errorf("createMetricsHTML: %w", err)
+				return fmt.Errorf("CreateMetricsHTML: %w", err)
 			}
 		}
 	}
@@ -905,21 +1230,59 @@ func (rds RDS) Free_m() (string, error) {
 	return result, nil
 }
 
+// GenPgPass retourne les lignes .pgpass des instances chargées avec le mot de passe maître
+// de Secrets Manager. Une instance sans secret géré par RDS garde une ligne dont le mot de passe
+// est à compléter ; les erreurs de lecture des secrets sont regroupées sans interrompre les autres instances
 func (rds RDS) GenPgPass() (string, error) {
-	// FIXME : needs improvement
-	// FIXME : il faudrait faire une commande qui récupère le endpoint et le port directement
-	str := fmt.Sprintf("%s:%d:%s:%s", rds.dbInstances.DBInstances[0].Endpoint.Address, rds.dbInstances.DBInstances[0].Endpoint.Port, "postgres", "<TODO>")
-	return str, nil
+	var lines []string
+	var errs []error
+	for _, instance := range rds.dbInstances.DBInstances {
+		entry := pgpass.Entry{
+			Hostname: instance.Endpoint.Address,
+			Port:     instance.Endpoint.Port,
+			Database: "postgres",
+			Username: instance.MasterUsername,
+			Password: pgPassPlaceholder,
+		}
+
+		credentials, err := rds.GetMasterUserCredentials(instance)
+		switch {
+		case errors.Is(err, ErrNoMasterUserSecret):
+			slog.Warn("Aucun secret géré par RDS, mot de passe à compléter",
+				slog.String("instance", instance.DBInstanceIdentifier),
+			)
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err))
+			continue
+		default:
+			entry.Username = credentials.Username
+			entry.Password = credentials.Password
+		}
+		lines = append(lines, entry.String())
+	}
+	return strings.Join(lines, "\n"), errors.Join(errs...)
 }
+
 func (rds RDS) GenPostgreSQLConf() (string, error) {
+	// Aurora : indiquer le niveau (cluster ou instance) de chaque valeur
+	withLevel := rds.GetDbCluster() != nil
+
 	var result strings.Builder
-	_, err := result.WriteString("ParameterName;ParameterValue\n")
+	header := "ParameterName;ParameterValue\n"
+	if withLevel {
+		header = "ParameterName;ParameterValue;Level\n"
+	}
+	_, err := result.WriteString(header)
 	if err != nil {
 		return "", fmt.Errorf("WriteString: %w", err)
 	}
 	for i := 0; i < len(rds.dbParameterGroups.Parameters); i++ {
-		if rds.dbParameterGroups.Parameters[i].ParameterValue != "" {
-			str := fmt.Sprintf("%s;%s\n", rds.dbParameterGroups.Parameters[i].ParameterName, rds.dbParameterGroups.Parameters[i].ParameterValue)
+		parameter := rds.dbParameterGroups.Parameters[i]
+		if parameter.ParameterValue != "" {
+			str := fmt.Sprintf("%s;%s\n", parameter.ParameterName, parameter.ParameterValue)
+			if withLevel {
+				str = fmt.Sprintf("%s;%s;%s\n", parameter.ParameterName, parameter.ParameterValue, parameter.Level)
+			}
 			_, err := result.WriteString(str)
 			if err != nil {
 				return "", fmt.Errorf("WriteString: %w", err)
@@ -938,335 +1301,31 @@ func (rds RDS) GetParameterValueByParameterName(parameterName string) (string, e
 	return value, nil
 }
 
-func (rds RDS) CheckParameter(parameterName string, parameterValue string) error {
-	value, err := rds.GetParameterValueByParameterName(parameterName)
-	if err != nil {
-		return fmt.Errorf("GetParameterValueByParameterName: %w", err)
-	}
-	str := ""
-	if value != parameterValue {
-		str = fmt.Sprintf("%s should be at %s", parameterName, parameterValue)
-	}
-
-	if value == parameterValue {
-		str = fmt.Sprintf("%s: OK", parameterName)
-	}
-
-	fmt.Println(str)
-
-	return nil
-}
-
-func (rds RDS) CheckPgbadger() error {
-	// FIXME : needs improvement
-	err := rds.CheckParameter("log_connections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_disconnections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_rotation_size", "1GO")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("lc_messages", "C")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_line_prefix", "%m [%p]: [%l-1] xact=%x,user=%u,db=%d,client=%h, app=%a ")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_lock_waits", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_temp_files", "0")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_autovacuum_min_duration", "0")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_min_duration_statement", "0")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_duration", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_checkpoints", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("log_statement", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-	err = rds.CheckParameter("track_io_timing", "on")
-	if err != nil {
-		return fmt.Errorf("CheckParameter: %w", err)
-	}
-
-	_, err = rds.dbParameterGroups.GetParameterValueByParameterName("track_activity_query_size")
+func (rds RDS) GetParameterByParameterName(parameterName string) (Parameter, error) {
+	parameter, err := rds.dbParameterGroups.GetParameterByParameterName(parameterName)
 	if err != nil {
-		return fmt.Errorf("GetParameterValueByParameterName: %w", err)
+		return Parameter{}, fmt.Errorf("GetParameterByParameterName: %w", err)
 	}
 
-	return nil
+	return parameter, nil
 }
 
-// Creating graphs
-func readCSV(file io.Reader) (plotter.XYs, string, error) {
-	reader := csv.NewReader(file)
-	reader.Comma = ';'
-	reader.TrimLeadingSpace = true
-
-	lines, err := reader.ReadAll()
-	if err != nil {
-		return nil, "", err
-	}
-
-	yAxisLabel := lines[0][1]
-
-	// Trier les lignes en fonction de la colonne Timestamp
-	sort.Slice(lines[1:], func(i, j int) bool {
-		return lines[1+i][0] < lines[1+j][0]
+// Check évalue un profil de vérification embarqué sur les paramètres de l'instance.
+// Un paramètre sans valeur dans le groupe de paramètres prend la valeur par défaut du moteur
+func (rds RDS) Check(profile string) ([]check.Result, error) {
+	results, err := check.Run(profile, "rds", func(name string) (string, bool) {
+		value, err := rds.GetParameterValueByParameterName(name)
+		return value, err == nil && value != ""
 	})
-
-	layout := "2006-01-02 15:04:05 -0700 MST"
-
-	interval := 5 * time.Minute
-
-	avgData := make(map[int64]float64)
-	avgCount := make(map[int64]int)
-
-	for _, line := range lines[1:] {
-		t, err := time.Parse(layout, line[0])
-		if err != nil {
-			return nil, "", err
-		}
-		y, err := strconv.ParseFloat(strings.TrimSpace(line[1]), 64)
-		if err != nil {
-			return nil, "", err
-		}
-
-		intervalStart := t.Truncate(interval).Unix()
-
-		avgData[intervalStart] += y
-		avgCount[intervalStart]++
-	}
-
-	data := make(plotter.XYs, len(avgData))
-	i := 0
-	for k, v := range avgData {
-		data[i].X = float64(k)
-		data[i].Y = v / float64(avgCount[k])
-		i++
-	}
-
-	sort.Slice(data, func(i, j int) bool {
-		return data[i].X < data[j].X
-	})
-
-	return data, yAxisLabel, nil
-}
-
-func writeHTML(file *os.File, content string, step string) error {
-	if _, err := file.WriteString(content); err != nil {
-		return fmt.Errorf("file.WriteString: %w", err)
-	}
-
-	return nil
-}
-
-func createMetricsHTML(outputFilenames []string, instanceIdentifier string) error {
-	htmlFile, err := os.Create(fmt.Sprintf("%s.html", instanceIdentifier))
 	if err != nil {
-		return err
-	}
-	defer func() { _ = htmlFile.Close() }()
-
-	// Écrire l'en-tête HTML
-	err = writeHTML(htmlFile, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n", "début HTML")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<meta charset=\"UTF-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1, shrink-to-fit=no\">\n", "meta tags")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<link rel=\"stylesheet\" href=\"https://stackpath.bootstrapcdn.com/bootstrap/4.3.1/css/bootstrap.min.css\" integrity=\"sha384-ggOyR0iXCbMQv3Xipma34MD+dH/1fQ784/j6cY/iJTQUOhcWr7x9JvoRxT2MZw1T\" crossorigin=\"anonymous\">\n", "Bootstrap CSS")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<title>Metrics Viewer</title>\n</head>\n<body>\n", "titre HTML")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<div class=\"container mt-4\">\n<h2>Metrics</h2>\n", "conteneur principal")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	// Parcourir les catégories de métriques
-	categories := make(map[string]bool)
-	for _, filename := range outputFilenames {
-		category := getCategory(filename)
-		categories[category] = true
-	}
-
-	// Générer le contenu pour chaque catégorie
-	for category := range categories {
-		err = writeHTML(htmlFile, fmt.Sprintf("<div class=\"mt-3\" id=\"%s\">\n", category), "début catégorie")
-		if err != nil {
-			return fmt.Errorf("writeHTML: %w", err)
-		}
-
-		err = writeHTML(htmlFile, "<div class=\"card card-body\">\n", "conteneur carte")
-		if err != nil {
-			return fmt.Errorf("writeHTML: %w", err)
-		}
-
-		err = writeHTML(htmlFile, fmt.Sprintf("<h5 class=\"card-title\">%s</h5>\n", getCategoryTitle(category)), "titre catégorie")
-		if err != nil {
-			return fmt.Errorf("writeHTML: %w", err)
-		}
-
-		// Afficher toutes les images pour la catégorie
-		for _, filename := range outputFilenames {
-			if strings.Contains(filename, "Average") {
-				err = writeHTML(htmlFile, fmt.Sprintf("<img src=\"%s\" class=\"img-fluid\" alt=\"%s\">\n", filename, filepath.Base(filename)), "image catégorie")
-				if err != nil {
-					return fmt.Errorf("writeHTML: %w", err)
-				}
-			}
-		}
-
-		err = writeHTML(htmlFile, "</div>\n</div>\n", "fermeture div catégorie")
-		if err != nil {
-			return fmt.Errorf("writeHTML: %w", err)
-		}
-	}
-
-	// Bouton Collapse pour les autres metrics
-	err = writeHTML(htmlFile, "<button class=\"btn btn-secondary mt-3\" type=\"button\" data-toggle=\"collapse\" data-target=\"#otherMetrics\" aria-expanded=\"false\" aria-controls=\"otherMetrics\">\n", "bouton collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
+		return nil, fmt.Errorf("check: Run: %w", err)
 	}
-
-	err = writeHTML(htmlFile, "Afficher les autres Metrics\n", "texte bouton collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "</button>\n", "fermeture bouton collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	// Contenu collapse pour les autres Metrics
-	err = writeHTML(htmlFile, "<div class=\"collapse mt-3\" id=\"otherMetrics\">\n", "début collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<div class=\"card card-body\">\n", "conteneur collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	// Afficher toutes les images ici
-	for _, filename := range outputFilenames {
-		err = writeHTML(htmlFile, fmt.Sprintf("<img src=\"%s\" class=\"img-fluid\" alt=\"%s\">\n", filename, filepath.Base(filename)), "image collapse")
-		if err != nil {
-			return fmt.Errorf("writeHTML: %w", err)
-		}
-	}
-
-	err = writeHTML(htmlFile, "</div>\n</div>\n", "fermeture collapse")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	// Fermer le corps et l'HTML
-	err = writeHTML(htmlFile, "<script src=\"https://code.jquery.com/jquery-3.3.1.slim.min.js\" integrity=\"sha384-q8i/X+965DzO0rT7abK41JStQIAqVgRVzpbzo5smXKp4YfRvH+8abtTE1Pi6jizo\" crossorigin=\"anonymous\"></script>\n", "script jQuery")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<script src=\"https://cdn.jsdelivr.net/npm/@popperjs/core@2.10.2/dist/umd/popper.min.js\" integrity=\"sha384-ggOyR0iXCbMQv3Xipma34MD+dH/1fQ784/j6cY/iJTQUOhcWr7x9JvoRxT2MZw1T\" crossorigin=\"anonymous\"></script>\n", "script Popper.js")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "<script src=\"https://stackpath.bootstrapcdn.com/bootstrap/4.3.1/js/bootstrap.min.js\" integrity=\"sha384-JjSmVgyd0p3pXB1rRibZUAYoIIy6OrQ6VrjIEaFf/nJGzIxFDsf4x0xIM+B07jRM\" crossorigin=\"anonymous\"></script>\n", "script Bootstrap")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	err = writeHTML(htmlFile, "</body>\n</html>\n", "fermeture HTML")
-	if err != nil {
-		return fmt.Errorf("writeHTML: %w", err)
-	}
-
-	return nil
-}
-
-// getCategory extrait la catégorie à partir du nom du fichier
-func getCategory(filename string) string {
-	parts := strings.Split(filename, "/")
-	if len(parts) >= 4 {
-		return parts[3]
-	}
-	return ""
+	return results, nil
 }
 
-// getCategoryTitle génère un titre lisible à partir de la catégorie
-func getCategoryTitle(category string) string {
-	// Vous pouvez personnaliser cette fonction pour obtenir un titre plus convivial
-	// En fonction de la catégorie fournie
-	return category + " Metrics"
-}
-
-func createPNGGraph(data plotter.XYs, yAxisLabel, outputFilename string) error {
-	p := plot.New()
-
-	baseFilename := filepath.Base(outputFilename)
-	p.Title.Text = "Graphe des données CSV : " + baseFilename
-	p.X.Label.Text = "Timestamp"
-	p.Y.Label.Text = yAxisLabel
-
-	plotter.DefaultGlyphStyle.Radius = vg.Points(2)
-
-	line, err := plotter.NewLine(data)
-	if err != nil {
-		return err
-	}
-	//  points.Shape = draw.CrossGlyph{}
-	line.Color = color.RGBA{R: 255, B: 255, A: 255}
-
-	p.Add(line)
-
-	p.X.Tick.Marker = plot.TimeTicks{Format: "2006-01-02\n15:04:05"}
-	p.X.Tick.Label.Rotation = -math.Pi / 2
-	p.X.Tick.Label.XAlign = draw.XRight
-	p.X.Tick.Label.YAlign = draw.YCenter
-
-	err = p.Save(10*vg.Inch, 5*vg.Inch, outputFilename)
-	if err != nil {
-		return err
-	}
-
-	return nil
+// CheckPgbadger vérifie les paramètres nécessaires à pgbadger
+func (rds RDS) CheckPgbadger() ([]check.Result, error) {
+	return rds.Check("pgbadger")
 }
 
 func (rds RDS) GetAllParameterNames() ([]string, error) {
@@ -0,0 +1,66 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// loadConfig charge la configuration AWS du profil dans une région donnée (vide : région du profil).
+// Avec un rôle IAM, les identifiants du profil servent à assumer ce rôle (AssumeRole), pour accéder à un autre compte
+func loadConfig(ctx context.Context, profile string, region string, roleARN string) (aws.Config, error) {
+	var cfgOptions []func(*config.LoadOptions) error
+	if profile != "" && profile != "none" {
+		cfgOptions = append(cfgOptions, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		cfgOptions = append(cfgOptions, config.WithRegion(region))
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(ctx, cfgOptions...)
+	if err != nil {
+		return awsConfig, fmt.Errorf("RDS: failed to load AWS config: %w", err)
+	}
+
+	if roleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsConfig), roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "cloud_helper"
+		})
+		awsConfig.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return awsConfig, nil
+}
+
+// ListRegions retourne les régions activées pour le compte du profil AWS,
+// ou pour le compte du rôle IAM assumé s'il est renseigné
+func ListRegions(profile string, roleARN string) ([]string, error) {
+	ctx := context.Background()
+
+	awsConfig, err := loadConfig(ctx, profile, "", roleARN)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := ec2.NewFromConfig(awsConfig).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("EC2: DescribeRegions SDK call: %w", err)
+	}
+
+	regions := make([]string, 0, len(result.Regions))
+	for _, region := range result.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// GetRegion retourne la région de la connexion
+func (rds RDS) GetRegion() string {
+	return rds.awsConfig.Region
+}
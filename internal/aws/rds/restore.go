@@ -0,0 +1,124 @@
+package rds
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// Statuts d'instance indiquant l'échec d'une restauration
+var failedRestoreStatuses = map[string]bool{
+	"failed":                              true,
+	"incompatible-restore":                true,
+	"incompatible-network":                true,
+	"incompatible-parameters":             true,
+	"inaccessible-encryption-credentials": true,
+}
+
+// RestoreToPointInTime crée une instance à partir de l'instance source, à l'instant demandé ou au dernier
+// instant restaurable. Le clone reprend le réseau, les groupes de sécurité et le groupe de paramètres de la
+// source, sans Multi-AZ ni protection contre la suppression
+func (rds RDS) RestoreToPointInTime(target string, restoreTime *time.Time, tags map[string]string, instanceClass string) error {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return fmt.Errorf("no instance found")
+	}
+	source := rds.dbInstances.DBInstances[0]
+	if source.DBClusterIdentifier != "" {
+		return fmt.Errorf("%s appartient au cluster %s, la restauration Aurora se fait au niveau du cluster", source.DBInstanceIdentifier, source.DBClusterIdentifier)
+	}
+
+	input := &awsRds.RestoreDBInstanceToPointInTimeInput{
+		SourceDBInstanceIdentifier:      aws.String(source.DBInstanceIdentifier),
+		TargetDBInstanceIdentifier:      aws.String(target),
+		MultiAZ:                         aws.Bool(false),
+		DeletionProtection:              aws.Bool(false),
+		PubliclyAccessible:              aws.Bool(source.PubliclyAccessible),
+		EnableIAMDatabaseAuthentication: aws.Bool(source.IAMDatabaseAuthenticationEnabled),
+	}
+	if restoreTime != nil {
+		input.RestoreTime = restoreTime
+	} else {
+		input.UseLatestRestorableTime = aws.Bool(true)
+	}
+	if instanceClass != "" {
+		input.DBInstanceClass = aws.String(instanceClass)
+	}
+	if source.DBSubnetGroup.DBSubnetGroupName != "" {
+		input.DBSubnetGroupName = aws.String(source.DBSubnetGroup.DBSubnetGroupName)
+	}
+	for _, securityGroup := range source.VpcSecurityGroups {
+		input.VpcSecurityGroupIds = append(input.VpcSecurityGroupIds, securityGroup.VpcSecurityGroupID)
+	}
+	if len(source.DBParameterGroups) > 0 {
+		input.DBParameterGroupName = aws.String(source.DBParameterGroups[0].DBParameterGroupName)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		input.Tags = append(input.Tags, rdsTypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	_, err := rds.rdsClient.RestoreDBInstanceToPointInTime(rds.ctx, input)
+	if err != nil {
+		return fmt.Errorf("RDS: RestoreDBInstanceToPointInTime SDK call: %w", err)
+	}
+	return nil
+}
+
+// DBInstanceStatus retourne l'état d'une instance, si elle est disponible, et une erreur si la restauration a échoué
+func (rds RDS) DBInstanceStatus(dbInstanceIdentifier string) (string, bool, error) {
+	result, err := rds.rdsClient.DescribeDBInstances(rds.ctx, &awsRds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbInstanceIdentifier),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("RDS: DescribeDBInstances SDK call: %w", err)
+	}
+	if len(result.DBInstances) == 0 {
+		return "", false, fmt.Errorf("instance %s introuvable", dbInstanceIdentifier)
+	}
+
+	status := aws.ToString(result.DBInstances[0].DBInstanceStatus)
+	if failedRestoreStatuses[status] {
+		return status, false, fmt.Errorf("instance %s en état %s", dbInstanceIdentifier, status)
+	}
+	return status, status == "available", nil
+}
+
+// DeleteDBInstance supprime une instance sans snapshot final ni sauvegardes automatiques conservées
+func (rds RDS) DeleteDBInstance(dbInstanceIdentifier string) error {
+	_, err := rds.rdsClient.DeleteDBInstance(rds.ctx, &awsRds.DeleteDBInstanceInput{
+		DBInstanceIdentifier:   aws.String(dbInstanceIdentifier),
+		SkipFinalSnapshot:      aws.Bool(true),
+		DeleteAutomatedBackups: aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("RDS: DeleteDBInstance SDK call: %w", err)
+	}
+	return nil
+}
+
+// DBInstanceTags retourne les étiquettes d'une instance
+func (rds RDS) DBInstanceTags(dbInstanceIdentifier string) (map[string]string, error) {
+	result, err := rds.rdsClient.DescribeDBInstances(rds.ctx, &awsRds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbInstanceIdentifier),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDBInstances SDK call: %w", err)
+	}
+
+	tags := make(map[string]string)
+	for _, instance := range result.DBInstances {
+		for _, tag := range instance.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,133 @@
+package rds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/robinportigliatti/cloud_helper/internal/pgpass"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// ErrNoMasterUserSecret indique que l'instance n'utilise pas ManageMasterUserPassword
+var ErrNoMasterUserSecret = errors.New("ManageMasterUserPassword n'est pas activé")
+
+// pgPassPlaceholder remplace le mot de passe des instances sans secret géré par RDS
+const pgPassPlaceholder = "<password>"
+
+// MasterUserCredentials représente le contenu du secret géré par RDS (ManageMasterUserPassword)
+type MasterUserCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// GetMasterUserSecret retourne le secret du mot de passe maître de l'instance,
+// ou celui de son cluster pour une instance Aurora
+func (rds RDS) GetMasterUserSecret(instance DBInstance) *MasterUserSecret {
+	if instance.MasterUserSecret != nil {
+		return instance.MasterUserSecret
+	}
+
+	if instance.DBClusterIdentifier != "" {
+		for _, cluster := range rds.dbClusters.DBClusters {
+			if cluster.DBClusterIdentifier == instance.DBClusterIdentifier {
+				return cluster.MasterUserSecret
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetMasterUserCredentials récupère les identifiants maîtres de l'instance depuis Secrets Manager
+func (rds RDS) GetMasterUserCredentials(instance DBInstance) (MasterUserCredentials, error) {
+	var credentials MasterUserCredentials
+
+	secret := rds.GetMasterUserSecret(instance)
+	if secret == nil {
+		return credentials, fmt.Errorf("RDS: %s: %w", instance.DBInstanceIdentifier, ErrNoMasterUserSecret)
+	}
+
+	result, err := rds.secretsClient.GetSecretValue(rds.ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secret.SecretArn),
+	})
+	if err != nil {
+		return credentials, fmt.Errorf("RDS: GetSecretValue SDK call: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(aws.ToString(result.SecretString)), &credentials)
+	if err != nil {
+		return credentials, fmt.Errorf("RDS: Unmarshal secret: %w", err)
+	}
+
+	if credentials.Username == "" {
+		credentials.Username = instance.MasterUsername
+	}
+
+	slog.Debug("Secret récupéré",
+		slog.String("instance", instance.DBInstanceIdentifier),
+		slog.String("secret", secret.SecretArn),
+		slog.String("password", pgpass.Mask(credentials.Password)),
+	)
+
+	return credentials, nil
+}
+
+// GetPgPassEntries retourne les entrées .pgpass de toutes les instances chargées.
+// Les instances sans secret géré par RDS sont ignorées ; une instance dont le secret ne peut pas être lu
+// n'interrompt pas les autres : les entrées obtenues sont retournées avec les erreurs regroupées
+func (rds RDS) GetPgPassEntries(database string) ([]pgpass.Entry, error) {
+	var entries []pgpass.Entry
+	var errs []error
+	for _, instance := range rds.dbInstances.DBInstances {
+		if rds.GetMasterUserSecret(instance) == nil {
+			slog.Warn("Aucun secret géré par RDS, instance ignorée",
+				slog.String("instance", instance.DBInstanceIdentifier),
+			)
+			continue
+		}
+
+		credentials, err := rds.GetMasterUserCredentials(instance)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err))
+			continue
+		}
+
+		entries = append(entries, pgpass.Entry{
+			Hostname: instance.Endpoint.Address,
+			Port:     instance.Endpoint.Port,
+			Database: database,
+			Username: credentials.Username,
+			Password: credentials.Password,
+		})
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+// GetSecretConnection retourne les paramètres de connexion de l'instance avec le mot de passe maître
+func (rds RDS) GetSecretConnection(database string) (postgres.Connection, error) {
+	instance := rds.GetdbInstance()
+	credentials, err := rds.GetMasterUserCredentials(instance)
+	if err != nil {
+		return postgres.Connection{}, err
+	}
+
+	caFile, err := DownloadCABundle()
+	if err != nil {
+		return postgres.Connection{}, fmt.Errorf("RDS: DownloadCABundle: %w", err)
+	}
+
+	return postgres.Connection{
+		Host:        instance.Endpoint.Address,
+		Port:        instance.Endpoint.Port,
+		Database:    database,
+		Username:    credentials.Username,
+		Password:    credentials.Password,
+		SSLMode:     "verify-full",
+		SSLRootCert: caFile,
+	}, nil
+}
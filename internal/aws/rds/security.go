@@ -0,0 +1,157 @@
+package rds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// openSecurityGroups retourne les groupes de sécurité de l'instance qui autorisent
+// tout Internet (0.0.0.0/0 ou ::/0) sur le port PostgreSQL
+func (rds *RDS) openSecurityGroups(dbInstance DBInstance, port int) ([]string, error) {
+	var groupIDs []string
+	for _, sg := range dbInstance.VpcSecurityGroups {
+		groupIDs = append(groupIDs, sg.VpcSecurityGroupID)
+	}
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	result, err := rds.ec2Client.DescribeSecurityGroups(rds.ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: groupIDs})
+	if err != nil {
+		return nil, fmt.Errorf("EC2: DescribeSecurityGroups SDK call: %w", err)
+	}
+
+	var open []string
+	for _, group := range result.SecurityGroups {
+		for _, permission := range group.IpPermissions {
+			protocol := aws.ToString(permission.IpProtocol)
+			if protocol != "-1" && protocol != "tcp" {
+				continue
+			}
+			// Le protocole -1 couvre tous les ports
+			if protocol == "tcp" && (int(aws.ToInt32(permission.FromPort)) > port || int(aws.ToInt32(permission.ToPort)) < port) {
+				continue
+			}
+
+			public := false
+			for _, ipRange := range permission.IpRanges {
+				public = public || security.IsOpenToInternet(aws.ToString(ipRange.CidrIp))
+			}
+			for _, ipRange := range permission.Ipv6Ranges {
+				public = public || security.IsOpenToInternet(aws.ToString(ipRange.CidrIpv6))
+			}
+			if public {
+				open = append(open, aws.ToString(group.GroupId))
+				break
+			}
+		}
+	}
+	return open, nil
+}
+
+// forceSSL indique si rds.force_ssl est actif ; sans valeur explicite, la valeur par défaut
+// du moteur s'applique (1 à partir de PostgreSQL 15)
+func (rds RDS) forceSSL(engineVersion string) bool {
+	value, err := rds.GetParameterValueByParameterName("rds.force_ssl")
+	if err != nil || value == "" {
+		major, _, _ := strings.Cut(engineVersion, ".")
+		number, err := strconv.Atoi(major)
+		return err == nil && number >= 15
+	}
+	return value == "1"
+}
+
+// SecurityFindings évalue la posture de sécurité de l'instance : exposition publique,
+// chiffrement TLS et du stockage, authentification, expiration de l'autorité de certification
+// (expiryDays jours avant la date de fin de validité) et protection contre la suppression
+func (rds *RDS) SecurityFindings(expiryDays int) ([]security.Finding, error) {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return nil, fmt.Errorf("aucune instance RDS")
+	}
+	dbInstance := rds.GetdbInstance()
+
+	finding := func(check string, severity string, detail string, remediation string) security.Finding {
+		return security.Finding{
+			Provider:    "rds",
+			Resource:    dbInstance.DBInstanceIdentifier,
+			Check:       check,
+			Severity:    severity,
+			Detail:      detail,
+			Remediation: remediation,
+		}
+	}
+	var findings []security.Finding
+
+	port := dbInstance.Endpoint.Port
+	if port == 0 {
+		port = security.PostgreSQLPort
+	}
+
+	if dbInstance.PubliclyAccessible {
+		open, err := rds.openSecurityGroups(dbInstance, port)
+		if err != nil {
+			return nil, fmt.Errorf("openSecurityGroups: %w", err)
+		}
+		if len(open) > 0 {
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityHigh,
+				fmt.Sprintf("instance publique, groupes de sécurité ouverts à 0.0.0.0/0 sur le port %d : %s", port, strings.Join(open, ", ")),
+				"Désactiver PubliclyAccessible et restreindre les règles entrantes des groupes de sécurité"))
+		} else {
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityMedium,
+				"instance publique (PubliclyAccessible), accès restreint par les groupes de sécurité",
+				"Désactiver PubliclyAccessible si l'instance n'a pas besoin d'être joignable depuis Internet"))
+		}
+	}
+
+	if !rds.forceSSL(dbInstance.EngineVersion) {
+		findings = append(findings, finding(security.CheckTLSEnforcement, security.SeverityHigh,
+			"rds.force_ssl n'est pas activé : les connexions non chiffrées sont acceptées",
+			"Positionner rds.force_ssl à 1 dans le groupe de paramètres"))
+	}
+
+	if !dbInstance.StorageEncrypted {
+		findings = append(findings, finding(security.CheckStorageEncryption, security.SeverityHigh,
+			"stockage non chiffré",
+			"Restaurer un snapshot chiffré avec une clé KMS (le chiffrement ne peut pas être activé sur une instance existante)"))
+	}
+
+	if !dbInstance.IAMDatabaseAuthenticationEnabled && len(dbInstance.DomainMemberships) == 0 {
+		findings = append(findings, finding(security.CheckPasswordOnlyAuth, security.SeverityMedium,
+			"authentification par mot de passe uniquement (ni IAM ni Kerberos)",
+			"Activer l'authentification IAM (--enable-iam-database-authentication)"))
+	}
+
+	if dbInstance.CACertificateIdentifier != "" {
+		certificates, err := rds.DescribeCertificates()
+		if err != nil {
+			return nil, fmt.Errorf("DescribeCertificates: %w", err)
+		}
+		certificate, ok := GetCertificate(certificates, dbInstance.CACertificateIdentifier)
+		if ok {
+			switch {
+			case certificate.ValidTill.Before(time.Now()):
+				findings = append(findings, finding(security.CheckCACertificateExpiry, security.SeverityHigh,
+					fmt.Sprintf("autorité de certification %s expirée depuis le %s", certificate.CertificateIdentifier, certificate.ValidTill.Format("2006-01-02")),
+					"Appliquer une autorité de certification récente (--ca-certificate-identifier) et mettre à jour le bundle des clients"))
+			case certificate.ValidTill.Before(time.Now().AddDate(0, 0, expiryDays)):
+				findings = append(findings, finding(security.CheckCACertificateExpiry, security.SeverityMedium,
+					fmt.Sprintf("autorité de certification %s expire le %s", certificate.CertificateIdentifier, certificate.ValidTill.Format("2006-01-02")),
+					"Planifier la rotation vers une autorité de certification récente (--ca-certificate-identifier)"))
+			}
+		}
+	}
+
+	if !dbInstance.DeletionProtection {
+		findings = append(findings, finding(security.CheckDeletionProtection, security.SeverityLow,
+			"protection contre la suppression désactivée",
+			"Activer DeletionProtection"))
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,30 @@
+package rds
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// SelectInstances retourne les identifiants des instances de la région du profil dont les étiquettes
+// (TagList) satisfont le sélecteur
+func SelectInstances(profile string, sel selector.Selector) ([]string, error) {
+	var rds RDS
+	err := rds.connect(profile, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	rds.dbInstances, err = rds.DescribeDbInstances()
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDbInstances: %w", err)
+	}
+
+	var identifiers []string
+	for _, instance := range rds.dbInstances.DBInstances {
+		if sel.Matches(instance.Tags()) {
+			identifiers = append(identifiers, instance.DBInstanceIdentifier)
+		}
+	}
+	return identifiers, nil
+}
@@ -0,0 +1,281 @@
+package rds
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+	"github.com/robinportigliatti/cloud_helper/internal/sizing"
+)
+
+// metricStats résume une métrique CloudWatch : p95 des moyennes et maximum des maxima
+func (rds RDS) metricStats(metricName string, startTime time.Time, endTime time.Time) (sizing.Stats, error) {
+	average, err := rds.metricSamples(metricName, cwTypes.StatisticAverage, startTime, endTime)
+	if err != nil {
+		return sizing.Stats{}, fmt.Errorf("%s: %w", metricName, err)
+	}
+	maximum, err := rds.metricSamples(metricName, cwTypes.StatisticMaximum, startTime, endTime)
+	if err != nil {
+		return sizing.Stats{}, fmt.Errorf("%s: %w", metricName, err)
+	}
+	return sizing.Summarize(average, maximum), nil
+}
+
+// Usage retourne l'utilisation de l'instance sur les derniers jours (CPUUtilization, FreeableMemory,
+// DatabaseConnections, ReadIOPS, WriteIOPS). La mémoire utilisée est déduite de FreeableMemory
+func (rds RDS) Usage(windowDays int, memoryBytes int64) (sizing.Usage, error) {
+	var usage sizing.Usage
+	var err error
+
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -windowDays)
+
+	usage.CPUPercent, err = rds.metricStats("CPUUtilization", startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.Connections, err = rds.metricStats("DatabaseConnections", startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.ReadIops, err = rds.metricStats("ReadIOPS", startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.WriteIops, err = rds.metricStats("WriteIOPS", startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+
+	// Le minimum de mémoire libre sur chaque période correspond au maximum de mémoire utilisée
+	freeAverage, err := rds.metricSamples("FreeableMemory", cwTypes.StatisticAverage, startTime, endTime)
+	if err != nil {
+		return usage, fmt.Errorf("FreeableMemory: %w", err)
+	}
+	freeMinimum, err := rds.metricSamples("FreeableMemory", cwTypes.StatisticMinimum, startTime, endTime)
+	if err != nil {
+		return usage, fmt.Errorf("FreeableMemory: %w", err)
+	}
+	usedAverage := make([]forecast.Sample, 0, len(freeAverage))
+	for _, sample := range freeAverage {
+		usedAverage = append(usedAverage, forecast.Sample{Timestamp: sample.Timestamp, Value: float64(memoryBytes) - sample.Value})
+	}
+	usedMaximum := make([]forecast.Sample, 0, len(freeMinimum))
+	for _, sample := range freeMinimum {
+		usedMaximum = append(usedMaximum, forecast.Sample{Timestamp: sample.Timestamp, Value: float64(memoryBytes) - sample.Value})
+	}
+	usage.MemoryBytes = sizing.Summarize(usedAverage, usedMaximum)
+
+	return usage, nil
+}
+
+// Nombre maximal de valeurs par filtre de DescribeInstanceTypes
+const describeInstanceTypesBatch = 100
+
+// FamilyClasses retourne les classes d'instance de la même famille proposées pour le moteur et
+// la version de l'instance (DescribeOrderableDBInstanceOptions), avec leurs vCPU et leur mémoire
+func (rds RDS) FamilyClasses() ([]sizing.Class, error) {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return nil, fmt.Errorf("no instance found")
+	}
+	instance := rds.dbInstances.DBInstances[0]
+	family := sizing.Family(instance.DBInstanceClass)
+
+	input := &awsRds.DescribeOrderableDBInstanceOptionsInput{
+		Engine:        aws.String(instance.Engine),
+		EngineVersion: aws.String(instance.EngineVersion),
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	paginator := awsRds.NewDescribeOrderableDBInstanceOptionsPaginator(rds.rdsClient, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(rds.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeOrderableDBInstanceOptions SDK call: %w", err)
+		}
+		for _, option := range result.OrderableDBInstanceOptions {
+			name := aws.ToString(option.DBInstanceClass)
+			if sizing.Family(name) != family || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if !seen[instance.DBInstanceClass] {
+		names = append(names, instance.DBInstanceClass)
+	}
+
+	// Les classes RDS correspondent aux types EC2 sans le préfixe "db." ; certaines n'existent pas
+	// côté EC2. Un filtre, contrairement à InstanceTypes, ignore les types inconnus au lieu d'échouer
+	types := make(map[string]string, len(names))
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		instanceType := strings.Replace(name, "db.", "", 1)
+		types[instanceType] = name
+		values = append(values, instanceType)
+	}
+
+	var classes []sizing.Class
+	for batch := range slices.Chunk(values, describeInstanceTypesBatch) {
+		paginator := ec2.NewDescribeInstanceTypesPaginator(rds.ec2Client, &ec2.DescribeInstanceTypesInput{
+			Filters: []ec2Types.Filter{{Name: aws.String("instance-type"), Values: batch}},
+		})
+		for paginator.HasMorePages() {
+			result, err := paginator.NextPage(rds.ctx)
+			if err != nil {
+				return nil, fmt.Errorf("EC2: DescribeInstanceTypes SDK call: %w", err)
+			}
+			for _, instanceType := range result.InstanceTypes {
+				class := sizing.Class{Name: types[string(instanceType.InstanceType)]}
+				if instanceType.VCpuInfo != nil {
+					class.VCPU = int(aws.ToInt32(instanceType.VCpuInfo.DefaultVCpus))
+				}
+				if instanceType.MemoryInfo != nil {
+					class.MemoryBytes = aws.ToInt64(instanceType.MemoryInfo.SizeInMiB) * 1024 * 1024
+				}
+				if instanceType.EbsInfo != nil && instanceType.EbsInfo.EbsOptimizedInfo != nil {
+					class.Iops = int(aws.ToInt32(instanceType.EbsInfo.EbsOptimizedInfo.BaselineIops))
+				}
+				classes = append(classes, class)
+			}
+		}
+	}
+
+	sizing.SortClasses(classes)
+	return classes, nil
+}
+
+// storageIops retourne la limite d'IOPS du stockage de l'instance : IOPS provisionnées (io1, io2, gp3)
+// ou IOPS de base de gp2 (3 par GiB, entre 100 et 16000). Le stockage Aurora n'a pas de limite fixe (0)
+func storageIops(instance DBInstance) int {
+	switch {
+	case instance.Iops > 0:
+		return instance.Iops
+	case instance.StorageType == "gp2":
+		return min(max(3*instance.AllocatedStorage, 100), 16000)
+	}
+	return 0
+}
+
+// formulaVariables retourne les variables des formules de paramètres pour une classe
+func formulaVariables(class sizing.Class, allocatedStorage int) map[string]float64 {
+	return map[string]float64{
+		"DBInstanceClassMemory": float64(class.MemoryBytes),
+		"DBInstanceVCPU":        float64(class.VCPU),
+		"AllocatedStorage":      float64(allocatedStorage) * forecast.GiB,
+	}
+}
+
+// ParameterChanges évalue les formules du groupe de paramètres ({DBInstanceClassMemory/32768}...)
+// pour la classe actuelle et la classe proposée, et retourne les paramètres dont la valeur change
+func (rds RDS) ParameterChanges(current sizing.Class, suggested sizing.Class) []sizing.ParameterChange {
+	allocatedStorage := 0
+	if len(rds.dbInstances.DBInstances) > 0 {
+		allocatedStorage = rds.dbInstances.DBInstances[0].AllocatedStorage
+	}
+	currentVariables := formulaVariables(current, allocatedStorage)
+	suggestedVariables := formulaVariables(suggested, allocatedStorage)
+
+	var changes []sizing.ParameterChange
+	for _, parameter := range rds.dbParameterGroups.Parameters {
+		if !strings.Contains(parameter.ParameterValue, "{") {
+			continue
+		}
+
+		currentValue, err := rules.EvalFormula(parameter.ParameterValue, currentVariables)
+		if err != nil {
+			continue
+		}
+		suggestedValue, err := rules.EvalFormula(parameter.ParameterValue, suggestedVariables)
+		if err != nil || suggestedValue == currentValue {
+			continue
+		}
+
+		changes = append(changes, sizing.ParameterChange{
+			Name:      parameter.ParameterName,
+			Formula:   parameter.ParameterValue,
+			Current:   strconv.FormatFloat(currentValue, 'f', 0, 64),
+			Suggested: strconv.FormatFloat(suggestedValue, 'f', 0, 64),
+		})
+	}
+	return changes
+}
+
+// RecommendSizing analyse l'utilisation de l'instance sur la fenêtre et propose une classe
+// plus grande ou plus petite de la même famille, avec les paramètres dont la formule en dépend
+func (rds RDS) RecommendSizing(windowDays int) (sizing.Recommendation, error) {
+	if len(rds.dbInstances.DBInstances) == 0 {
+		return sizing.Recommendation{}, fmt.Errorf("no instance found")
+	}
+	if len(rds.describeInstanceTypes.InstanceTypes) == 0 {
+		return sizing.Recommendation{}, fmt.Errorf("no instance type found for %s", rds.dbInstances.DBInstances[0].DBInstanceClass)
+	}
+	instance := rds.dbInstances.DBInstances[0]
+
+	recommendation := sizing.Recommendation{
+		Provider:   "rds",
+		Resource:   instance.DBInstanceIdentifier,
+		WindowDays: windowDays,
+		Current: sizing.Class{
+			Name:        instance.DBInstanceClass,
+			VCPU:        rds.GetDefaultVCpus(),
+			MemoryBytes: int64(rds.GetMemoryInfo().SizeInMiB) * 1024 * 1024,
+			Iops:        rds.describeInstanceTypes.InstanceTypes[0].EbsInfo.EbsOptimizedInfo.BaselineIops,
+		},
+	}
+
+	var err error
+	recommendation.Usage, err = rds.Usage(windowDays, recommendation.Current.MemoryBytes)
+	if err != nil {
+		return recommendation, fmt.Errorf("Usage: %w", err)
+	}
+	recommendation.Usage.StorageIops = storageIops(instance)
+
+	classes, err := rds.FamilyClasses()
+	if err != nil {
+		return recommendation, fmt.Errorf("FamilyClasses: %w", err)
+	}
+
+	// Le stockage Aurora n'est pas un volume EBS : le débit EBS de la classe ne le limite pas
+	if strings.HasPrefix(instance.Engine, "aurora") {
+		recommendation.Current.Iops = 0
+		for i := range classes {
+			classes[i].Iops = 0
+		}
+	}
+
+	recommendation.Action, recommendation.Suggested, recommendation.Reasons = sizing.Recommend(recommendation.Usage, recommendation.Current, classes)
+	if recommendation.Suggested == nil {
+		return recommendation, nil
+	}
+	recommendation.Parameters = rds.ParameterChanges(recommendation.Current, *recommendation.Suggested)
+
+	// Une classe plus petite réduit max_connections lorsqu'il dépend de la mémoire
+	for _, parameter := range recommendation.Parameters {
+		if parameter.Name != "max_connections" || recommendation.Action != sizing.ActionDownsize {
+			continue
+		}
+		maxConnections, _ := strconv.ParseFloat(parameter.Suggested, 64)
+		if maxConnections < recommendation.Usage.Connections.Max {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("max_connections passerait à %s pour %.0f connexions observées",
+				parameter.Suggested, recommendation.Usage.Connections.Max))
+			recommendation.Action = sizing.ActionKeep
+			recommendation.Suggested = nil
+			recommendation.Parameters = nil
+		}
+		break
+	}
+	return recommendation, nil
+}
@@ -0,0 +1,176 @@
+package rds
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+// lagWindow est la période sur laquelle le retard maximal des réplicas est mesuré
+const lagWindow = time.Hour
+
+// arnRegion, arnAccount et arnName extraient la région, le compte et l'identifiant d'un ARN RDS
+// (arn:aws:rds:<région>:<compte>:db:<id>)
+func arnRegion(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[3]
+}
+
+func arnAccount(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+func arnName(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
+
+// instanceMetricMaximum retourne le maximum d'une métrique CloudWatch d'une instance sur la dernière heure,
+// nil si la métrique n'est pas publiée
+func (rds RDS) instanceMetricMaximum(dbInstanceIdentifier string, metricName string) (*float64, error) {
+	endTime := time.Now()
+	statsResult, err := rds.cloudwatchClient.GetMetricStatistics(rds.ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/RDS"),
+		MetricName: aws.String(metricName),
+		StartTime:  aws.Time(endTime.Add(-lagWindow)),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int32(int32(lagWindow.Seconds())),
+		Statistics: []cwTypes.Statistic{cwTypes.StatisticMaximum},
+		Dimensions: []cwTypes.Dimension{{
+			Name:  aws.String("DBInstanceIdentifier"),
+			Value: aws.String(dbInstanceIdentifier),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch: GetMetricStatistics SDK call: %w", err)
+	}
+
+	var maximum *float64
+	for _, datapoint := range statsResult.Datapoints {
+		if datapoint.Maximum != nil && (maximum == nil || *datapoint.Maximum > *maximum) {
+			maximum = aws.Float64(*datapoint.Maximum)
+		}
+	}
+	return maximum, nil
+}
+
+// Topology retourne la topologie de réplication des instances chargées : primaires et réplicas en lecture
+// (ReplicaLag), clusters Aurora avec leur instance d'écriture et leurs lecteurs (AuroraReplicaLag).
+// Chaque instance porte le retard de son slot de réplication le plus ancien (OldestReplicationSlotLag).
+// Les réplicas et sources d'une autre région ne sont connus que par leur ARN, sans état ni métriques
+func (rds RDS) Topology() (topology.Topology, error) {
+	region := rds.awsConfig.Region
+	arns := make(map[string]string, len(rds.dbInstances.DBInstances))
+	for _, instance := range rds.dbInstances.DBInstances {
+		arns[instance.DBInstanceIdentifier] = instance.DBInstanceArn
+	}
+	// resolve retourne l'identifiant de nœud (ARN) d'une instance désignée par son identifiant ou son ARN
+	resolve := func(reference string) string {
+		if strings.HasPrefix(reference, "arn:") {
+			return reference
+		}
+		return arns[reference]
+	}
+
+	var nodes []topology.Node
+	known := make(map[string]bool)
+	for _, cluster := range rds.dbClusters.DBClusters {
+		nodes = append(nodes, topology.Node{
+			ID:     cluster.DBClusterArn,
+			Name:   cluster.DBClusterIdentifier,
+			Role:   topology.RoleCluster,
+			Region: region,
+			Status: cluster.Status,
+		})
+	}
+
+	for _, instance := range rds.dbInstances.DBInstances {
+		node := topology.Node{
+			ID:     instance.DBInstanceArn,
+			Name:   instance.DBInstanceIdentifier,
+			Role:   topology.RolePrimary,
+			Region: region,
+			Status: instance.DBInstanceStatus,
+		}
+		lagMetric, lagScale := "", 1.0
+		switch {
+		case instance.DBClusterIdentifier != "":
+			cluster := rds.findCluster(instance.DBClusterIdentifier)
+			member, _ := cluster.GetMember(instance.DBInstanceIdentifier)
+			node.Role = topology.RoleWriter
+			node.Parent = cluster.DBClusterArn
+			if !member.IsClusterWriter {
+				node.Role = topology.RoleReader
+				lagMetric, lagScale = "AuroraReplicaLag", 0.001
+				for _, writer := range cluster.DBClusterMembers {
+					if writer.IsClusterWriter {
+						node.Parent = arns[writer.DBInstanceIdentifier]
+					}
+				}
+			}
+		case instance.ReadReplicaSourceDBInstanceIdentifier != "":
+			node.Role = topology.RoleReplica
+			node.Parent = resolve(instance.ReadReplicaSourceDBInstanceIdentifier)
+			lagMetric = "ReplicaLag"
+		}
+
+		if lagMetric != "" {
+			lag, err := rds.instanceMetricMaximum(instance.DBInstanceIdentifier, lagMetric)
+			if err != nil {
+				return topology.Topology{}, err
+			}
+			if lag != nil {
+				node.ReplicaLagSeconds = aws.Float64(*lag * lagScale)
+			}
+		}
+		slotLag, err := rds.instanceMetricMaximum(instance.DBInstanceIdentifier, "OldestReplicationSlotLag")
+		if err != nil {
+			return topology.Topology{}, err
+		}
+		node.SlotLagBytes = slotLag
+
+		nodes = append(nodes, node)
+		known[node.ID] = true
+	}
+
+	// Réplicas et sources dans une autre région, connus uniquement par leur ARN
+	for _, instance := range rds.dbInstances.DBInstances {
+		for _, replica := range instance.ReadReplicaDBInstanceIdentifiers {
+			id := resolve(replica)
+			if known[id] || !strings.HasPrefix(id, "arn:") {
+				continue
+			}
+			nodes = append(nodes, topology.Node{ID: id, Name: arnName(id), Role: topology.RoleReplica, Region: arnRegion(id), Parent: instance.DBInstanceArn})
+			known[id] = true
+		}
+		source := instance.ReadReplicaSourceDBInstanceIdentifier
+		if strings.HasPrefix(source, "arn:") && !known[source] {
+			nodes = append(nodes, topology.Node{ID: source, Name: arnName(source), Role: topology.RolePrimary, Region: arnRegion(source)})
+			known[source] = true
+		}
+	}
+
+	return topology.New("rds", nodes), nil
+}
+
+// findCluster retourne le cluster Aurora chargé portant cet identifiant
+func (rds RDS) findCluster(dbClusterIdentifier string) DBCluster {
+	for _, cluster := range rds.dbClusters.DBClusters {
+		if cluster.DBClusterIdentifier == dbClusterIdentifier {
+			return cluster
+		}
+	}
+	return DBCluster{DBClusterIdentifier: dbClusterIdentifier}
+}
@@ -0,0 +1,129 @@
+package rds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsRds "github.com/aws/aws-sdk-go-v2/service/rds"
+
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+// maxUpgradeSteps borne le nombre de montées de version successives d'un chemin
+const maxUpgradeSteps = 10
+
+// ValidUpgradeTargets retourne les versions cibles d'une version du moteur (ValidUpgradeTarget)
+func (rds RDS) ValidUpgradeTargets(engine string, engineVersion string) ([]upgrade.Target, error) {
+	result, err := rds.rdsClient.DescribeDBEngineVersions(rds.ctx, &awsRds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(engineVersion),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDBEngineVersions SDK call: %w", err)
+	}
+
+	var targets []upgrade.Target
+	for _, engineVersion := range result.DBEngineVersions {
+		for _, target := range engineVersion.ValidUpgradeTarget {
+			targets = append(targets, upgrade.Target{
+				Version:     aws.ToString(target.EngineVersion),
+				Major:       aws.ToBool(target.IsMajorVersionUpgrade),
+				Description: aws.ToString(target.Description),
+			})
+		}
+	}
+	upgrade.SortTargets(targets)
+	return targets, nil
+}
+
+// SupportedExtensions retourne les extensions proposées pour une version du moteur : valeurs autorisées
+// de rds.extensions dans les paramètres par défaut de sa famille (de cluster sur Aurora, à défaut de ceux
+// d'instance). nil si la famille ne déclare pas ce paramètre
+func (rds RDS) SupportedExtensions(engine string, engineVersion string) (*upgrade.SupportedExtensions, error) {
+	result, err := rds.rdsClient.DescribeDBEngineVersions(rds.ctx, &awsRds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(engineVersion),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeDBEngineVersions SDK call: %w", err)
+	}
+	if len(result.DBEngineVersions) == 0 {
+		return nil, fmt.Errorf("RDS: version %s %s introuvable", engine, engineVersion)
+	}
+	family := aws.ToString(result.DBEngineVersions[0].DBParameterGroupFamily)
+
+	defaults, err := rds.DescribeEngineDefaultParameters(family)
+	if err != nil {
+		return nil, fmt.Errorf("RDS: DescribeEngineDefaultParameters: %w", err)
+	}
+	parameter, err := defaults.GetParameterByParameterName("rds.extensions")
+	if err != nil && strings.HasPrefix(engine, "aurora") {
+		clusterDefaults, err := rds.DescribeEngineDefaultClusterParameters(family)
+		if err != nil {
+			return nil, fmt.Errorf("RDS: DescribeEngineDefaultClusterParameters: %w", err)
+		}
+		parameter, _ = clusterDefaults.GetParameterByParameterName("rds.extensions")
+	}
+	if parameter.AllowedValues == "" {
+		return nil, nil
+	}
+	return upgrade.ParseSupportedExtensions(parameter.AllowedValues, fmt.Sprintf("rds.extensions (%s)", family)), nil
+}
+
+// UpgradePlans construit le plan de montée de version de chaque instance PostgreSQL. Le chemin
+// enchaîne à chaque étape la version cible la plus récente, jusqu'à la dernière version atteignable
+func (rds RDS) UpgradePlans(calendar upgrade.Calendar) ([]upgrade.Plan, error) {
+	cache := make(map[string][]upgrade.Target)
+	targetsOf := func(engine string, version string) ([]upgrade.Target, error) {
+		key := engine + "/" + version
+		if targets, ok := cache[key]; ok {
+			return targets, nil
+		}
+		targets, err := rds.ValidUpgradeTargets(engine, version)
+		if err != nil {
+			return nil, err
+		}
+		cache[key] = targets
+		return targets, nil
+	}
+
+	var plans []upgrade.Plan
+	for _, instance := range rds.dbInstances.DBInstances {
+		if instance.Engine != "postgres" && instance.Engine != "aurora-postgresql" {
+			continue
+		}
+
+		targets, err := targetsOf(instance.Engine, instance.EngineVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err)
+		}
+
+		var path []string
+		version := instance.EngineVersion
+		stepTargets := targets
+		for range maxUpgradeSteps {
+			if len(stepTargets) == 0 {
+				break
+			}
+			latest := stepTargets[len(stepTargets)-1].Version
+			if upgrade.Compare(latest, version) <= 0 {
+				break
+			}
+			path = append(path, latest)
+			version = latest
+
+			stepTargets, err = targetsOf(instance.Engine, version)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", instance.DBInstanceIdentifier, err)
+			}
+		}
+
+		plan := upgrade.NewPlan(calendar, "rds", instance.DBInstanceIdentifier, instance.EngineVersion, targets, path)
+		if instance.DBClusterIdentifier != "" {
+			plan.Notes = append(plan.Notes, fmt.Sprintf("la montée de version se fait au niveau du cluster %s", instance.DBClusterIdentifier))
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
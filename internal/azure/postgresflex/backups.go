@@ -0,0 +1,92 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/backup"
+)
+
+// ServerBackupListResult est la réponse de l'API backups d'un serveur
+type ServerBackupListResult struct {
+	Value    []ServerBackup `json:"value"`
+	NextLink string         `json:"nextLink,omitempty"`
+}
+
+type ServerBackup struct {
+	Name       string                 `json:"name"`
+	Properties ServerBackupProperties `json:"properties"`
+}
+
+type ServerBackupProperties struct {
+	BackupType    string    `json:"backupType"`
+	CompletedTime time.Time `json:"completedTime"`
+	Source        string    `json:"source,omitempty"`
+}
+
+// ListBackups retourne les sauvegardes complètes du serveur, automatiques (Full) et à la demande
+func (pf *PostgresFlex) ListBackups() ([]backup.Snapshot, error) {
+	if pf.server == nil {
+		return nil, fmt.Errorf("no server initialized")
+	}
+
+	var snapshots []backup.Snapshot
+	url := fmt.Sprintf("https://management.azure.com%s/backups?api-version=%s", pf.server.ID, apiVersion)
+	for url != "" {
+		output, err := pf.Rest(url)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Rest: %w", err)
+		}
+
+		var page ServerBackupListResult
+		err = json.Unmarshal([]byte(output), &page)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+		}
+
+		for _, serverBackup := range page.Value {
+			snapshotType := backup.TypeManual
+			if serverBackup.Properties.BackupType == "Full" {
+				snapshotType = backup.TypeAutomated
+			}
+			snapshots = append(snapshots, backup.Snapshot{
+				Name:     serverBackup.Name,
+				Type:     snapshotType,
+				Status:   "completed",
+				Created:  serverBackup.Properties.CompletedTime,
+				Location: pf.server.Location,
+			})
+		}
+		url = page.NextLink
+	}
+	return snapshots, nil
+}
+
+// BackupReport retourne la configuration de sauvegarde du serveur. La restauration à un instant donné
+// est toujours active sur Flexible Server, de earliestRestoreDate à maintenant ; la copie dans la région
+// appairée dépend de geoRedundantBackup
+func (pf *PostgresFlex) BackupReport() (backup.Report, error) {
+	if pf.server == nil {
+		return backup.Report{}, fmt.Errorf("no server initialized")
+	}
+	serverBackup := pf.server.Properties.Backup
+
+	crossRegion := serverBackup.GeoRedundantBackup == "Enabled"
+	report := backup.Report{
+		Provider:      "azure",
+		Resource:      pf.serverName,
+		Region:        pf.server.Location,
+		RetentionDays: serverBackup.BackupRetentionDays,
+		PITR:          true,
+		CrossRegion:   &crossRegion,
+	}
+
+	snapshots, err := pf.ListBackups()
+	if err != nil {
+		return report, err
+	}
+	report.SetSnapshots(snapshots)
+	report.SetWindow(serverBackup.EarliestRestoreDate, time.Now())
+	return report, nil
+}
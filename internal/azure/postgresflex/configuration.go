@@ -1,5 +1,14 @@
 package postgresflex
 
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
 type Configuration struct {
 	ID         string                  `json:"id"`
 	Name       string                  `json:"name"`
@@ -13,6 +22,7 @@ type ConfigurationProperties struct {
 	DefaultValue           string `json:"defaultValue,omitempty"`
 	DataType               string `json:"dataType,omitempty"`
 	AllowedValues          string `json:"allowedValues,omitempty"`
+	Unit                   string `json:"unit,omitempty"`
 	Source                 string `json:"source,omitempty"`
 	IsDynamicConfig        bool   `json:"isDynamicConfig,omitempty"`
 	IsReadOnly             bool   `json:"isReadOnly,omitempty"`
@@ -20,7 +30,8 @@ type ConfigurationProperties struct {
 }
 
 type ConfigurationListResult struct {
-	Value []Configuration `json:"value"`
+	Value    []Configuration `json:"value"`
+	NextLink string          `json:"nextLink,omitempty"`
 }
 
 func (c *ConfigurationListResult) GetValueByName(name string) (string, error) {
@@ -31,3 +42,48 @@ func (c *ConfigurationListResult) GetValueByName(name string) (string, error) {
 	}
 	return "", nil
 }
+
+// GetConfigurationByName retourne la configuration portant ce nom
+func (c *ConfigurationListResult) GetConfigurationByName(name string) (Configuration, error) {
+	for _, config := range c.Value {
+		if config.Name == name {
+			return config, nil
+		}
+	}
+	return Configuration{}, fmt.Errorf("configuration %s not found", name)
+}
+
+// Validate vérifie qu'une valeur est acceptable pour la configuration (lecture seule, type, valeurs autorisées)
+// et retourne la valeur à transmettre à Azure (les booléens sont convertis en on/off)
+func (c Configuration) Validate(value string) (string, error) {
+	if c.Properties.IsReadOnly {
+		return "", fmt.Errorf("la configuration %s est en lecture seule", c.Name)
+	}
+
+	switch strings.ToLower(c.Properties.DataType) {
+	case "boolean":
+		normalized, ok := pgsettings.NormalizeBool(value)
+		if !ok {
+			return "", fmt.Errorf("%s attend un booléen (valeur : %s)", c.Name, value)
+		}
+		return normalized, nil
+	case "integer", "numeric":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s attend une valeur numérique (valeur : %s)", c.Name, value)
+		}
+		if c.Properties.AllowedValues != "" && !pgsettings.InRange(c.Properties.AllowedValues, number) {
+			return "", fmt.Errorf("%s doit être compris dans %s (valeur : %s)", c.Name, c.Properties.AllowedValues, value)
+		}
+		return value, nil
+	case "enumeration", "set":
+		allowedValues := strings.Split(c.Properties.AllowedValues, ",")
+		for _, v := range strings.Split(value, ",") {
+			if !slices.ContainsFunc(allowedValues, func(allowed string) bool { return strings.EqualFold(strings.TrimSpace(allowed), strings.TrimSpace(v)) }) {
+				return "", fmt.Errorf("%s n'accepte que %s (valeur : %s)", c.Name, c.Properties.AllowedValues, v)
+			}
+		}
+	}
+
+	return value, nil
+}
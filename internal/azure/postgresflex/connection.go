@@ -0,0 +1,28 @@
+package postgresflex
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// GetConnection retourne les paramètres de connexion au FQDN du serveur
+// Sans nom d'utilisateur, le login administrateur est utilisé ; sans mot de passe, PGPASSWORD ou .pgpass
+func (pf *PostgresFlex) GetConnection(username string, database string, password string) (postgres.Connection, error) {
+	if pf.server == nil {
+		return postgres.Connection{}, fmt.Errorf("no server initialized")
+	}
+
+	if username == "" {
+		username = pf.server.Properties.AdministratorLogin
+	}
+
+	return postgres.Connection{
+		Host:     pf.server.Properties.FullyQualifiedDomainName,
+		Port:     5432,
+		Database: database,
+		Username: username,
+		Password: password,
+		SSLMode:  "require",
+	}, nil
+}
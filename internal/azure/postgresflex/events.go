@@ -0,0 +1,96 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/events"
+)
+
+// Version de l'API du journal d'activité Azure (Microsoft.Insights/eventtypes/management)
+const activityLogAPIVersion = "2015-04-01"
+
+// ActivityLogResult est une page du journal d'activité
+type ActivityLogResult struct {
+	Value    []ActivityLogEvent `json:"value"`
+	NextLink string             `json:"nextLink,omitempty"`
+}
+
+type ActivityLogEvent struct {
+	EventTimestamp time.Time        `json:"eventTimestamp"`
+	Caller         string           `json:"caller,omitempty"`
+	Level          string           `json:"level,omitempty"`
+	OperationName  ActivityLogValue `json:"operationName"`
+	Status         ActivityLogValue `json:"status"`
+	SubStatus      ActivityLogValue `json:"subStatus"`
+	Category       ActivityLogValue `json:"category"`
+}
+
+type ActivityLogValue struct {
+	Value          string `json:"value"`
+	LocalizedValue string `json:"localizedValue"`
+}
+
+// Events retourne les entrées du journal d'activité Azure du serveur entre start et end : redémarrages,
+// bascules, modifications de paramètres, maintenance... Azure conserve le journal d'activité 90 jours
+func (pf *PostgresFlex) Events(start time.Time, end time.Time) ([]events.Event, error) {
+	if pf.server == nil {
+		return nil, fmt.Errorf("no server initialized")
+	}
+
+	filter := fmt.Sprintf("eventTimestamp ge '%s' and eventTimestamp le '%s' and resourceUri eq '%s'",
+		start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), pf.server.ID)
+	next := fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/Microsoft.Insights/eventtypes/management/values?api-version=%s&$filter=%s",
+		pf.server.Subscription(), activityLogAPIVersion, url.QueryEscape(filter))
+
+	var result []events.Event
+	for next != "" {
+		output, err := pf.Rest(next)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Rest: %w", err)
+		}
+
+		var page ActivityLogResult
+		err = json.Unmarshal([]byte(output), &page)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+		}
+
+		for _, entry := range page.Value {
+			result = append(result, events.Event{
+				Time:     entry.EventTimestamp,
+				Provider: "azure",
+				Source:   pf.serverName,
+				Category: entry.OperationName.Value,
+				Message:  activityMessage(entry),
+			})
+		}
+		next = page.NextLink
+	}
+
+	events.Sort(result)
+	return result, nil
+}
+
+// activityMessage décrit une entrée du journal d'activité : opération, état et auteur
+func activityMessage(entry ActivityLogEvent) string {
+	operation := entry.OperationName.LocalizedValue
+	if operation == "" {
+		operation = entry.OperationName.Value
+	}
+	parts := []string{operation}
+	if entry.Status.Value != "" {
+		parts = append(parts, entry.Status.Value)
+	}
+	if entry.SubStatus.LocalizedValue != "" {
+		parts = append(parts, entry.SubStatus.LocalizedValue)
+	}
+	message := strings.Join(parts, " ")
+	if entry.Caller != "" {
+		message = fmt.Sprintf("%s by %s", message, entry.Caller)
+	}
+	return message
+}
@@ -0,0 +1,77 @@
+package postgresflex
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// skuRegexp extrait la famille et le nombre de vCPU d'un SKU (ex: Standard_D4ds_v5, Standard_B1ms)
+var skuRegexp = regexp.MustCompile(`^Standard_([A-Z]+)([0-9]+)([a-z]*)`)
+
+// Conversion des unités ARM vers les unités de pg_settings
+var azureUnits = map[string]string{
+	"KB":           "kB",
+	"8KB":          "8kB",
+	"MB":           "MB",
+	"GB":           "GB",
+	"milliseconds": "ms",
+	"seconds":      "s",
+	"minutes":      "min",
+}
+
+// skuResources estime les vCPU et la mémoire (en Gio) d'un SKU : 4 Gio par vCPU pour les séries D
+// et B (ms), 8 Gio pour la série E, 2 Gio pour les B (s). La mémoire est inconnue pour les autres séries
+func skuResources(sku string) (int, int) {
+	matches := skuRegexp.FindStringSubmatch(sku)
+	if matches == nil {
+		return 0, 0
+	}
+
+	vcpu, _ := strconv.Atoi(matches[2])
+	switch {
+	case matches[1] == "E":
+		return vcpu, vcpu * 8
+	case matches[1] == "D":
+		return vcpu, vcpu * 4
+	case matches[1] == "B" && matches[3] == "ms" && vcpu == 1:
+		return vcpu, 2
+	case matches[1] == "B" && matches[3] == "ms":
+		return vcpu, vcpu * 4
+	case matches[1] == "B":
+		return vcpu, vcpu * 2
+	}
+	return vcpu, 0
+}
+
+// Facts retourne le modèle commun aux fournisseurs utilisé par le moteur de règles
+func (pf *PostgresFlex) Facts() rules.Instance {
+	facts := rules.Instance{
+		Provider:   "azure",
+		Identifier: pf.serverName,
+		Parameters: make(map[string]rules.Parameter),
+	}
+
+	if pf.server != nil {
+		vcpu, memoryGiB := skuResources(pf.server.Sku.Name)
+		facts.MachineType = pf.server.Sku.Name
+		facts.VCPU = vcpu
+		facts.MemoryBytes = int64(memoryGiB) * 1024 * 1024 * 1024
+		facts.EngineVersion = pf.server.Properties.Version
+		facts.StorageGB = pf.server.Properties.Storage.StorageSizeGB
+		facts.HighAvailability = pf.server.Properties.HighAvailability.Mode != "" &&
+			!strings.EqualFold(pf.server.Properties.HighAvailability.Mode, "Disabled")
+		facts.BackupRetentionDays = &pf.server.Properties.Backup.BackupRetentionDays
+	}
+
+	for _, configuration := range pf.configurations.Value {
+		unit, ok := azureUnits[configuration.Properties.Unit]
+		if !ok {
+			unit = configuration.Properties.Unit
+		}
+		facts.Parameters[configuration.Name] = rules.Parameter{Value: configuration.Properties.Value, Unit: unit}
+	}
+	return facts
+}
@@ -0,0 +1,145 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+)
+
+// Version de l'API Azure Monitor (Microsoft.Insights/metrics)
+const metricsAPIVersion = "2018-01-01"
+
+// Tailles de stockage proposées (GiB) et IOPS de base du disque Premium SSD correspondant
+var storageIops = map[int]int{
+	32:    120,
+	64:    240,
+	128:   500,
+	256:   1100,
+	512:   2300,
+	1024:  5000,
+	2048:  7500,
+	4096:  7500,
+	8192:  16000,
+	16384: 18000,
+	32767: 20000,
+}
+
+// storageSizes retourne les tailles de stockage proposées, par ordre croissant
+func storageSizes() []int {
+	sizes := make([]int, 0, len(storageIops))
+	for size := range storageIops {
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+	return sizes
+}
+
+// GetMetrics retourne les métriques Azure Monitor du serveur, agrégées par heure
+func (pf *PostgresFlex) GetMetrics(metricNames string, aggregation string, startTime time.Time, endTime time.Time) (MetricsResult, error) {
+	if pf.server == nil {
+		return MetricsResult{}, fmt.Errorf("no server initialized")
+	}
+	return pf.serverMetrics(pf.server.ID, metricNames, aggregation, startTime, endTime)
+}
+
+// serverMetrics retourne les métriques Azure Monitor d'un serveur désigné par son identifiant ARN
+func (pf *PostgresFlex) serverMetrics(serverID string, metricNames string, aggregation string, startTime time.Time, endTime time.Time) (MetricsResult, error) {
+	var result MetricsResult
+	url := fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.Insights/metrics?api-version=%s&metricnames=%s&aggregation=%s&interval=PT1H&timespan=%s/%s",
+		serverID, metricsAPIVersion, metricNames, aggregation,
+		startTime.UTC().Format(time.RFC3339), endTime.UTC().Format(time.RFC3339))
+	output, err := pf.Rest(url)
+	if err != nil {
+		return result, fmt.Errorf("PostgresFlex: Rest: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(output), &result)
+	if err != nil {
+		return result, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+	}
+	return result, nil
+}
+
+// metricSamples extrait les points d'une métrique (agrégation Maximum)
+func metricSamples(result MetricsResult, metricName string) []forecast.Sample {
+	var samples []forecast.Sample
+	for _, metric := range result.Value {
+		if metric.Name.Value != metricName {
+			continue
+		}
+		for _, timeseries := range metric.Timeseries {
+			for _, value := range timeseries.Data {
+				if value.Maximum == nil {
+					continue
+				}
+				samples = append(samples, forecast.Sample{Timestamp: value.Timestamp, Value: *value.Maximum})
+			}
+		}
+	}
+	return samples
+}
+
+// ForecastStorage ajuste une tendance à l'espace utilisé (storage_used) sur les derniers jours,
+// estime l'échéance du disque plein et recommande une taille de stockage proposée par Azure.
+// Les IOPS d'un disque Premium SSD dépendant de sa taille, la taille est augmentée si le pic d'IOPS l'exige
+func (pf *PostgresFlex) ForecastStorage(historyDays int, horizonDays int) (forecast.StorageForecast, error) {
+	if pf.server == nil {
+		return forecast.StorageForecast{}, fmt.Errorf("no server initialized")
+	}
+	storage := pf.server.Properties.Storage
+
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -historyDays)
+
+	metrics, err := pf.GetMetrics("storage_used,read_iops,write_iops", "Maximum", startTime, endTime)
+	if err != nil {
+		return forecast.StorageForecast{}, fmt.Errorf("GetMetrics: %w", err)
+	}
+
+	// Avec la croissance automatique, le disque grandit jusqu'à la taille maximale proposée
+	sizes := storageSizes()
+	maxStorageGB := 0
+	if storage.AutoGrow {
+		maxStorageGB = sizes[len(sizes)-1]
+	}
+
+	storageForecast, err := forecast.Storage(metricSamples(metrics, "storage_used"), storage.StorageSizeGB, maxStorageGB, historyDays, horizonDays)
+	if err != nil {
+		return storageForecast, fmt.Errorf("forecast.Storage: %w", err)
+	}
+	storageForecast.Provider = "azure"
+	storageForecast.Resource = pf.serverName
+	storageForecast.Iops = storage.Iops
+
+	ranges := make([]forecast.Range, 0, len(sizes))
+	for _, size := range sizes {
+		ranges = append(ranges, forecast.Range{From: size, To: size, Step: 1})
+	}
+	storageForecast.RecommendStorage(ranges)
+	if !storage.AutoGrow {
+		storageForecast.Notes = append(storageForecast.Notes, "la croissance automatique du stockage est désactivée (autoGrow)")
+	}
+
+	peak := forecast.Peak(forecast.Sum(metricSamples(metrics, "read_iops"), metricSamples(metrics, "write_iops")))
+	storageForecast.PeakIops = math.Round(peak)
+	target := int(math.Ceil(peak * forecast.IopsMargin))
+
+	storageForecast.RecommendedIops = storageIops[storageForecast.RecommendedStorageGB]
+	if storageForecast.RecommendedIops < target {
+		idx := slices.IndexFunc(sizes, func(size int) bool {
+			return size > storageForecast.RecommendedStorageGB && storageIops[size] >= target
+		})
+		if idx == -1 {
+			storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("%d IOPS dépassent les IOPS de base du plus grand disque", target))
+		} else {
+			storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("taille augmentée à %d GB pour obtenir %d IOPS", sizes[idx], target))
+			storageForecast.RecommendedStorageGB = sizes[idx]
+			storageForecast.RecommendedIops = storageIops[sizes[idx]]
+		}
+	}
+	return storageForecast, nil
+}
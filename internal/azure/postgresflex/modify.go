@@ -0,0 +1,61 @@
+package postgresflex
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// ConfigurationChange représente une modification de configuration planifiée
+type ConfigurationChange struct {
+	Name            string `json:"name"`
+	CurrentValue    string `json:"currentValue"`
+	Value           string `json:"value"`
+	RequiresRestart bool   `json:"requiresRestart"`
+}
+
+// PlanConfigurationChanges valide les affectations et retourne les modifications à appliquer, sans rien modifier
+func (pf *PostgresFlex) PlanConfigurationChanges(assignments []pgsettings.Assignment) ([]ConfigurationChange, error) {
+	var changes []ConfigurationChange
+
+	for _, assignment := range assignments {
+		config, err := pf.configurations.GetConfigurationByName(assignment.Name)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: GetConfigurationByName: %w", err)
+		}
+
+		value, err := config.Validate(assignment.Value)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Validate: %w", err)
+		}
+
+		if pgsettings.Equivalent(config.Properties.Value, value, "") {
+			continue
+		}
+
+		changes = append(changes, ConfigurationChange{
+			Name:            config.Name,
+			CurrentValue:    config.Properties.Value,
+			Value:           value,
+			RequiresRestart: !config.Properties.IsDynamicConfig,
+		})
+	}
+
+	return changes, nil
+}
+
+// ApplyConfigurationChanges applique les modifications via az postgres flexible-server parameter set
+func (pf *PostgresFlex) ApplyConfigurationChanges(changes []ConfigurationChange) error {
+	for _, change := range changes {
+		_, err := pf.ExecuteArgs("postgres", "flexible-server", "parameter", "set",
+			"--resource-group", pf.resourceGroup,
+			"--server-name", pf.serverName,
+			"--name", change.Name,
+			"--value", change.Value)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: ExecuteArgs: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,18 @@
 package postgresflex
 
 import (
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
 )
 
+// Version de l'API ARM Microsoft.DBforPostgreSQL/flexibleServers
+const apiVersion = "2022-12-01"
+
 // PostgresFlex structure principale pour gérer Azure PostgreSQL Flexible Server
 type PostgresFlex struct {
 	serverName     string
@@ -50,20 +57,47 @@ func (pf *PostgresFlex) GetServer() Server {
 	return Server{}
 }
 
-func (pf *PostgresFlex) Execute(command string, args string) (string, error) {
-	var cmdStr string
+// ExecuteArgs exécute une commande az avec une liste d'arguments, sans shell, pour que les valeurs
+// fournies par l'utilisateur (valeurs de paramètres, étiquettes) ne soient jamais interprétées
+func (pf *PostgresFlex) ExecuteArgs(args ...string) (string, error) {
 	if pf.subscription != "" {
-		cmdStr = fmt.Sprintf("az %s %s --subscription %s", command, args, pf.subscription)
-	} else {
-		cmdStr = fmt.Sprintf("az %s %s", command, args)
+		args = append(args, "--subscription", pf.subscription)
 	}
 
-	cmd := exec.Command("bash", "-c", cmdStr)
+	cmd := exec.Command("az", args...)
 	output, err := cmd.CombinedOutput()
 	result := string(output)
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %s %w", cmdStr, result, err)
+		return "", fmt.Errorf("az %s: %s %w", strings.Join(args, " "), result, err)
+	}
+
+	return result, nil
+}
+
+// Rest interroge l'API ARM via az rest et retourne la réponse JSON brute.
+// Contrairement à "az postgres flexible-server", la réponse respecte le format ARM (champ properties)
+func (pf *PostgresFlex) Rest(url string) (string, error) {
+	subscription := pf.subscription
+	if subscription == "" {
+		// az rest remplace {subscriptionId} par l'abonnement courant
+		subscription = "{subscriptionId}"
+	}
+	if !strings.HasPrefix(url, "https://") {
+		scope := fmt.Sprintf("/subscriptions/%s", subscription)
+		if pf.resourceGroup != "" {
+			scope = fmt.Sprintf("%s/resourceGroups/%s", scope, pf.resourceGroup)
+		}
+		url = fmt.Sprintf("https://management.azure.com%s/providers/Microsoft.DBforPostgreSQL/flexibleServers%s?api-version=%s",
+			scope, url, apiVersion)
+	}
+
+	cmd := exec.Command("az", "rest", "--method", "get", "--url", url)
+	output, err := cmd.CombinedOutput()
+	result := string(output)
+
+	if err != nil {
+		return "", fmt.Errorf("az rest --method get --url %s: %s %w", url, result, err)
 	}
 
 	return result, nil
@@ -78,16 +112,16 @@ func (pf *PostgresFlex) DescribeServer() error {
 		return nil
 	}
 
-	args := fmt.Sprintf("--resource-group %s --name %s", pf.resourceGroup, pf.serverName)
-	_, err := pf.Execute("postgres flexible-server show", args)
+	output, err := pf.Rest(fmt.Sprintf("/%s", pf.serverName))
 	if err != nil {
-		return fmt.Errorf("PostgresFlex: Execute: %w", err)
+		return fmt.Errorf("PostgresFlex: Rest: %w", err)
 	}
 
-	// Parse JSON response
 	var server Server
-	// Note: En production, il faudrait utiliser json.Unmarshal ici
-	// Pour l'instant, on simule avec la structure
+	err = json.Unmarshal([]byte(output), &server)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+	}
 	pf.server = &server
 
 	return nil
@@ -131,14 +165,23 @@ func (pf *PostgresFlex) LoadConfigurations() error {
 		return nil
 	}
 
-	args := fmt.Sprintf("--resource-group %s --server-name %s", pf.resourceGroup, pf.serverName)
-	_, err := pf.Execute("postgres flexible-server parameter list", args)
-	if err != nil {
-		return fmt.Errorf("PostgresFlex: Execute: %w", err)
-	}
+	// La liste des configurations est paginée (nextLink)
+	url := fmt.Sprintf("/%s/configurations", pf.serverName)
+	for url != "" {
+		output, err := pf.Rest(url)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: Rest: %w", err)
+		}
+
+		var page ConfigurationListResult
+		err = json.Unmarshal([]byte(output), &page)
+		if err != nil {
+			return fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+		}
 
-	// Parse JSON response
-	// Note: En production, il faudrait utiliser json.Unmarshal ici
+		pf.configurations.Value = append(pf.configurations.Value, page.Value...)
+		url = page.NextLink
+	}
 
 	return nil
 }
@@ -183,71 +226,37 @@ func (pf *PostgresFlex) GetConfigurationValue(name string) (string, error) {
 	return value, nil
 }
 
-func (pf *PostgresFlex) CheckConfiguration(name string, expectedValue string) error {
-	value, err := pf.GetConfigurationValue(name)
+// Check évalue un profil de vérification embarqué sur les paramètres du serveur
+func (pf *PostgresFlex) Check(profile string) ([]check.Result, error) {
+	results, err := check.Run(profile, "azure", func(name string) (string, bool) {
+		value, err := pf.GetConfigurationValue(name)
+		return value, err == nil && value != ""
+	})
 	if err != nil {
-		return fmt.Errorf("GetConfigurationValue: %w", err)
-	}
-
-	str := ""
-	if value != expectedValue {
-		str = fmt.Sprintf("%s should be at %s (currently: %s)", name, expectedValue, value)
-	} else {
-		str = fmt.Sprintf("%s: OK", name)
+		return nil, fmt.Errorf("check: Run: %w", err)
 	}
-
-	fmt.Println(str)
-	return nil
+	return results, nil
 }
 
-func (pf *PostgresFlex) CheckPgbadger() error {
-	err := pf.CheckConfiguration("log_connections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_disconnections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_lock_waits", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_temp_files", "0")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_autovacuum_min_duration", "0")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_min_duration_statement", "0")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_duration", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_checkpoints", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("log_statement", "all")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
-	err = pf.CheckConfiguration("track_io_timing", "on")
-	if err != nil {
-		return fmt.Errorf("CheckConfiguration: %w", err)
-	}
+// CheckPgbadger vérifie les paramètres nécessaires à pgbadger
+func (pf *PostgresFlex) CheckPgbadger() ([]check.Result, error) {
+	return pf.Check("pgbadger")
+}
 
-	_, err = pf.GetConfigurationValue("track_activity_query_size")
-	if err != nil {
-		return fmt.Errorf("GetConfigurationValue: %w", err)
+// GetNonDefaultConfigurations retourne les configurations dont la valeur diffère de la valeur par défaut
+func (pf *PostgresFlex) GetNonDefaultConfigurations() []Configuration {
+	var configurations []Configuration
+	for _, config := range pf.configurations.Value {
+		if config.Properties.Value == "" {
+			continue
+		}
+		// Une valeur surchargée (source user-override) mais égale à la valeur par défaut n'est pas retenue
+		if pgsettings.Equivalent(config.Properties.Value, config.Properties.DefaultValue, "") {
+			continue
+		}
+		configurations = append(configurations, config)
 	}
-
-	return nil
+	return configurations
 }
 
 func (pf *PostgresFlex) GetAllConfigurationNames() ([]string, error) {
@@ -260,12 +269,27 @@ func (pf *PostgresFlex) GetAllConfigurationNames() ([]string, error) {
 
 // ListServers retourne tous les serveurs PostgreSQL Flexible dans le resource group
 func (pf *PostgresFlex) ListServers() ([]Server, error) {
-	args := fmt.Sprintf("--resource-group %s", pf.resourceGroup)
-	_, err := pf.Execute("postgres flexible-server list", args)
-	if err != nil {
-		return nil, fmt.Errorf("PostgresFlex: Execute: %w", err)
+	var servers []Server
+
+	url := ""
+	for {
+		output, err := pf.Rest(url)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Rest: %w", err)
+		}
+
+		var page ServerListResult
+		err = json.Unmarshal([]byte(output), &page)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+		}
+
+		servers = append(servers, page.Value...)
+		if page.NextLink == "" {
+			break
+		}
+		url = page.NextLink
 	}
 
-	// Note: En production, parser le JSON avec json.Unmarshal
-	return []Server{}, nil
+	return servers, nil
 }
@@ -0,0 +1,96 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RestoreServer restaure le serveur vers un nouveau serveur à l'instant demandé, ou à l'instant présent.
+// La commande rend la main immédiatement (--no-wait) : l'état du nouveau serveur est suivi avec ServerState
+func (pf *PostgresFlex) RestoreServer(target string, restoreTime *time.Time, tags map[string]string) error {
+	if pf.server == nil {
+		return fmt.Errorf("no server initialized")
+	}
+
+	args := []string{"postgres", "flexible-server", "restore",
+		"--resource-group", pf.resourceGroup,
+		"--name", target,
+		"--source-server", pf.serverName,
+		"--no-wait"}
+	if restoreTime != nil {
+		args = append(args, "--restore-time", restoreTime.UTC().Format(time.RFC3339))
+	}
+	if len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// Une étiquette par argument, sans shell : les valeurs ne sont jamais interprétées
+		args = append(args, "--tags")
+		for _, key := range keys {
+			args = append(args, key+"="+tags[key])
+		}
+	}
+
+	_, err := pf.ExecuteArgs(args...)
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: ExecuteArgs: %w", err)
+	}
+	return nil
+}
+
+// ServerState retourne l'état d'un serveur du groupe de ressources, s'il est disponible (Ready),
+// et une erreur s'il est désactivé. Un serveur pas encore créé est en Provisioning
+func (pf *PostgresFlex) ServerState(name string) (string, bool, error) {
+	output, err := pf.Rest(fmt.Sprintf("/%s", name))
+	if err != nil && strings.Contains(err.Error(), "ResourceNotFound") {
+		return "Provisioning", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("PostgresFlex: Rest: %w", err)
+	}
+
+	var server Server
+	err = json.Unmarshal([]byte(output), &server)
+	if err != nil {
+		return "", false, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+	}
+
+	state := server.Properties.State
+	if state == "Disabled" {
+		return state, false, fmt.Errorf("serveur %s en état %s", name, state)
+	}
+	return state, state == "Ready", nil
+}
+
+// ServerTags retourne les étiquettes d'un serveur du groupe de ressources
+func (pf *PostgresFlex) ServerTags(name string) (map[string]string, error) {
+	output, err := pf.Rest(fmt.Sprintf("/%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("PostgresFlex: Rest: %w", err)
+	}
+
+	var server Server
+	err = json.Unmarshal([]byte(output), &server)
+	if err != nil {
+		return nil, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+	}
+	return server.Tags, nil
+}
+
+// DeleteServer supprime un serveur du groupe de ressources
+func (pf *PostgresFlex) DeleteServer(name string) error {
+	_, err := pf.ExecuteArgs("postgres", "flexible-server", "delete",
+		"--resource-group", pf.resourceGroup,
+		"--name", name,
+		"--yes")
+	if err != nil {
+		return fmt.Errorf("PostgresFlex: ExecuteArgs: %w", err)
+	}
+	return nil
+}
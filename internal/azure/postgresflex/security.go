@@ -0,0 +1,103 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// ListFirewallRules retourne les règles de pare-feu du serveur
+func (pf *PostgresFlex) ListFirewallRules() ([]FirewallRule, error) {
+	var rules []FirewallRule
+
+	// La liste des règles est paginée (nextLink)
+	url := fmt.Sprintf("/%s/firewallRules", pf.serverName)
+	for url != "" {
+		output, err := pf.Rest(url)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Rest: %w", err)
+		}
+
+		var page FirewallRuleListResult
+		err = json.Unmarshal([]byte(output), &page)
+		if err != nil {
+			return nil, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+		}
+
+		rules = append(rules, page.Value...)
+		url = page.NextLink
+	}
+
+	return rules, nil
+}
+
+// SecurityFindings évalue la posture de sécurité du serveur : accès public et règles de pare-feu,
+// chiffrement TLS et authentification. Le stockage est toujours chiffré et l'autorité de
+// certification est gérée par Azure
+func (pf *PostgresFlex) SecurityFindings() ([]security.Finding, error) {
+	if pf.server == nil {
+		return nil, fmt.Errorf("aucun serveur Azure PostgreSQL")
+	}
+
+	finding := func(check string, severity string, detail string, remediation string) security.Finding {
+		return security.Finding{
+			Provider:    "azure",
+			Resource:    pf.server.Name,
+			Check:       check,
+			Severity:    severity,
+			Detail:      detail,
+			Remediation: remediation,
+		}
+	}
+	var findings []security.Finding
+
+	if strings.EqualFold(pf.server.Properties.Network.PublicNetworkAccess, "Enabled") {
+		rules, err := pf.ListFirewallRules()
+		if err != nil {
+			return nil, fmt.Errorf("ListFirewallRules: %w", err)
+		}
+
+		var open, azureServices []string
+		for _, rule := range rules {
+			switch {
+			case rule.Properties.StartIPAddress == "0.0.0.0" && rule.Properties.EndIPAddress == "255.255.255.255":
+				open = append(open, rule.Name)
+			case rule.Properties.StartIPAddress == "0.0.0.0" && rule.Properties.EndIPAddress == "0.0.0.0":
+				azureServices = append(azureServices, rule.Name)
+			}
+		}
+
+		switch {
+		case len(open) > 0:
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityHigh,
+				fmt.Sprintf("accès public et règles de pare-feu ouvertes à tout Internet sur le port %d : %s", security.PostgreSQLPort, strings.Join(open, ", ")),
+				"Supprimer les règles 0.0.0.0-255.255.255.255 et privilégier l'intégration VNet ou Private Link"))
+		case len(azureServices) > 0:
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityMedium,
+				fmt.Sprintf("accès public autorisé depuis tous les services Azure, y compris d'autres abonnements : %s", strings.Join(azureServices, ", ")),
+				"Remplacer la règle AllowAllAzureServices par les adresses des services concernés"))
+		default:
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityMedium,
+				fmt.Sprintf("accès public activé, restreint par %d règles de pare-feu", len(rules)),
+				"Désactiver l'accès public si le serveur n'a pas besoin d'être joignable depuis Internet"))
+		}
+	}
+
+	requireSecureTransport, _ := pf.GetConfigurationValue("require_secure_transport")
+	if value, _ := pgsettings.NormalizeBool(requireSecureTransport); value != "on" {
+		findings = append(findings, finding(security.CheckTLSEnforcement, security.SeverityHigh,
+			"require_secure_transport n'est pas activé : les connexions non chiffrées sont acceptées",
+			"Positionner require_secure_transport à on"))
+	}
+
+	if !strings.EqualFold(pf.server.Properties.AuthConfig.ActiveDirectoryAuth, "Enabled") {
+		findings = append(findings, finding(security.CheckPasswordOnlyAuth, security.SeverityMedium,
+			"authentification par mot de passe uniquement (Microsoft Entra ID désactivé)",
+			"Activer l'authentification Microsoft Entra ID (az postgres flexible-server update --microsoft-entra-auth Enabled)"))
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,24 @@
+package postgresflex
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// SelectServers retourne les serveurs du groupe de ressources (ou de l'abonnement) dont les
+// étiquettes (tags) satisfont le sélecteur
+func (pf *PostgresFlex) SelectServers(sel selector.Selector) ([]Server, error) {
+	servers, err := pf.ListServers()
+	if err != nil {
+		return nil, fmt.Errorf("PostgresFlex: ListServers: %w", err)
+	}
+
+	var selected []Server
+	for _, server := range servers {
+		if sel.Matches(server.Tags) {
+			selected = append(selected, server)
+		}
+	}
+	return selected, nil
+}
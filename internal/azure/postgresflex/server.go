@@ -1,6 +1,7 @@
 package postgresflex
 
 import (
+	"strings"
 	"time"
 )
 
@@ -14,6 +15,28 @@ type Server struct {
 	Properties ServerProperties  `json:"properties"`
 }
 
+// ResourceGroup retourne le groupe de ressources du serveur, extrait de son identifiant ARM
+func (s Server) ResourceGroup() string {
+	parts := strings.Split(s.ID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// Subscription retourne l'abonnement du serveur, extrait de son identifiant ARM
+func (s Server) Subscription() string {
+	parts := strings.Split(s.ID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "subscriptions") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
 type ServerSku struct {
 	Name string `json:"name"`
 	Tier string `json:"tier"`
@@ -31,6 +54,15 @@ type ServerProperties struct {
 	MaintenanceWindow        MaintenanceWindow `json:"maintenanceWindow,omitempty"`
 	AvailabilityZone         string            `json:"availabilityZone,omitempty"`
 	CreateMode               string            `json:"createMode,omitempty"`
+	AuthConfig               AuthConfig        `json:"authConfig,omitempty"`
+	ReplicationRole          string            `json:"replicationRole,omitempty"`
+	ReplicaCapacity          int               `json:"replicaCapacity,omitempty"`
+	SourceServerResourceId   string            `json:"sourceServerResourceId,omitempty"`
+}
+
+type AuthConfig struct {
+	ActiveDirectoryAuth string `json:"activeDirectoryAuth,omitempty"`
+	PasswordAuth        string `json:"passwordAuth,omitempty"`
 }
 
 type Storage struct {
@@ -64,6 +96,22 @@ type MaintenanceWindow struct {
 	StartMinute  int    `json:"startMinute,omitempty"`
 }
 
+type FirewallRule struct {
+	Name       string                 `json:"name"`
+	Properties FirewallRuleProperties `json:"properties"`
+}
+
+type FirewallRuleProperties struct {
+	StartIPAddress string `json:"startIpAddress"`
+	EndIPAddress   string `json:"endIpAddress"`
+}
+
+type FirewallRuleListResult struct {
+	Value    []FirewallRule `json:"value"`
+	NextLink string         `json:"nextLink,omitempty"`
+}
+
 type ServerListResult struct {
-	Value []Server `json:"value"`
+	Value    []Server `json:"value"`
+	NextLink string   `json:"nextLink,omitempty"`
 }
@@ -0,0 +1,94 @@
+package postgresflex
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+// Métriques Azure Monitor du retard de réplication : en secondes sur le réplica,
+// en octets sur le primaire (plus grand retard de ses réplicas)
+const (
+	replicaLagMetric     = "physical_replication_delay_in_seconds"
+	replicaByteLagMetric = "physical_replication_delay_in_bytes"
+)
+
+// lagWindow est la période sur laquelle le retard maximal des réplicas est mesuré
+const lagWindow = time.Hour
+
+// serverMetricMaximum retourne le maximum d'une métrique d'un serveur sur la dernière heure,
+// nil si la métrique n'est pas publiée
+func (pf *PostgresFlex) serverMetricMaximum(serverID string, metricName string) (*float64, error) {
+	endTime := time.Now()
+	result, err := pf.serverMetrics(serverID, metricName, "Maximum", endTime.Add(-lagWindow), endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var maximum *float64
+	for _, sample := range metricSamples(result, metricName) {
+		if maximum == nil || sample.Value > *maximum {
+			value := sample.Value
+			maximum = &value
+		}
+	}
+	return maximum, nil
+}
+
+// Topology retourne la topologie de réplication des serveurs du groupe de ressources (ou de l'abonnement) :
+// primaires et réplicas en lecture (sourceServerResourceId), avec le retard des réplicas et, sur les
+// primaires ayant des réplicas, le plus grand retard en octets. Une source hors du périmètre n'est connue
+// que par son identifiant, sans état ni métriques
+func (pf *PostgresFlex) Topology() (topology.Topology, error) {
+	servers, err := pf.ListServers()
+	if err != nil {
+		return topology.Topology{}, fmt.Errorf("PostgresFlex: ListServers: %w", err)
+	}
+
+	// Les identifiants ARM ne sont pas sensibles à la casse
+	known := make(map[string]bool, len(servers))
+	hasReplicas := make(map[string]bool)
+	for _, server := range servers {
+		known[strings.ToLower(server.ID)] = true
+		if server.Properties.SourceServerResourceId != "" {
+			hasReplicas[strings.ToLower(server.Properties.SourceServerResourceId)] = true
+		}
+	}
+
+	var nodes []topology.Node
+	for _, server := range servers {
+		node := topology.Node{
+			ID:     strings.ToLower(server.ID),
+			Name:   server.Name,
+			Role:   topology.RolePrimary,
+			Region: server.Location,
+			Status: server.Properties.State,
+		}
+
+		source := strings.ToLower(server.Properties.SourceServerResourceId)
+		switch {
+		case source != "":
+			node.Role = topology.RoleReplica
+			node.Parent = source
+			node.ReplicaLagSeconds, err = pf.serverMetricMaximum(server.ID, replicaLagMetric)
+			if err != nil {
+				return topology.Topology{}, fmt.Errorf("PostgresFlex: serverMetricMaximum %s: %w", server.Name, err)
+			}
+			if !known[source] {
+				nodes = append(nodes, topology.Node{ID: source, Name: path.Base(source), Role: topology.RolePrimary})
+				known[source] = true
+			}
+		case hasReplicas[node.ID]:
+			node.SlotLagBytes, err = pf.serverMetricMaximum(server.ID, replicaByteLagMetric)
+			if err != nil {
+				return topology.Topology{}, fmt.Errorf("PostgresFlex: serverMetricMaximum %s: %w", server.Name, err)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	return topology.New("azure", nodes), nil
+}
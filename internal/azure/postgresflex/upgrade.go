@@ -0,0 +1,112 @@
+package postgresflex
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+// CapabilitiesResult est la réponse de l'API capabilities d'une région
+type CapabilitiesResult struct {
+	Value []Capability `json:"value"`
+}
+
+type Capability struct {
+	Name                            string          `json:"name"`
+	SupportedFlexibleServerEditions []ServerEdition `json:"supportedFlexibleServerEditions"`
+}
+
+type ServerEdition struct {
+	Name                    string                   `json:"name"`
+	SupportedServerVersions []SupportedServerVersion `json:"supportedServerVersions"`
+}
+
+type SupportedServerVersion struct {
+	Name                       string   `json:"name"`
+	SupportedVersionsToUpgrade []string `json:"supportedVersionsToUpgrade"`
+}
+
+// GetCapabilities retourne les capacités de la région du serveur (éditions, versions, montées de version)
+func (pf *PostgresFlex) GetCapabilities() (CapabilitiesResult, error) {
+	var result CapabilitiesResult
+	if pf.server == nil {
+		return result, fmt.Errorf("no server initialized")
+	}
+
+	subscription := pf.subscription
+	if subscription == "" {
+		subscription = "{subscriptionId}"
+	}
+	url := fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/Microsoft.DBforPostgreSQL/locations/%s/capabilities?api-version=%s",
+		subscription, pf.server.Location, apiVersion)
+	output, err := pf.Rest(url)
+	if err != nil {
+		return result, fmt.Errorf("PostgresFlex: Rest: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(output), &result)
+	if err != nil {
+		return result, fmt.Errorf("PostgresFlex: Unmarshal: %w", err)
+	}
+	return result, nil
+}
+
+// SupportedExtensions retourne les extensions autorisées sur le serveur (valeurs autorisées d'azure.extensions).
+// Azure ne publie pas cette liste pour une autre version : c'est celle de la version actuelle
+func (pf *PostgresFlex) SupportedExtensions() *upgrade.SupportedExtensions {
+	for _, configuration := range pf.configurations.Value {
+		if configuration.Name == "azure.extensions" && configuration.Properties.AllowedValues != "" {
+			return upgrade.ParseSupportedExtensions(configuration.Properties.AllowedValues,
+				fmt.Sprintf("azure.extensions (PostgreSQL %s)", pf.server.Properties.Version))
+		}
+	}
+	return nil
+}
+
+// UpgradePlan construit le plan de montée de version du serveur à partir des versions proposées
+// pour son édition (supportedVersionsToUpgrade). La montée de version majeure sur place pouvant
+// sauter plusieurs versions, le chemin vise directement la plus récente
+func (pf *PostgresFlex) UpgradePlan(calendar upgrade.Calendar) (upgrade.Plan, error) {
+	if pf.server == nil {
+		return upgrade.Plan{}, fmt.Errorf("no server initialized")
+	}
+
+	capabilities, err := pf.GetCapabilities()
+	if err != nil {
+		return upgrade.Plan{}, fmt.Errorf("GetCapabilities: %w", err)
+	}
+
+	version := pf.server.Properties.Version
+	seen := make(map[string]bool)
+	var targets []upgrade.Target
+	for _, capability := range capabilities.Value {
+		for _, edition := range capability.SupportedFlexibleServerEditions {
+			if edition.Name != pf.server.Sku.Tier {
+				continue
+			}
+			for _, serverVersion := range edition.SupportedServerVersions {
+				if serverVersion.Name != version {
+					continue
+				}
+				for _, target := range serverVersion.SupportedVersionsToUpgrade {
+					if seen[target] || upgrade.Compare(target, version) <= 0 {
+						continue
+					}
+					seen[target] = true
+					targets = append(targets, upgrade.Target{Version: target, Major: true})
+				}
+			}
+		}
+	}
+	upgrade.SortTargets(targets)
+
+	var path []string
+	if len(targets) > 0 {
+		path = append(path, targets[len(targets)-1].Version)
+	}
+
+	plan := upgrade.NewPlan(calendar, "azure", pf.serverName, version, targets, path)
+	plan.Notes = append(plan.Notes, "les versions mineures sont appliquées automatiquement pendant la fenêtre de maintenance")
+	return plan, nil
+}
@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// Types de sauvegarde
+const (
+	TypeAutomated = "automated"
+	TypeManual    = "manual"
+)
+
+// Statuts de conformité à la politique
+const (
+	StatusCompliant    = "compliant"
+	StatusNonCompliant = "non-compliant"
+	StatusUnknown      = "unknown"
+)
+
+// Snapshot décrit une sauvegarde (snapshot RDS, backup run Cloud SQL, sauvegarde Azure)
+type Snapshot struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Status   string    `json:"status"`
+	Created  time.Time `json:"created"`
+	Location string    `json:"location,omitempty"`
+}
+
+// Report décrit la configuration de sauvegarde d'une instance et sa conformité à la politique
+type Report struct {
+	Provider      string `json:"provider"`
+	Resource      string `json:"resource"`
+	Region        string `json:"region"`
+	RetentionDays int    `json:"retention_days"`
+	// RetainedBackups est la rétention exprimée en nombre de sauvegardes (Cloud SQL), sans équivalent en jours
+	RetainedBackups int    `json:"retained_backups,omitempty"`
+	BackupWindow    string `json:"backup_window,omitempty"`
+	PITR            bool   `json:"pitr"`
+	// CrossRegion est nil lorsque la copie dans une autre région ne peut pas être vérifiée depuis la région
+	// de l'instance (copies manuelles de snapshots RDS)
+	CrossRegion    *bool      `json:"cross_region"`
+	RestorableFrom *time.Time `json:"restorable_from,omitempty"`
+	RestorableTo   *time.Time `json:"restorable_to,omitempty"`
+	Automated      int        `json:"automated"`
+	Manual         int        `json:"manual"`
+	LatestBackup   *time.Time `json:"latest_backup,omitempty"`
+	Snapshots      []Snapshot `json:"snapshots"`
+	Status         string     `json:"status"`
+	Issues         []string   `json:"issues"`
+}
+
+// Policy est la politique de sauvegarde attendue
+type Policy struct {
+	MinRetentionDays   int
+	RequirePITR        bool
+	RequireCrossRegion bool
+}
+
+// SetSnapshots enregistre les sauvegardes de l'instance, triées de la plus récente à la plus ancienne,
+// et en déduit le nombre de sauvegardes automatiques et manuelles et la date de la dernière
+func (r *Report) SetSnapshots(snapshots []Snapshot) {
+	slices.SortFunc(snapshots, func(a, b Snapshot) int { return b.Created.Compare(a.Created) })
+	r.Snapshots = snapshots
+	r.Automated, r.Manual = 0, 0
+	r.LatestBackup = nil
+	for _, snapshot := range snapshots {
+		if snapshot.Type == TypeAutomated {
+			r.Automated++
+		} else {
+			r.Manual++
+		}
+		if r.LatestBackup == nil && !snapshot.Created.IsZero() {
+			created := snapshot.Created
+			r.LatestBackup = &created
+		}
+	}
+	if r.Snapshots == nil {
+		r.Snapshots = []Snapshot{}
+	}
+}
+
+// SetWindow enregistre la fenêtre de restauration ; une date nulle est ignorée
+func (r *Report) SetWindow(from time.Time, to time.Time) {
+	if !from.IsZero() {
+		r.RestorableFrom = &from
+	}
+	if !to.IsZero() {
+		r.RestorableTo = &to
+	}
+}
+
+// WindowDays retourne la durée de la fenêtre de restauration, en jours
+func (r Report) WindowDays() float64 {
+	if r.RestorableFrom == nil || r.RestorableTo == nil {
+		return 0
+	}
+	return math.Round(r.RestorableTo.Sub(*r.RestorableFrom).Hours()/24*10) / 10
+}
+
+// Evaluate vérifie le rapport par rapport à la politique et renseigne son statut
+func (p Policy) Evaluate(r *Report) {
+	r.Issues = []string{}
+	switch {
+	case r.RetainedBackups > 0:
+		// Rétention en nombre de sauvegardes : la politique est exprimée en jours et les sauvegardes
+		// automatiques sont quotidiennes, une sauvegarde retenue couvre donc au plus un jour
+		if r.RetainedBackups < p.MinRetentionDays {
+			r.Issues = append(r.Issues, fmt.Sprintf("rétention de %d sauvegardes quotidiennes < %d jours", r.RetainedBackups, p.MinRetentionDays))
+		}
+	case r.RetentionDays < p.MinRetentionDays:
+		r.Issues = append(r.Issues, fmt.Sprintf("rétention de %d jours < %d jours", r.RetentionDays, p.MinRetentionDays))
+	}
+	if p.RequirePITR && !r.PITR {
+		r.Issues = append(r.Issues, "restauration à un instant donné (PITR) désactivée")
+	}
+	unknown := false
+	if p.RequireCrossRegion {
+		switch {
+		case r.CrossRegion == nil:
+			unknown = true
+		case !*r.CrossRegion:
+			r.Issues = append(r.Issues, "aucune copie des sauvegardes dans une autre région")
+		}
+	}
+
+	r.Status = StatusCompliant
+	if len(r.Issues) > 0 {
+		r.Status = StatusNonCompliant
+	} else if unknown {
+		r.Status = StatusUnknown
+	}
+	if unknown {
+		r.Issues = append(r.Issues, "copie des sauvegardes dans une autre région non vérifiable depuis la région de l'instance")
+	}
+}
+
+// formatRetention affiche la rétention en jours, ou en nombre de sauvegardes lorsque le fournisseur l'exprime ainsi
+func formatRetention(report Report) string {
+	if report.RetainedBackups > 0 {
+		return fmt.Sprintf("%d backups", report.RetainedBackups)
+	}
+	return strconv.Itoa(report.RetentionDays)
+}
+
+// Print affiche les rapports au format demandé (default, csv, json, markdown) dans un tableau commun
+// aux fournisseurs ; avec snapshots, les sauvegardes de chaque instance sont listées ensuite
+func Print(format string, reports []Report, snapshots bool) error {
+	if format == "json" {
+		if reports == nil {
+			reports = []Report{}
+		}
+		return output.PrintJSON(reports)
+	}
+
+	columns := []string{"Provider", "Resource", "Region", "Retention", "PITR", "CrossRegion", "RestorableFrom", "RestorableTo", "WindowDays",
+		"Automated", "Manual", "LatestBackup", "Status", "Issues"}
+	rows := make([][]string, 0, len(reports))
+	for _, report := range reports {
+		rows = append(rows, []string{
+			report.Provider,
+			report.Resource,
+			report.Region,
+			formatRetention(report),
+			strconv.FormatBool(report.PITR),
+			formatCrossRegion(report.CrossRegion),
+			formatTime(report.RestorableFrom),
+			formatTime(report.RestorableTo),
+			strconv.FormatFloat(report.WindowDays(), 'f', 1, 64),
+			strconv.Itoa(report.Automated),
+			strconv.Itoa(report.Manual),
+			formatTime(report.LatestBackup),
+			report.Status,
+			strings.Join(report.Issues, "; "),
+		})
+	}
+	if err := output.PrintRows(format, columns, rows); err != nil {
+		return err
+	}
+
+	if !snapshots {
+		return nil
+	}
+	fmt.Println()
+	snapshotRows := [][]string{}
+	for _, report := range reports {
+		for _, snapshot := range report.Snapshots {
+			created := snapshot.Created
+			snapshotRows = append(snapshotRows, []string{report.Provider, report.Resource, snapshot.Name, snapshot.Type, snapshot.Status,
+				formatTime(&created), snapshot.Location})
+		}
+	}
+	return output.PrintRows(format, []string{"Provider", "Resource", "Snapshot", "Type", "Status", "Created", "Location"}, snapshotRows)
+}
+
+// formatCrossRegion affiche la copie dans une autre région, ou unknown si elle n'a pas pu être vérifiée
+func formatCrossRegion(crossRegion *bool) string {
+	if crossRegion == nil {
+		return StatusUnknown
+	}
+	return strconv.FormatBool(*crossRegion)
+}
+
+// formatTime affiche une date au format AAAA-MM-JJ HH:MM (UTC), ou une chaîne vide si elle est inconnue
+func formatTime(date *time.Time) string {
+	if date == nil || date.IsZero() {
+		return ""
+	}
+	return date.UTC().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,356 @@
+package check
+
+import (
+	"embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+//go:embed profiles/*.yaml
+var profilesFS embed.FS
+
+// Sévérités d'une règle
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Statuts d'un résultat de vérification
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+	StatusWarn = "warn"
+	StatusSkip = "skip"
+)
+
+// ErrFailed est retournée lorsqu'au moins une règle de sévérité error n'est pas respectée
+var ErrFailed = errors.New("des règles de vérification ne sont pas respectées")
+
+// Rule décrit la valeur attendue d'un paramètre PostgreSQL
+type Rule struct {
+	Name        string   `yaml:"name"`
+	Parameter   string   `yaml:"parameter"`
+	Operator    string   `yaml:"operator"`
+	Value       string   `yaml:"value"`
+	Values      []string `yaml:"values"`
+	Default     *string  `yaml:"default"`
+	Severity    string   `yaml:"severity"`
+	Providers   []string `yaml:"providers"`
+	Remediation string   `yaml:"remediation"`
+}
+
+// Profile regroupe les règles d'un usage (pgbadger, quellog, security, performance)
+type Profile struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Rules       []Rule `yaml:"rules"`
+}
+
+// Result représente le résultat d'une règle sur une instance
+type Result struct {
+	Instance    string `json:"instance,omitempty"`
+	Profile     string `json:"profile"`
+	Rule        string `json:"rule"`
+	Parameter   string `json:"parameter"`
+	Expected    string `json:"expected"`
+	Actual      string `json:"actual"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// Lookup retourne la valeur d'un paramètre telle que déclarée chez le fournisseur,
+// et false si le fournisseur ne la renseigne pas (valeur par défaut du moteur)
+type Lookup func(name string) (string, bool)
+
+// Profiles retourne le nom des profils embarqués
+func Profiles() []string {
+	entries, err := profilesFS.ReadDir("profiles")
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	return names
+}
+
+// LoadProfile charge un profil embarqué
+func LoadProfile(name string) (Profile, error) {
+	data, err := profilesFS.ReadFile(path.Join("profiles", name+".yaml"))
+	if err != nil {
+		return Profile{}, fmt.Errorf("profil %s inconnu (profils disponibles : %s)", name, strings.Join(Profiles(), ", "))
+	}
+
+	var profile Profile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return Profile{}, fmt.Errorf("YAML Unmarshal: %w", err)
+	}
+
+	for _, rule := range profile.Rules {
+		if err := rule.validate(); err != nil {
+			return Profile{}, fmt.Errorf("profil %s, règle %s: %w", name, rule.Name, err)
+		}
+	}
+	return profile, nil
+}
+
+func (r Rule) validate() error {
+	switch r.Operator {
+	case "eq", "ne", "contains", "gt", "gte", "lt", "lte":
+		if r.Value == "" {
+			return fmt.Errorf("l'opérateur %s nécessite une valeur", r.Operator)
+		}
+	case "match":
+		if _, err := regexp.Compile(r.Value); err != nil || r.Value == "" {
+			return fmt.Errorf("l'opérateur match nécessite une expression régulière valide (%s)", r.Value)
+		}
+	case "in":
+		if len(r.Values) == 0 {
+			return fmt.Errorf("l'opérateur in nécessite une liste de valeurs")
+		}
+	case "notempty":
+	default:
+		return fmt.Errorf("opérateur %s inconnu", r.Operator)
+	}
+
+	if r.Severity != SeverityError && r.Severity != SeverityWarning {
+		return fmt.Errorf("sévérité %s inconnue", r.Severity)
+	}
+	return nil
+}
+
+// appliesTo indique si la règle concerne le fournisseur (toutes les règles sans restriction s'appliquent)
+func (r Rule) appliesTo(provider string) bool {
+	return len(r.Providers) == 0 || slices.Contains(r.Providers, provider)
+}
+
+// Expected retourne la contrainte de la règle sous forme lisible
+func (r Rule) Expected() string {
+	switch r.Operator {
+	case "eq":
+		return r.Value
+	case "ne":
+		return "!= " + r.Value
+	case "in":
+		return strings.Join(r.Values, ", ")
+	case "contains":
+		return "contient " + r.Value
+	case "match":
+		return "~ " + r.Value
+	case "gt":
+		return "> " + r.Value
+	case "gte":
+		return ">= " + r.Value
+	case "lt":
+		return "< " + r.Value
+	case "lte":
+		return "<= " + r.Value
+	case "notempty":
+		return "non vide"
+	}
+	return r.Value
+}
+
+// Match indique si une valeur respecte la règle
+func (r Rule) Match(value string) bool {
+	switch r.Operator {
+	case "eq":
+		return pgsettings.Equivalent(r.Value, value, "")
+	case "ne":
+		return !pgsettings.Equivalent(r.Value, value, "")
+	case "in":
+		return slices.ContainsFunc(r.Values, func(v string) bool { return pgsettings.Equivalent(v, value, "") })
+	case "contains":
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.Trim(strings.TrimSpace(item), `"`), r.Value) {
+				return true
+			}
+		}
+		return false
+	case "match":
+		matched, err := regexp.MatchString(r.Value, value)
+		return err == nil && matched
+	case "gt", "gte", "lt", "lte":
+		actual, ok := pgsettings.Normalize(value, "")
+		if !ok {
+			return false
+		}
+		expected, ok := pgsettings.Normalize(r.Value, "")
+		if !ok {
+			return false
+		}
+		switch r.Operator {
+		case "gt":
+			return actual > expected
+		case "gte":
+			return actual >= expected
+		case "lt":
+			return actual < expected
+		default:
+			return actual <= expected
+		}
+	case "notempty":
+		return strings.TrimSpace(value) != ""
+	}
+	return false
+}
+
+// Evaluate applique les règles du profil concernant le fournisseur.
+// Un paramètre non renseigné par le fournisseur prend la valeur par défaut de PostgreSQL
+// déclarée dans la règle ; sans valeur par défaut, la règle est ignorée
+func Evaluate(profile Profile, provider string, lookup Lookup) []Result {
+	var results []Result
+	for _, rule := range profile.Rules {
+		if !rule.appliesTo(provider) {
+			continue
+		}
+
+		result := Result{
+			Profile:     profile.Name,
+			Rule:        rule.Name,
+			Parameter:   rule.Parameter,
+			Expected:    rule.Expected(),
+			Severity:    rule.Severity,
+			Remediation: rule.Remediation,
+		}
+
+		value, ok := lookup(rule.Parameter)
+		if !ok {
+			if rule.Default == nil {
+				result.Status = StatusSkip
+				results = append(results, result)
+				continue
+			}
+			value = *rule.Default
+		}
+		result.Actual = value
+
+		switch {
+		case rule.Match(value):
+			result.Status = StatusOK
+		case rule.Severity == SeverityError:
+			result.Status = StatusFail
+		default:
+			result.Status = StatusWarn
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Run charge un profil embarqué et l'évalue
+func Run(profileName string, provider string, lookup Lookup) ([]Result, error) {
+	profile, err := LoadProfile(profileName)
+	if err != nil {
+		return nil, fmt.Errorf("LoadProfile: %w", err)
+	}
+	return Evaluate(profile, provider, lookup), nil
+}
+
+// Failed indique si au moins une règle de sévérité error n'est pas respectée
+func Failed(results []Result) bool {
+	return slices.ContainsFunc(results, func(r Result) bool { return r.Status == StatusFail })
+}
+
+// PrintResults affiche les résultats au format demandé (default, csv, json, markdown, junit)
+func PrintResults(format string, results []Result) error {
+	switch format {
+	case "json":
+		return output.PrintJSON(results)
+	case "junit":
+		return printJUnit(results)
+	}
+
+	// La colonne Instance n'est affichée que si plusieurs instances sont vérifiées ensemble
+	withInstance := slices.ContainsFunc(results, func(result Result) bool { return result.Instance != "" })
+
+	columns := []string{"Profile", "Parameter", "Expected", "Actual", "Status", "Remediation"}
+	if withInstance {
+		columns = append([]string{"Instance"}, columns...)
+	}
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		remediation := ""
+		if result.Status == StatusFail || result.Status == StatusWarn {
+			remediation = result.Remediation
+		}
+		row := []string{result.Profile, result.Parameter, result.Expected, result.Actual, result.Status, remediation}
+		if withInstance {
+			row = append([]string{result.Instance}, row...)
+		}
+		rows = append(rows, row)
+	}
+	return output.PrintRows(format, columns, rows)
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// printJUnit affiche les résultats au format JUnit XML pour les outils d'intégration continue.
+// Seules les règles de sévérité error sont des échecs, les avertissements restent des succès
+func printJUnit(results []Result) error {
+	suite := junitTestSuite{Name: "cloud_helper check", Tests: len(results)}
+	for _, result := range results {
+		testCase := junitTestCase{Name: result.Rule, ClassName: result.Profile}
+		if result.Instance != "" {
+			testCase.ClassName = result.Instance + "." + result.Profile
+		}
+		switch result.Status {
+		case StatusFail:
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%s = %s, attendu %s", result.Parameter, result.Actual, result.Expected),
+				Type:    result.Severity,
+				Text:    result.Remediation,
+			}
+		case StatusSkip:
+			suite.Skipped++
+			testCase.Skipped = &junitSkipped{Message: "paramètre non renseigné par le fournisseur"}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("XML Marshal: %w", err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, xml.Header+string(data))
+	return err
+}
@@ -0,0 +1,53 @@
+package check
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// RulesProfile est le profil des résultats issus des fichiers de règles d'audit (--rules)
+const RulesProfile = "rules"
+
+// RunRules charge les fichiers de règles d'audit et les évalue sur les faits de l'instance
+func RunRules(paths []string, instance rules.Instance) ([]Result, error) {
+	auditRules, err := rules.LoadFiles(paths)
+	if err != nil {
+		return nil, fmt.Errorf("rules: LoadFiles: %w", err)
+	}
+	return FromRules(rules.Evaluate(auditRules, instance)), nil
+}
+
+// FromRules convertit les résultats du moteur de règles d'audit en résultats de vérification.
+// Une règle non respectée (ou non évaluable) de sévérité error est un échec, les autres un avertissement
+func FromRules(findings []rules.Result) []Result {
+	results := make([]Result, 0, len(findings))
+	for _, finding := range findings {
+		result := Result{
+			Profile:     RulesProfile,
+			Rule:        finding.Rule,
+			Parameter:   finding.Rule,
+			Expected:    finding.Expr,
+			Actual:      finding.Message,
+			Severity:    SeverityWarning,
+			Remediation: finding.Remediation,
+		}
+		if finding.Severity == rules.SeverityError {
+			result.Severity = SeverityError
+		}
+
+		switch finding.Status {
+		case rules.StatusOK:
+			result.Status = StatusOK
+		case rules.StatusSkip:
+			result.Status = StatusSkip
+		default:
+			result.Status = StatusWarn
+			if result.Severity == SeverityError {
+				result.Status = StatusFail
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,155 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// Statuts d'un écart entre la configuration cloud et le serveur
+const (
+	StatusMismatch       = "mismatch"
+	StatusUnits          = "units"
+	StatusPendingRestart = "pending_restart"
+	StatusOverride       = "override"
+)
+
+// Expected représente une valeur attendue d'après la configuration cloud
+// (groupe de paramètres RDS, database flags Cloud SQL, configurations Azure)
+type Expected struct {
+	Name  string
+	Value string
+}
+
+// Item représente un écart constaté
+type Item struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Unit     string `json:"unit,omitempty"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Compare confronte les valeurs attendues aux paramètres actifs du serveur et à ses surcharges
+// par rôle et par base. Les paramètres inconnus de pg_settings sont ignorés
+func Compare(expected []Expected, settings []pgsettings.Setting, roleSettings []pgsettings.RoleSetting) []Item {
+	var items []Item
+	expectedByName := make(map[string]Expected)
+
+	for _, e := range expected {
+		expectedByName[strings.ToLower(e.Name)] = e
+
+		setting, ok := pgsettings.Find(settings, e.Name)
+		if !ok || setting.PendingRestart {
+			// Les paramètres en attente de redémarrage sont signalés plus bas
+			continue
+		}
+
+		equal, unitsOnly := pgsettings.Compare(e.Value, setting.Setting, setting.Unit)
+		switch {
+		case equal && unitsOnly:
+			items = append(items, Item{
+				Name:     setting.Name,
+				Expected: e.Value,
+				Actual:   setting.Setting,
+				Unit:     setting.Unit,
+				Status:   StatusUnits,
+			})
+		case !equal:
+			items = append(items, Item{
+				Name:     setting.Name,
+				Expected: e.Value,
+				Actual:   setting.Setting,
+				Unit:     setting.Unit,
+				Status:   StatusMismatch,
+				Detail:   sourceDetail(setting),
+			})
+		}
+	}
+
+	for _, setting := range settings {
+		if !setting.PendingRestart {
+			continue
+		}
+		item := Item{
+			Name:   setting.Name,
+			Actual: setting.Setting,
+			Unit:   setting.Unit,
+			Status: StatusPendingRestart,
+			Detail: sourceDetail(setting),
+		}
+		if e, ok := expectedByName[strings.ToLower(setting.Name)]; ok {
+			item.Expected = e.Value
+		}
+		items = append(items, item)
+	}
+
+	for _, roleSetting := range roleSettings {
+		e, ok := expectedByName[strings.ToLower(roleSetting.Name)]
+		if !ok {
+			continue
+		}
+		unit := ""
+		if setting, ok := pgsettings.Find(settings, roleSetting.Name); ok {
+			unit = setting.Unit
+		}
+		items = append(items, Item{
+			Name:     roleSetting.Name,
+			Expected: e.Value,
+			Actual:   roleSetting.Value,
+			Unit:     unit,
+			Status:   StatusOverride,
+			Detail:   fmt.Sprintf("database=%s role=%s", roleSetting.Database, roleSetting.Role),
+		})
+	}
+
+	return items
+}
+
+// sourceDetail décrit l'origine de la valeur active (ALTER SYSTEM, fichier de configuration...)
+func sourceDetail(setting pgsettings.Setting) string {
+	if strings.HasSuffix(setting.SourceFile, "postgresql.auto.conf") {
+		return "source=ALTER SYSTEM"
+	}
+	return fmt.Sprintf("source=%s", setting.Source)
+}
+
+// PrintItems affiche le rapport de dérive au format demandé (default, csv, json)
+func PrintItems(format string, items []Item) error {
+	if format == "json" {
+		return output.PrintJSON(items)
+	}
+
+	columns := []string{"Name", "Expected", "Actual", "Unit", "Status", "Detail"}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []string{item.Name, item.Expected, item.Actual, item.Unit, item.Status, item.Detail})
+	}
+	return output.PrintRows(format, columns, rows)
+}
+
+// Report se connecte au serveur et compare sa configuration active aux valeurs attendues
+func Report(ctx context.Context, connection postgres.Connection, expected []Expected) ([]Item, error) {
+	conn, err := postgres.Connect(ctx, connection)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: Connect: %w", err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	settings, err := pgsettings.LoadSettings(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("pgsettings: LoadSettings: %w", err)
+	}
+
+	roleSettings, err := pgsettings.LoadRoleSettings(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("pgsettings: LoadRoleSettings: %w", err)
+	}
+
+	return Compare(expected, settings, roleSettings), nil
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/metrics"
+)
+
+// Event est un événement d'une instance : redémarrage, bascule, modification de paramètres, maintenance...
+// (événements RDS, opérations Cloud SQL, journal d'activité Azure)
+type Event struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	Source   string    `json:"source"`
+	Category string    `json:"category"`
+	Message  string    `json:"message"`
+}
+
+// Sort trie les événements par date croissante, pour les aligner sur les logs et les métriques
+func Sort(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+}
+
+// Write écrit la chronologie des événements dans <directory>/events/<resource>.events.csv et .json
+// et retourne les fichiers écrits
+func Write(directory string, resource string, events []Event) ([]string, error) {
+	eventsPath := filepath.Join(directory, "events")
+	err := os.MkdirAll(eventsPath, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	Sort(events)
+	csvPath := filepath.Join(eventsPath, resource+".events.csv")
+	if err := WriteCSV(csvPath, events); err != nil {
+		return nil, fmt.Errorf("WriteCSV: %w", err)
+	}
+	jsonPath := filepath.Join(eventsPath, resource+".events.json")
+	if err := WriteJSON(jsonPath, events); err != nil {
+		return nil, fmt.Errorf("WriteJSON: %w", err)
+	}
+	return []string{csvPath, jsonPath}, nil
+}
+
+// WriteCSV écrit les événements dans un fichier CSV séparé par des points-virgules, comme les métriques
+func WriteCSV(path string, events []Event) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	writer := csv.NewWriter(file)
+	writer.Comma = ';'
+	records := [][]string{{"Time", "Provider", "Source", "Category", "Message"}}
+	for _, event := range events {
+		records = append(records, []string{
+			event.Time.UTC().Format(time.RFC3339),
+			event.Provider,
+			event.Source,
+			event.Category,
+			event.Message,
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("csv.WriteAll: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON écrit les événements dans un fichier JSON
+func WriteJSON(path string, events []Event) error {
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("WriteFile: %w", err)
+	}
+	return nil
+}
+
+// Markers convertit les événements en repères affichés sur les graphes et la page HTML des métriques
+func Markers(events []Event) []metrics.Marker {
+	markers := make([]metrics.Marker, 0, len(events))
+	for _, event := range events {
+		markers = append(markers, metrics.Marker{
+			Time:  event.Time,
+			Label: fmt.Sprintf("%s: %s", event.Category, event.Message),
+		})
+	}
+	return markers
+}
@@ -0,0 +1,264 @@
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// GiB est le nombre d'octets d'un gibioctet, unité des tailles de stockage chez les fournisseurs
+const GiB = 1024 * 1024 * 1024
+
+// Headroom est la part d'espace libre conservée lors d'une recommandation de taille
+const Headroom = 0.2
+
+// IopsMargin est la marge appliquée au pic d'IOPS observé lors d'une recommandation
+const IopsMargin = 1.2
+
+// ErrNotEnoughSamples est retournée lorsque l'historique ne permet pas d'ajuster une tendance
+var ErrNotEnoughSamples = errors.New("pas assez de points pour ajuster une tendance")
+
+// Sample représente une valeur de métrique horodatée
+type Sample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// Trend est la droite ajustée par la méthode des moindres carrés. Slope est exprimée par jour
+type Trend struct {
+	Origin    time.Time
+	Slope     float64
+	Intercept float64
+}
+
+// Range est une plage de valeurs autorisées (taille de stockage, IOPS)
+type Range struct {
+	From int
+	To   int
+	Step int
+}
+
+// Fit ajuste une tendance linéaire aux points
+func Fit(samples []Sample) (Trend, error) {
+	if len(samples) < 2 {
+		return Trend{}, ErrNotEnoughSamples
+	}
+
+	sorted := append([]Sample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	trend := Trend{Origin: sorted[0].Timestamp}
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range sorted {
+		x := sample.Timestamp.Sub(trend.Origin).Hours() / 24
+		sumX += x
+		sumY += sample.Value
+		sumXY += x * sample.Value
+		sumXX += x * x
+	}
+
+	n := float64(len(sorted))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return Trend{}, ErrNotEnoughSamples
+	}
+	trend.Slope = (n*sumXY - sumX*sumY) / denominator
+	trend.Intercept = (sumY - trend.Slope*sumX) / n
+	return trend, nil
+}
+
+// At retourne la valeur de la tendance à une date
+func (t Trend) At(date time.Time) float64 {
+	return t.Intercept + t.Slope*date.Sub(t.Origin).Hours()/24
+}
+
+// DaysUntil retourne le nombre de jours avant que la tendance atteigne la limite,
+// et false si elle ne l'atteint jamais (croissance nulle ou négative)
+func (t Trend) DaysUntil(from time.Time, limit float64) (int, bool) {
+	current := t.At(from)
+	if current >= limit {
+		return 0, true
+	}
+	if t.Slope <= 0 {
+		return 0, false
+	}
+	return int(math.Floor((limit - current) / t.Slope)), true
+}
+
+// RoundUp retourne la plus petite valeur autorisée supérieure ou égale à la cible,
+// ou la plus grande valeur autorisée si la cible les dépasse toutes (false)
+func RoundUp(ranges []Range, target int) (int, bool) {
+	best, maximum := 0, 0
+	found := false
+	for _, r := range ranges {
+		if r.To > maximum {
+			maximum = r.To
+		}
+		if target > r.To {
+			continue
+		}
+
+		value := r.From
+		if target > r.From {
+			step := max(r.Step, 1)
+			value = r.From + (target-r.From+step-1)/step*step
+			value = min(value, r.To)
+		}
+		if !found || value < best {
+			best = value
+			found = true
+		}
+	}
+	if !found {
+		return maximum, false
+	}
+	return best, true
+}
+
+// Peak retourne la valeur maximale des points
+func Peak(samples []Sample) float64 {
+	peak := 0.0
+	for _, sample := range samples {
+		peak = max(peak, sample.Value)
+	}
+	return peak
+}
+
+// Sum additionne des séries horodatées point à point (ex: ReadIOPS + WriteIOPS)
+func Sum(series ...[]Sample) []Sample {
+	totals := make(map[time.Time]float64)
+	for _, samples := range series {
+		for _, sample := range samples {
+			totals[sample.Timestamp] += sample.Value
+		}
+	}
+
+	result := make([]Sample, 0, len(totals))
+	for timestamp, value := range totals {
+		result = append(result, Sample{Timestamp: timestamp, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result
+}
+
+// StorageForecast est la prévision d'occupation du stockage d'une instance
+type StorageForecast struct {
+	Provider             string   `json:"provider"`
+	Resource             string   `json:"resource"`
+	HistoryDays          int      `json:"history_days"`
+	Samples              int      `json:"samples"`
+	UsedGB               float64  `json:"used_gb"`
+	AllocatedGB          int      `json:"allocated_gb"`
+	GrowthGBPerDay       float64  `json:"growth_gb_per_day"`
+	DaysUntilFull        *int     `json:"days_until_full,omitempty"`
+	MaxStorageGB         int      `json:"max_storage_gb,omitempty"`
+	DaysUntilMaxStorage  *int     `json:"days_until_max_storage,omitempty"`
+	HorizonDays          int      `json:"horizon_days"`
+	ProjectedUsedGB      float64  `json:"projected_used_gb"`
+	RecommendedStorageGB int      `json:"recommended_storage_gb"`
+	Iops                 int      `json:"iops,omitempty"`
+	PeakIops             float64  `json:"peak_iops,omitempty"`
+	RecommendedIops      int      `json:"recommended_iops,omitempty"`
+	Notes                []string `json:"notes,omitempty"`
+}
+
+// Storage ajuste une tendance à l'espace utilisé (en octets) et calcule l'échéance à laquelle
+// le disque sera plein, puis celle du plafond d'autoscaling (maxStorageGB, 0 si absent)
+func Storage(used []Sample, allocatedGB int, maxStorageGB int, historyDays int, horizonDays int) (StorageForecast, error) {
+	forecast := StorageForecast{
+		HistoryDays:  historyDays,
+		Samples:      len(used),
+		AllocatedGB:  allocatedGB,
+		MaxStorageGB: maxStorageGB,
+		HorizonDays:  horizonDays,
+	}
+
+	trend, err := Fit(used)
+	if err != nil {
+		return forecast, err
+	}
+
+	now := time.Now()
+	forecast.UsedGB = round(trend.At(now) / GiB)
+	forecast.GrowthGBPerDay = round(trend.Slope / GiB)
+	forecast.ProjectedUsedGB = round(trend.At(now.AddDate(0, 0, horizonDays)) / GiB)
+
+	if days, ok := trend.DaysUntil(now, float64(allocatedGB)*GiB); ok {
+		forecast.DaysUntilFull = &days
+	}
+	if maxStorageGB > allocatedGB {
+		if days, ok := trend.DaysUntil(now, float64(maxStorageGB)*GiB); ok {
+			forecast.DaysUntilMaxStorage = &days
+		}
+	}
+	return forecast, nil
+}
+
+// TargetStorageGB retourne la taille nécessaire pour absorber la croissance jusqu'à l'horizon
+// en conservant la marge d'espace libre
+func (f StorageForecast) TargetStorageGB() int {
+	return int(math.Ceil(max(f.ProjectedUsedGB, f.UsedGB) / (1 - Headroom)))
+}
+
+// RecommendStorage fixe la taille recommandée dans les plages autorisées ; le stockage ne pouvant
+// pas être réduit, elle n'est jamais inférieure à la taille allouée
+func (f *StorageForecast) RecommendStorage(ranges []Range) {
+	target := max(f.TargetStorageGB(), f.AllocatedGB)
+	recommended, ok := RoundUp(ranges, target)
+	if !ok {
+		f.Notes = append(f.Notes, fmt.Sprintf("la taille nécessaire (%d GB) dépasse la taille maximale autorisée", target))
+	}
+	f.RecommendedStorageGB = max(recommended, f.AllocatedGB)
+}
+
+// Print affiche la prévision au format demandé (default, csv, json, markdown)
+func Print(format string, forecast StorageForecast) error {
+	if format == "json" {
+		return output.PrintJSON(forecast)
+	}
+
+	rows := [][]string{
+		{"Resource", forecast.Resource},
+		{"History", fmt.Sprintf("%d days (%d samples)", forecast.HistoryDays, forecast.Samples)},
+		{"Used", fmt.Sprintf("%.2f GB", forecast.UsedGB)},
+		{"Allocated", fmt.Sprintf("%d GB", forecast.AllocatedGB)},
+		{"Growth", fmt.Sprintf("%.2f GB/day", forecast.GrowthGBPerDay)},
+		{"Days until full", formatDays(forecast.DaysUntilFull)},
+	}
+	if forecast.MaxStorageGB > forecast.AllocatedGB {
+		rows = append(rows,
+			[]string{"Max storage", fmt.Sprintf("%d GB", forecast.MaxStorageGB)},
+			[]string{"Days until max storage", formatDays(forecast.DaysUntilMaxStorage)},
+		)
+	}
+	rows = append(rows,
+		[]string{"Projected used", fmt.Sprintf("%.2f GB in %d days", forecast.ProjectedUsedGB, forecast.HorizonDays)},
+		[]string{"Recommended storage", fmt.Sprintf("%d GB", forecast.RecommendedStorageGB)},
+	)
+	if forecast.RecommendedIops > 0 {
+		rows = append(rows,
+			[]string{"IOPS", fmt.Sprintf("%d (peak %.0f)", forecast.Iops, forecast.PeakIops)},
+			[]string{"Recommended IOPS", fmt.Sprintf("%d", forecast.RecommendedIops)},
+		)
+	}
+	if len(forecast.Notes) > 0 {
+		rows = append(rows, []string{"Notes", strings.Join(forecast.Notes, "; ")})
+	}
+	return output.PrintRows(format, []string{"Field", "Value"}, rows)
+}
+
+func formatDays(days *int) string {
+	if days == nil {
+		return "never"
+	}
+	return fmt.Sprintf("%d", *days)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,77 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFit(t *testing.T) {
+	origin := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Croissance de 2 GiB par jour à partir de 100 GiB, points non triés
+	var samples []Sample
+	for _, day := range []int{3, 0, 2, 1, 4} {
+		samples = append(samples, Sample{Timestamp: origin.AddDate(0, 0, day), Value: float64(100+2*day) * GiB})
+	}
+
+	trend, err := Fit(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trend.Origin.Equal(origin) {
+		t.Errorf("Origin = %v, attendu %v", trend.Origin, origin)
+	}
+	if math.Abs(trend.Slope-2*GiB) > 1 || math.Abs(trend.Intercept-100*GiB) > 1 {
+		t.Errorf("Slope = %.0f, Intercept = %.0f, attendu %d et %d", trend.Slope, trend.Intercept, 2*GiB, 100*GiB)
+	}
+
+	days, ok := trend.DaysUntil(origin.AddDate(0, 0, 4), 200*GiB)
+	if !ok || days != 46 {
+		t.Errorf("DaysUntil = %d (%v), attendu 46", days, ok)
+	}
+
+	// Une tendance décroissante n'atteint jamais la limite
+	decreasing := Trend{Origin: origin, Slope: -GiB, Intercept: 100 * GiB}
+	if _, ok := decreasing.DaysUntil(origin, 200*GiB); ok {
+		t.Errorf("DaysUntil: tendance décroissante, limite atteinte")
+	}
+
+	for _, samples := range [][]Sample{nil, samples[:1], {{Timestamp: origin, Value: 1}, {Timestamp: origin, Value: 2}}} {
+		if _, err := Fit(samples); !errors.Is(err, ErrNotEnoughSamples) {
+			t.Errorf("%d points: %v, ErrNotEnoughSamples attendu", len(samples), err)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	ranges := []Range{
+		{From: 20, To: 6144, Step: 1},
+		{From: 6144, To: 65536, Step: 1024},
+	}
+
+	tests := []struct {
+		target int
+		value  int
+		ok     bool
+	}{
+		{10, 20, true},
+		{100, 100, true},
+		{6144, 6144, true},
+		{7000, 7168, true},
+		{65536, 65536, true},
+		{70000, 65536, false},
+	}
+	for _, tt := range tests {
+		value, ok := RoundUp(ranges, tt.target)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("RoundUp(%d) = %d (%v), attendu %d (%v)", tt.target, value, ok, tt.value, tt.ok)
+		}
+	}
+
+	// Un pas nul est traité comme un pas de 1
+	if value, _ := RoundUp([]Range{{From: 1000, To: 3000}}, 1500); value != 1500 {
+		t.Errorf("RoundUp sans pas = %d, attendu 1500", value)
+	}
+}
@@ -0,0 +1,105 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/sqladmin/v1"
+
+	"github.com/robinportigliatti/cloud_helper/internal/backup"
+)
+
+// ListBackupRuns retourne les sauvegardes de l'instance (backupRuns), automatiques et à la demande
+func (g *GCP) ListBackupRuns() ([]backup.Snapshot, error) {
+	if g.instance == nil {
+		return nil, fmt.Errorf("no instance initialized")
+	}
+
+	var snapshots []backup.Snapshot
+	err := g.service.BackupRuns.List(g.projectID, g.instanceName).Pages(context.Background(), func(page *sqladmin.BackupRunsListResponse) error {
+		for _, run := range page.Items {
+			snapshotType := backup.TypeManual
+			if run.Type == "AUTOMATED" {
+				snapshotType = backup.TypeAutomated
+			}
+			created, _ := time.Parse(time.RFC3339, run.EndTime)
+			if created.IsZero() {
+				created, _ = time.Parse(time.RFC3339, run.WindowStartTime)
+			}
+			snapshots = append(snapshots, backup.Snapshot{
+				Name:     strconv.FormatInt(run.Id, 10),
+				Type:     snapshotType,
+				Status:   run.Status,
+				Created:  created,
+				Location: run.Location,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GCP: BackupRuns.List: %w", err)
+	}
+	return snapshots, nil
+}
+
+// BackupReport retourne la configuration de sauvegarde de l'instance. Avec PITR, la fenêtre de restauration
+// couvre les journaux de transactions conservés (transactionLogRetentionDays) ; sans PITR, elle va de la plus
+// ancienne à la plus récente sauvegarde réussie. Sans emplacement personnalisé, les sauvegardes sont stockées
+// dans la multirégion la plus proche de l'instance et donc copiées hors de sa région
+func (g *GCP) BackupReport() (backup.Report, error) {
+	if g.instance == nil {
+		return backup.Report{}, fmt.Errorf("no instance initialized")
+	}
+	configuration := g.instance.Settings.BackupConfiguration
+
+	crossRegion := configuration.Enabled && configuration.Location != g.instance.Region
+	report := backup.Report{
+		Provider:     "gcp",
+		Resource:     g.instanceName,
+		Region:       g.instance.Region,
+		BackupWindow: configuration.StartTime,
+		PITR:         configuration.Enabled && configuration.PointInTimeRecoveryEnabled,
+		CrossRegion:  &crossRegion,
+	}
+	// Cloud SQL exprime la rétention en nombre de sauvegardes (retentionUnit COUNT), pas en jours
+	if configuration.Enabled {
+		switch unit := configuration.BackupRetentionSettings.RetentionUnit; unit {
+		case "", "COUNT", "RETENTION_UNIT_UNSPECIFIED":
+			report.RetainedBackups = configuration.BackupRetentionSettings.RetainedBackups
+		default:
+			slog.Warn("Unité de rétention des sauvegardes inconnue", slog.String("instance", g.instanceName), slog.String("unit", unit))
+		}
+	}
+
+	snapshots, err := g.ListBackupRuns()
+	if err != nil {
+		return report, err
+	}
+	report.SetSnapshots(snapshots)
+
+	var oldest, latest time.Time
+	for _, snapshot := range report.Snapshots {
+		if snapshot.Status != "SUCCESSFUL" || snapshot.Created.IsZero() {
+			continue
+		}
+		if latest.IsZero() {
+			latest = snapshot.Created
+		}
+		oldest = snapshot.Created
+	}
+
+	if report.PITR {
+		now := time.Now()
+		from := now.AddDate(0, 0, -configuration.TransactionLogRetentionDays)
+		if from.Before(oldest) {
+			from = oldest
+		}
+		report.SetWindow(from, now)
+	} else {
+		report.SetWindow(oldest, latest)
+	}
+	return report, nil
+}
@@ -0,0 +1,32 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// GetConnection retourne les paramètres de connexion à l'adresse IP de l'instance (PRIMARY en priorité)
+// Sans mot de passe, psql et pgx utilisent PGPASSWORD ou le fichier .pgpass
+func (g *GCP) GetConnection(username string, database string, password string) (postgres.Connection, error) {
+	if g.instance == nil || len(g.instance.IPAddresses) == 0 {
+		return postgres.Connection{}, fmt.Errorf("no instance or IP address")
+	}
+
+	host := g.instance.IPAddresses[0].IPAddress
+	for _, ip := range g.instance.IPAddresses {
+		if ip.Type == "PRIMARY" {
+			host = ip.IPAddress
+			break
+		}
+	}
+
+	return postgres.Connection{
+		Host:     host,
+		Port:     5432,
+		Database: database,
+		Username: username,
+		Password: password,
+		SSLMode:  "require",
+	}, nil
+}
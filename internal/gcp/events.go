@@ -0,0 +1,61 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/sqladmin/v1"
+
+	"github.com/robinportigliatti/cloud_helper/internal/events"
+)
+
+// Events retourne les opérations Cloud SQL de l'instance démarrées entre start et end (operations.list) :
+// redémarrages, bascules, modifications de flags, maintenance, sauvegardes...
+func (g *GCP) Events(start time.Time, end time.Time) ([]events.Event, error) {
+	if g.instanceName == "" {
+		return nil, fmt.Errorf("instance name is required")
+	}
+
+	var result []events.Event
+	err := g.service.Operations.List(g.projectID).Instance(g.instanceName).Pages(context.Background(), func(page *sqladmin.OperationsListResponse) error {
+		for _, operation := range page.Items {
+			eventTime, _ := time.Parse(time.RFC3339, operation.StartTime)
+			if eventTime.IsZero() {
+				eventTime, _ = time.Parse(time.RFC3339, operation.InsertTime)
+			}
+			if eventTime.Before(start) || eventTime.After(end) {
+				continue
+			}
+			result = append(result, events.Event{
+				Time:     eventTime,
+				Provider: "gcp",
+				Source:   g.instanceName,
+				Category: operation.OperationType,
+				Message:  operationMessage(operation),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GCP: Operations.List: %w", err)
+	}
+
+	events.Sort(result)
+	return result, nil
+}
+
+// operationMessage décrit l'état d'une opération, son auteur et sa première erreur
+func operationMessage(operation *sqladmin.Operation) string {
+	message := operation.Status
+	if operation.User != "" {
+		message = fmt.Sprintf("%s by %s", message, operation.User)
+	}
+	if operation.EndTime != "" {
+		message = fmt.Sprintf("%s, ended %s", message, operation.EndTime)
+	}
+	if operation.Error != nil && len(operation.Error.Errors) > 0 {
+		message = fmt.Sprintf("%s: %s", message, operation.Error.Errors[0].Message)
+	}
+	return message
+}
@@ -0,0 +1,40 @@
+package gcp
+
+import (
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// Facts retourne le modèle commun aux fournisseurs utilisé par le moteur de règles
+func (g *GCP) Facts() rules.Instance {
+	facts := rules.Instance{
+		Provider:    "gcp",
+		Identifier:  g.instanceName,
+		MachineType: g.GetTier(),
+		VCPU:        g.GetVCpus(),
+		MemoryBytes: int64(g.GetMemoryMb()) * 1024 * 1024,
+		Parameters:  make(map[string]rules.Parameter),
+	}
+
+	if g.instance == nil {
+		return facts
+	}
+
+	facts.EngineVersion = g.instance.DatabaseVersion
+	facts.StorageGB = int(g.instance.Settings.DataDiskSizeGb)
+	facts.HighAvailability = g.instance.Settings.AvailabilityType == "REGIONAL"
+
+	// Cloud SQL conserve un nombre de sauvegardes quotidiennes et non une durée :
+	// backup_retention n'est défini que si les sauvegardes sont désactivées
+	backup := g.instance.Settings.BackupConfiguration
+	switch unit := backup.BackupRetentionSettings.RetentionUnit; {
+	case !backup.Enabled:
+		facts.BackupRetentionDays = new(int)
+	case unit == "" || unit == "COUNT" || unit == "RETENTION_UNIT_UNSPECIFIED":
+		facts.RetainedBackups = backup.BackupRetentionSettings.RetainedBackups
+	}
+
+	for _, flag := range g.instance.Settings.DatabaseFlags {
+		facts.Parameters[flag.Name] = rules.Parameter{Value: flag.Value}
+	}
+	return facts
+}
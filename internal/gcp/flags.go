@@ -1,5 +1,14 @@
 package gcp
 
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
 type FlagMetadata struct {
 	Name            string   `json:"name"`
 	Type            string   `json:"type"`
@@ -27,3 +36,52 @@ func (d *DescribeFlagsResult) GetFlagValueByName(flagName string, instance *Data
 
 	return "", nil
 }
+
+// GetFlagMetadataByName retourne les métadonnées d'un flag PostgreSQL
+func (d *DescribeFlagsResult) GetFlagMetadataByName(flagName string) (FlagMetadata, error) {
+	for _, flag := range d.Items {
+		if flag.Name == flagName {
+			return flag, nil
+		}
+	}
+	return FlagMetadata{}, fmt.Errorf("flag %s not found", flagName)
+}
+
+// Validate vérifie qu'une valeur est acceptable pour le flag (type, bornes, valeurs autorisées)
+// et retourne la valeur à transmettre à Cloud SQL (les booléens sont convertis en on/off)
+func (f FlagMetadata) Validate(value string) (string, error) {
+	switch f.Type {
+	case "BOOLEAN":
+		normalized, ok := pgsettings.NormalizeBool(value)
+		if !ok {
+			return "", fmt.Errorf("%s attend un booléen (valeur : %s)", f.Name, value)
+		}
+		return normalized, nil
+	case "INTEGER", "FLOAT":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || (f.Type == "INTEGER" && number != float64(int64(number))) {
+			return "", fmt.Errorf("%s attend une valeur de type %s (valeur : %s)", f.Name, strings.ToLower(f.Type), value)
+		}
+		if f.MinValue != nil && number < float64(*f.MinValue) {
+			return "", fmt.Errorf("%s doit être supérieur ou égal à %d (valeur : %s)", f.Name, *f.MinValue, value)
+		}
+		if f.MaxValue != nil && number > float64(*f.MaxValue) {
+			return "", fmt.Errorf("%s doit être inférieur ou égal à %d (valeur : %s)", f.Name, *f.MaxValue, value)
+		}
+	case "NONE":
+		if value != "" {
+			return "", fmt.Errorf("%s n'accepte pas de valeur", f.Name)
+		}
+	}
+
+	if len(f.AllowedValues) > 0 {
+		values := strings.Split(value, ",")
+		for _, v := range values {
+			if !slices.ContainsFunc(f.AllowedValues, func(allowed string) bool { return strings.EqualFold(allowed, strings.TrimSpace(v)) }) {
+				return "", fmt.Errorf("%s n'accepte que %s (valeur : %s)", f.Name, strings.Join(f.AllowedValues, ","), v)
+			}
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,134 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoring "google.golang.org/api/monitoring/v3"
+	"google.golang.org/api/option"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+)
+
+// Taille maximale d'un disque Cloud SQL, en GB
+const maxDataDiskSizeGb = 65536
+
+// ListTimeSeries retourne les séries Cloud Monitoring d'une métrique de l'instance,
+// agrégées par heure avec l'aligneur demandé (ALIGN_MAX, ALIGN_MEAN...)
+func (g *GCP) ListTimeSeries(metricType string, aligner string, startTime time.Time, endTime time.Time) (ListTimeSeriesResult, error) {
+	return g.instanceTimeSeries(g.instanceName, metricType, aligner, startTime, endTime)
+}
+
+// instanceTimeSeries retourne les séries Cloud Monitoring d'une métrique d'une instance du projet
+func (g *GCP) instanceTimeSeries(instanceName string, metricType string, aligner string, startTime time.Time, endTime time.Time) (ListTimeSeriesResult, error) {
+	var result ListTimeSeriesResult
+
+	ctx := context.Background()
+	var opts []option.ClientOption
+	if g.credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(g.credentialsFile))
+	}
+	opts = append(opts, option.WithScopes(monitoring.MonitoringReadScope))
+
+	service, err := monitoring.NewService(ctx, opts...)
+	if err != nil {
+		return result, fmt.Errorf("GCP: Init service Monitoring: %w", err)
+	}
+
+	filter := fmt.Sprintf(`metric.type="%s" AND resource.labels.database_id="%s:%s"`, metricType, g.projectID, instanceName)
+	call := service.Projects.TimeSeries.List("projects/" + g.projectID).
+		Filter(filter).
+		IntervalStartTime(startTime.Format(time.RFC3339)).
+		IntervalEndTime(endTime.Format(time.RFC3339)).
+		AggregationAlignmentPeriod("3600s").
+		AggregationPerSeriesAligner(aligner)
+
+	err = call.Pages(ctx, func(response *monitoring.ListTimeSeriesResponse) error {
+		for _, sdkSeries := range response.TimeSeries {
+			series := TimeSeries{
+				MetricKind: sdkSeries.MetricKind,
+				ValueType:  sdkSeries.ValueType,
+				Unit:       sdkSeries.Unit,
+			}
+			if sdkSeries.Metric != nil {
+				series.Metric = Metric{Type: sdkSeries.Metric.Type, Labels: sdkSeries.Metric.Labels}
+			}
+			if sdkSeries.Resource != nil {
+				series.Resource = MonitoredResource{Type: sdkSeries.Resource.Type, Labels: sdkSeries.Resource.Labels}
+			}
+
+			for _, sdkPoint := range sdkSeries.Points {
+				if sdkPoint.Interval == nil || sdkPoint.Value == nil {
+					continue
+				}
+				point := TimeSeriesPoint{
+					Value: PointValue{
+						DoubleValue: sdkPoint.Value.DoubleValue,
+						Int64Value:  sdkPoint.Value.Int64Value,
+					},
+				}
+				point.Interval.StartTime, _ = time.Parse(time.RFC3339, sdkPoint.Interval.StartTime)
+				point.Interval.EndTime, _ = time.Parse(time.RFC3339, sdkPoint.Interval.EndTime)
+				series.Points = append(series.Points, point)
+			}
+			result.TimeSeries = append(result.TimeSeries, series)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("GCP: TimeSeries.List: %w", err)
+	}
+
+	return result, nil
+}
+
+// ForecastStorage ajuste une tendance à l'espace disque utilisé (database/disk/bytes_used) sur les
+// derniers jours et estime l'échéance du disque plein, ou de la limite d'augmentation automatique
+func (g *GCP) ForecastStorage(historyDays int, horizonDays int) (forecast.StorageForecast, error) {
+	if g.instance == nil {
+		return forecast.StorageForecast{}, fmt.Errorf("no instance initialized")
+	}
+	settings := g.instance.Settings
+
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -historyDays)
+
+	series, err := g.ListTimeSeries("cloudsql.googleapis.com/database/disk/bytes_used", "ALIGN_MAX", startTime, endTime)
+	if err != nil {
+		return forecast.StorageForecast{}, fmt.Errorf("ListTimeSeries: %w", err)
+	}
+
+	var used []forecast.Sample
+	for _, timeSeries := range series.TimeSeries {
+		for _, point := range timeSeries.Points {
+			used = append(used, forecast.Sample{Timestamp: point.Interval.EndTime, Value: point.GetValue()})
+		}
+	}
+
+	// Avec l'augmentation automatique, le disque grandit jusqu'à la limite (sans limite : taille maximale)
+	maxStorageGB := 0
+	if settings.StorageAutoResize {
+		maxStorageGB = maxDataDiskSizeGb
+		if settings.StorageAutoResizeLimit > 0 {
+			maxStorageGB = int(settings.StorageAutoResizeLimit)
+		}
+	}
+
+	storageForecast, err := forecast.Storage(used, int(settings.DataDiskSizeGb), maxStorageGB, historyDays, horizonDays)
+	if err != nil {
+		return storageForecast, fmt.Errorf("forecast.Storage: %w", err)
+	}
+	storageForecast.Provider = "gcp"
+	storageForecast.Resource = g.instanceName
+
+	storageForecast.RecommendStorage([]forecast.Range{{From: 10, To: maxDataDiskSizeGb, Step: 1}})
+	if !settings.StorageAutoResize {
+		storageForecast.Notes = append(storageForecast.Notes, "l'augmentation automatique du stockage est désactivée (storageAutoResize)")
+	}
+	if storageForecast.DaysUntilMaxStorage != nil && *storageForecast.DaysUntilMaxStorage <= horizonDays {
+		storageForecast.Notes = append(storageForecast.Notes, "la limite d'augmentation automatique sera atteinte avant l'horizon, augmenter storageAutoResizeLimit")
+	}
+	storageForecast.Notes = append(storageForecast.Notes, fmt.Sprintf("les IOPS d'un disque %s dépendent de sa taille", settings.DataDiskType))
+	return storageForecast, nil
+}
@@ -18,6 +18,8 @@ import (
 	"google.golang.org/api/compute/v1"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sqladmin/v1"
+
+	"github.com/robinportigliatti/cloud_helper/internal/check"
 )
 
 // GCP structure principale pour gérer Cloud SQL sur GCP
@@ -142,6 +144,19 @@ func (g *GCP) DescribeInstance() error {
 		InstanceType:    instanceResp.InstanceType,
 		SelfLink:        instanceResp.SelfLink,
 		GceZone:         instanceResp.GceZone,
+
+		MaintenanceVersion:           instanceResp.MaintenanceVersion,
+		AvailableMaintenanceVersions: instanceResp.AvailableMaintenanceVersions,
+		MasterInstanceName:           instanceResp.MasterInstanceName,
+		ReplicaNames:                 instanceResp.ReplicaNames,
+	}
+
+	for _, version := range instanceResp.UpgradableDatabaseVersions {
+		g.instance.UpgradableDatabaseVersions = append(g.instance.UpgradableDatabaseVersions, AvailableDatabaseVersion{
+			MajorVersion: version.MajorVersion,
+			Name:         version.Name,
+			DisplayName:  version.DisplayName,
+		})
 	}
 
 	// Copier les IP addresses
@@ -152,17 +167,42 @@ func (g *GCP) DescribeInstance() error {
 		})
 	}
 
+	if instanceResp.ServerCaCert != nil {
+		expiration, _ := time.Parse(time.RFC3339, instanceResp.ServerCaCert.ExpirationTime)
+		g.instance.ServerCaCert = &SslCert{
+			CommonName:     instanceResp.ServerCaCert.CommonName,
+			ExpirationTime: expiration,
+		}
+	}
+
 	// Copier les settings
 	if instanceResp.Settings != nil {
 		g.instance.Settings = Settings{
-			Tier:                   instanceResp.Settings.Tier,
-			ActivationPolicy:       instanceResp.Settings.ActivationPolicy,
-			DataDiskSizeGb:         instanceResp.Settings.DataDiskSizeGb,
-			DataDiskType:           instanceResp.Settings.DataDiskType,
-			PricingPlan:            instanceResp.Settings.PricingPlan,
-			StorageAutoResize:      instanceResp.Settings.StorageAutoResize != nil && *instanceResp.Settings.StorageAutoResize,
-			StorageAutoResizeLimit: instanceResp.Settings.StorageAutoResizeLimit,
-			AvailabilityType:       instanceResp.Settings.AvailabilityType,
+			Tier:                      instanceResp.Settings.Tier,
+			ActivationPolicy:          instanceResp.Settings.ActivationPolicy,
+			DataDiskSizeGb:            instanceResp.Settings.DataDiskSizeGb,
+			DataDiskType:              instanceResp.Settings.DataDiskType,
+			PricingPlan:               instanceResp.Settings.PricingPlan,
+			StorageAutoResize:         instanceResp.Settings.StorageAutoResize != nil && *instanceResp.Settings.StorageAutoResize,
+			StorageAutoResizeLimit:    instanceResp.Settings.StorageAutoResizeLimit,
+			AvailabilityType:          instanceResp.Settings.AvailabilityType,
+			DeletionProtectionEnabled: instanceResp.Settings.DeletionProtectionEnabled,
+		}
+
+		// Copier la configuration réseau
+		if ipConfiguration := instanceResp.Settings.IpConfiguration; ipConfiguration != nil {
+			g.instance.Settings.IPConfiguration = IPConfiguration{
+				Ipv4Enabled:    ipConfiguration.Ipv4Enabled,
+				RequireSsl:     ipConfiguration.RequireSsl,
+				SslMode:        ipConfiguration.SslMode,
+				PrivateNetwork: ipConfiguration.PrivateNetwork,
+			}
+			for _, network := range ipConfiguration.AuthorizedNetworks {
+				g.instance.Settings.IPConfiguration.AuthorizedNetworks = append(g.instance.Settings.IPConfiguration.AuthorizedNetworks, AclEntry{
+					Name:  network.Name,
+					Value: network.Value,
+				})
+			}
 		}
 
 		// Copier les database flags
@@ -176,9 +216,17 @@ func (g *GCP) DescribeInstance() error {
 		// Copier la configuration de backup
 		if instanceResp.Settings.BackupConfiguration != nil {
 			g.instance.Settings.BackupConfiguration = BackupConfiguration{
-				Enabled:                    instanceResp.Settings.BackupConfiguration.Enabled,
-				StartTime:                  instanceResp.Settings.BackupConfiguration.StartTime,
-				PointInTimeRecoveryEnabled: instanceResp.Settings.BackupConfiguration.PointInTimeRecoveryEnabled,
+				Enabled:                     instanceResp.Settings.BackupConfiguration.Enabled,
+				StartTime:                   instanceResp.Settings.BackupConfiguration.StartTime,
+				PointInTimeRecoveryEnabled:  instanceResp.Settings.BackupConfiguration.PointInTimeRecoveryEnabled,
+				TransactionLogRetentionDays: int(instanceResp.Settings.BackupConfiguration.TransactionLogRetentionDays),
+				Location:                    instanceResp.Settings.BackupConfiguration.Location,
+			}
+			if retention := instanceResp.Settings.BackupConfiguration.BackupRetentionSettings; retention != nil {
+				g.instance.Settings.BackupConfiguration.BackupRetentionSettings = BackupRetentionSettings{
+					RetentionUnit:   retention.RetentionUnit,
+					RetainedBackups: int(retention.RetainedBackups),
+				}
 			}
 		}
 	}
@@ -269,13 +317,20 @@ func (g *GCP) GetDatabaseFlagsInformation() error {
 	for _, flag := range flagsResp.Items {
 		// Filtrer uniquement les flags PostgreSQL
 		if contains(flag.AppliesTo, "POSTGRES") {
-			g.databaseFlags.Items = append(g.databaseFlags.Items, FlagMetadata{
+			metadata := FlagMetadata{
 				Name:            flag.Name,
 				Type:            flag.Type,
 				AppliesTo:       flag.AppliesTo,
 				AllowedValues:   flag.AllowedStringValues,
 				RequiresRestart: flag.RequiresRestart,
-			})
+			}
+			// L'API omet les bornes nulles : seules les bornes renseignées sont conservées
+			if flag.MinValue != 0 || flag.MaxValue != 0 {
+				minValue, maxValue := flag.MinValue, flag.MaxValue
+				metadata.MinValue = &minValue
+				metadata.MaxValue = &maxValue
+			}
+			g.databaseFlags.Items = append(g.databaseFlags.Items, metadata)
 		}
 	}
 
@@ -351,71 +406,22 @@ func (g *GCP) GetFlagValueByName(flagName string) (string, error) {
 	return value, nil
 }
 
-func (g *GCP) CheckFlag(flagName string, flagValue string) error {
-	value, err := g.GetFlagValueByName(flagName)
+// Check évalue un profil de vérification embarqué sur les database flags de l'instance.
+// Un flag non positionné prend la valeur par défaut de PostgreSQL
+func (g *GCP) Check(profile string) ([]check.Result, error) {
+	results, err := check.Run(profile, "gcp", func(name string) (string, bool) {
+		value, err := g.GetFlagValueByName(name)
+		return value, err == nil && value != ""
+	})
 	if err != nil {
-		return fmt.Errorf("GetFlagValueByName: %w", err)
+		return nil, fmt.Errorf("check: Run: %w", err)
 	}
-
-	str := ""
-	if value != flagValue {
-		str = fmt.Sprintf("%s should be at %s", flagName, flagValue)
-	} else {
-		str = fmt.Sprintf("%s: OK", flagName)
-	}
-
-	fmt.Println(str)
-	return nil
+	return results, nil
 }
 
-func (g *GCP) CheckPgbadger() error {
-	err := g.CheckFlag("log_connections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_disconnections", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_lock_waits", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_temp_files", "0")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_autovacuum_min_duration", "0")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_min_duration_statement", "0")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_duration", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_checkpoints", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("log_statement", "all")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-	err = g.CheckFlag("track_io_timing", "on")
-	if err != nil {
-		return fmt.Errorf("CheckFlag: %w", err)
-	}
-
-	_, err = g.GetFlagValueByName("track_activity_query_size")
-	if err != nil {
-		return fmt.Errorf("GetFlagValue: %w", err)
-	}
-
-	return nil
+// CheckPgbadger vérifie les flags nécessaires à pgbadger
+func (g *GCP) CheckPgbadger() ([]check.Result, error) {
+	return g.Check("pgbadger")
 }
 
 func (g *GCP) GetAllFlagNames() ([]string, error) {
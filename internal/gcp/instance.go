@@ -5,21 +5,38 @@ import (
 )
 
 type DatabaseInstance struct {
-	Name                         string              `json:"name"`
-	Project                      string              `json:"project"`
-	DatabaseVersion              string              `json:"databaseVersion"`
-	Region                       string              `json:"region"`
-	State                        string              `json:"state"`
-	ConnectionName               string              `json:"connectionName"`
-	IPAddresses                  []IPMapping         `json:"ipAddresses"`
-	Settings                     Settings            `json:"settings"`
-	BackupConfiguration          BackupConfiguration `json:"backupConfiguration,omitempty"`
-	CreateTime                   time.Time           `json:"createTime"`
-	InstanceType                 string              `json:"instanceType"`
-	MaintenanceVersion           string              `json:"maintenanceVersion,omitempty"`
-	AvailableMaintenanceVersions []string            `json:"availableMaintenanceVersions,omitempty"`
-	GceZone                      string              `json:"gceZone,omitempty"`
-	SelfLink                     string              `json:"selfLink"`
+	Name                         string                     `json:"name"`
+	Project                      string                     `json:"project"`
+	DatabaseVersion              string                     `json:"databaseVersion"`
+	Region                       string                     `json:"region"`
+	State                        string                     `json:"state"`
+	ConnectionName               string                     `json:"connectionName"`
+	IPAddresses                  []IPMapping                `json:"ipAddresses"`
+	Settings                     Settings                   `json:"settings"`
+	BackupConfiguration          BackupConfiguration        `json:"backupConfiguration,omitempty"`
+	CreateTime                   time.Time                  `json:"createTime"`
+	InstanceType                 string                     `json:"instanceType"`
+	MaintenanceVersion           string                     `json:"maintenanceVersion,omitempty"`
+	AvailableMaintenanceVersions []string                   `json:"availableMaintenanceVersions,omitempty"`
+	UpgradableDatabaseVersions   []AvailableDatabaseVersion `json:"upgradableDatabaseVersions,omitempty"`
+	GceZone                      string                     `json:"gceZone,omitempty"`
+	SelfLink                     string                     `json:"selfLink"`
+	ServerCaCert                 *SslCert                   `json:"serverCaCert,omitempty"`
+	MasterInstanceName           string                     `json:"masterInstanceName,omitempty"`
+	ReplicaNames                 []string                   `json:"replicaNames,omitempty"`
+}
+
+// AvailableDatabaseVersion représente une version majeure vers laquelle l'instance peut monter
+type AvailableDatabaseVersion struct {
+	MajorVersion string `json:"majorVersion"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+}
+
+// SslCert représente le certificat de l'autorité de certification du serveur
+type SslCert struct {
+	CommonName     string    `json:"commonName"`
+	ExpirationTime time.Time `json:"expirationTime"`
 }
 
 type IPMapping struct {
@@ -28,19 +45,20 @@ type IPMapping struct {
 }
 
 type Settings struct {
-	Tier                   string              `json:"tier"`
-	ActivationPolicy       string              `json:"activationPolicy"`
-	DataDiskSizeGb         int64               `json:"dataDiskSizeGb,omitempty"`
-	DataDiskType           string              `json:"dataDiskType,omitempty"`
-	DatabaseFlags          []DatabaseFlag      `json:"databaseFlags,omitempty"`
-	BackupConfiguration    BackupConfiguration `json:"backupConfiguration,omitempty"`
-	MaintenanceWindow      MaintenanceWindow   `json:"maintenanceWindow,omitempty"`
-	IPConfiguration        IPConfiguration     `json:"ipConfiguration,omitempty"`
-	PricingPlan            string              `json:"pricingPlan"`
-	StorageAutoResize      bool                `json:"storageAutoResize,omitempty"`
-	StorageAutoResizeLimit int64               `json:"storageAutoResizeLimit,omitempty"`
-	AvailabilityType       string              `json:"availabilityType,omitempty"`
-	LocationPreference     LocationPreference  `json:"locationPreference,omitempty"`
+	Tier                      string              `json:"tier"`
+	ActivationPolicy          string              `json:"activationPolicy"`
+	DataDiskSizeGb            int64               `json:"dataDiskSizeGb,omitempty"`
+	DataDiskType              string              `json:"dataDiskType,omitempty"`
+	DatabaseFlags             []DatabaseFlag      `json:"databaseFlags,omitempty"`
+	BackupConfiguration       BackupConfiguration `json:"backupConfiguration,omitempty"`
+	MaintenanceWindow         MaintenanceWindow   `json:"maintenanceWindow,omitempty"`
+	IPConfiguration           IPConfiguration     `json:"ipConfiguration,omitempty"`
+	PricingPlan               string              `json:"pricingPlan"`
+	StorageAutoResize         bool                `json:"storageAutoResize,omitempty"`
+	StorageAutoResizeLimit    int64               `json:"storageAutoResizeLimit,omitempty"`
+	AvailabilityType          string              `json:"availabilityType,omitempty"`
+	DeletionProtectionEnabled bool                `json:"deletionProtectionEnabled,omitempty"`
+	LocationPreference        LocationPreference  `json:"locationPreference,omitempty"`
 }
 
 type DatabaseFlag struct {
@@ -54,6 +72,7 @@ type BackupConfiguration struct {
 	PointInTimeRecoveryEnabled  bool                    `json:"pointInTimeRecoveryEnabled,omitempty"`
 	TransactionLogRetentionDays int                     `json:"transactionLogRetentionDays,omitempty"`
 	BackupRetentionSettings     BackupRetentionSettings `json:"backupRetentionSettings,omitempty"`
+	Location                    string                  `json:"location,omitempty"`
 }
 
 type BackupRetentionSettings struct {
@@ -71,6 +90,7 @@ type IPConfiguration struct {
 	AuthorizedNetworks []AclEntry `json:"authorizedNetworks,omitempty"`
 	Ipv4Enabled        bool       `json:"ipv4Enabled"`
 	RequireSsl         bool       `json:"requireSsl,omitempty"`
+	SslMode            string     `json:"sslMode,omitempty"`
 	PrivateNetwork     string     `json:"privateNetwork,omitempty"`
 }
 
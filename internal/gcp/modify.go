@@ -0,0 +1,90 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+	"google.golang.org/api/sqladmin/v1"
+)
+
+// FlagChange représente une modification de flag planifiée
+type FlagChange struct {
+	Name            string `json:"name"`
+	CurrentValue    string `json:"currentValue"`
+	Value           string `json:"value"`
+	RequiresRestart bool   `json:"requiresRestart"`
+}
+
+// PlanFlagChanges valide les affectations et retourne les modifications à appliquer, sans rien modifier
+func (g *GCP) PlanFlagChanges(assignments []pgsettings.Assignment) ([]FlagChange, error) {
+	var changes []FlagChange
+
+	for _, assignment := range assignments {
+		metadata, err := g.databaseFlags.GetFlagMetadataByName(assignment.Name)
+		if err != nil {
+			return nil, fmt.Errorf("GCP: GetFlagMetadataByName: %w", err)
+		}
+
+		value, err := metadata.Validate(assignment.Value)
+		if err != nil {
+			return nil, fmt.Errorf("GCP: Validate: %w", err)
+		}
+
+		current, err := g.GetFlagValueByName(assignment.Name)
+		if err != nil {
+			return nil, fmt.Errorf("GCP: GetFlagValueByName: %w", err)
+		}
+
+		if current != "" && pgsettings.Equivalent(current, value, "") {
+			continue
+		}
+
+		changes = append(changes, FlagChange{
+			Name:            assignment.Name,
+			CurrentValue:    current,
+			Value:           value,
+			RequiresRestart: metadata.RequiresRestart,
+		})
+	}
+
+	return changes, nil
+}
+
+// ApplyFlagChanges applique les modifications. L'API remplace la liste complète des flags :
+// les flags existants sont donc conservés et fusionnés avec les nouvelles valeurs
+func (g *GCP) ApplyFlagChanges(changes []FlagChange) error {
+	if g.instance == nil {
+		return fmt.Errorf("no instance initialized")
+	}
+
+	values := make(map[string]string)
+	for _, change := range changes {
+		values[change.Name] = change.Value
+	}
+
+	var flags []*sqladmin.DatabaseFlags
+	for _, flag := range g.instance.Settings.DatabaseFlags {
+		value := flag.Value
+		if newValue, ok := values[flag.Name]; ok {
+			value = newValue
+			delete(values, flag.Name)
+		}
+		flags = append(flags, &sqladmin.DatabaseFlags{Name: flag.Name, Value: value})
+	}
+	for _, change := range changes {
+		if _, ok := values[change.Name]; ok {
+			flags = append(flags, &sqladmin.DatabaseFlags{Name: change.Name, Value: change.Value})
+		}
+	}
+
+	ctx := context.Background()
+	_, err := g.service.Instances.Patch(g.projectID, g.instanceName, &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{DatabaseFlags: flags},
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("GCP: Instances.Patch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sqladmin/v1"
+)
+
+// operationPollInterval est l'intervalle d'interrogation d'une opération Cloud SQL
+const operationPollInterval = 5 * time.Second
+
+// CloneInstance clone l'instance vers une nouvelle instance, à l'instant demandé (PITR) ou à l'état courant
+func (g *GCP) CloneInstance(target string, pointInTime *time.Time) error {
+	if g.instance == nil {
+		return fmt.Errorf("no instance initialized")
+	}
+
+	cloneContext := &sqladmin.CloneContext{DestinationInstanceName: target}
+	if pointInTime != nil {
+		cloneContext.PointInTime = pointInTime.UTC().Format(time.RFC3339)
+	}
+
+	_, err := g.service.Instances.Clone(g.projectID, g.instanceName, &sqladmin.InstancesCloneRequest{
+		CloneContext: cloneContext,
+	}).Context(context.Background()).Do()
+	if err != nil {
+		return fmt.Errorf("GCP: Instances.Clone: %w", err)
+	}
+	return nil
+}
+
+// InstanceState retourne l'état d'une instance du projet, si elle est disponible (RUNNABLE),
+// et une erreur si elle est en échec. Une instance pas encore créée est en PENDING_CREATE
+func (g *GCP) InstanceState(name string) (string, bool, error) {
+	instance, err := g.service.Instances.Get(g.projectID, name).Context(context.Background()).Do()
+	var apiError *googleapi.Error
+	if errors.As(err, &apiError) && apiError.Code == http.StatusNotFound {
+		return "PENDING_CREATE", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("GCP: Instances.Get: %w", err)
+	}
+
+	if instance.State == "FAILED" || instance.State == "SUSPENDED" {
+		return instance.State, false, fmt.Errorf("instance %s en état %s", name, instance.State)
+	}
+	return instance.State, instance.State == "RUNNABLE", nil
+}
+
+// InstanceLabels retourne les étiquettes (userLabels) d'une instance du projet
+func (g *GCP) InstanceLabels(name string) (map[string]string, error) {
+	instance, err := g.service.Instances.Get(g.projectID, name).Context(context.Background()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("GCP: Instances.Get: %w", err)
+	}
+	if instance.Settings == nil {
+		return map[string]string{}, nil
+	}
+	return instance.Settings.UserLabels, nil
+}
+
+// LabelClone pose les étiquettes sur le clone et désactive sa protection contre la suppression,
+// héritée de la source. Le clone doit être disponible
+func (g *GCP) LabelClone(name string, labels map[string]string) error {
+	operation, err := g.service.Instances.Patch(g.projectID, name, &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{
+			UserLabels:                labels,
+			DeletionProtectionEnabled: false,
+			ForceSendFields:           []string{"DeletionProtectionEnabled"},
+		},
+	}).Context(context.Background()).Do()
+	if err != nil {
+		return fmt.Errorf("GCP: Instances.Patch: %w", err)
+	}
+	return g.waitOperation(operation)
+}
+
+// DeleteInstance supprime une instance du projet et attend la fin de l'opération
+func (g *GCP) DeleteInstance(name string) error {
+	operation, err := g.service.Instances.Delete(g.projectID, name).Context(context.Background()).Do()
+	if err != nil {
+		return fmt.Errorf("GCP: Instances.Delete: %w", err)
+	}
+	return g.waitOperation(operation)
+}
+
+// waitOperation attend la fin d'une opération Cloud SQL
+func (g *GCP) waitOperation(operation *sqladmin.Operation) error {
+	for operation.Status != "DONE" {
+		time.Sleep(operationPollInterval)
+
+		var err error
+		operation, err = g.service.Operations.Get(g.projectID, operation.Name).Context(context.Background()).Do()
+		if err != nil {
+			return fmt.Errorf("GCP: Operations.Get: %w", err)
+		}
+	}
+
+	if operation.Error != nil && len(operation.Error.Errors) > 0 {
+		return fmt.Errorf("GCP: operation %s: %s", operation.OperationType, operation.Error.Errors[0].Message)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// SecurityFindings évalue la posture de sécurité de l'instance Cloud SQL : réseaux autorisés,
+// chiffrement TLS, authentification IAM, expiration de l'autorité de certification
+// (expiryDays jours avant la date de fin de validité) et protection contre la suppression.
+// Le stockage Cloud SQL est toujours chiffré
+func (g *GCP) SecurityFindings(expiryDays int) ([]security.Finding, error) {
+	if g.instance == nil {
+		return nil, fmt.Errorf("aucune instance Cloud SQL")
+	}
+
+	finding := func(check string, severity string, detail string, remediation string) security.Finding {
+		return security.Finding{
+			Provider:    "gcp",
+			Resource:    g.instance.Name,
+			Check:       check,
+			Severity:    severity,
+			Detail:      detail,
+			Remediation: remediation,
+		}
+	}
+	var findings []security.Finding
+
+	ipConfiguration := g.instance.Settings.IPConfiguration
+	if ipConfiguration.Ipv4Enabled && len(ipConfiguration.AuthorizedNetworks) > 0 {
+		var open []string
+		for _, network := range ipConfiguration.AuthorizedNetworks {
+			if security.IsOpenToInternet(network.Value) {
+				open = append(open, fmt.Sprintf("%s (%s)", network.Value, network.Name))
+			}
+		}
+		if len(open) > 0 {
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityHigh,
+				fmt.Sprintf("IP publique et réseaux autorisés ouverts à tout Internet sur le port %d : %s", security.PostgreSQLPort, strings.Join(open, ", ")),
+				"Supprimer les réseaux autorisés 0.0.0.0/0 et utiliser le Cloud SQL Auth Proxy ou une IP privée"))
+		} else {
+			findings = append(findings, finding(security.CheckPublicExposure, security.SeverityMedium,
+				fmt.Sprintf("IP publique accessible depuis %d réseaux autorisés", len(ipConfiguration.AuthorizedNetworks)),
+				"Privilégier une IP privée ou le Cloud SQL Auth Proxy"))
+		}
+	}
+
+	switch {
+	case ipConfiguration.SslMode == "ENCRYPTED_ONLY", ipConfiguration.SslMode == "TRUSTED_CLIENT_CERTIFICATE_REQUIRED":
+	case ipConfiguration.SslMode == "" && ipConfiguration.RequireSsl:
+	default:
+		findings = append(findings, finding(security.CheckTLSEnforcement, security.SeverityHigh,
+			"les connexions non chiffrées sont acceptées (sslMode ALLOW_UNENCRYPTED_AND_ENCRYPTED)",
+			"Positionner sslMode à ENCRYPTED_ONLY (gcloud sql instances patch --ssl-mode=ENCRYPTED_ONLY)"))
+	}
+
+	iamAuthentication, _ := g.GetFlagValueByName("cloudsql.iam_authentication")
+	if value, _ := pgsettings.NormalizeBool(iamAuthentication); value != "on" {
+		findings = append(findings, finding(security.CheckPasswordOnlyAuth, security.SeverityMedium,
+			"authentification par mot de passe uniquement (cloudsql.iam_authentication désactivé)",
+			"Activer le flag cloudsql.iam_authentication et créer des utilisateurs IAM"))
+	}
+
+	if certificate := g.instance.ServerCaCert; certificate != nil && !certificate.ExpirationTime.IsZero() {
+		switch {
+		case certificate.ExpirationTime.Before(time.Now()):
+			findings = append(findings, finding(security.CheckCACertificateExpiry, security.SeverityHigh,
+				fmt.Sprintf("autorité de certification %s expirée depuis le %s", certificate.CommonName, certificate.ExpirationTime.Format("2006-01-02")),
+				"Effectuer la rotation de l'autorité de certification (gcloud sql ssl server-ca-certs rotate)"))
+		case certificate.ExpirationTime.Before(time.Now().AddDate(0, 0, expiryDays)):
+			findings = append(findings, finding(security.CheckCACertificateExpiry, security.SeverityMedium,
+				fmt.Sprintf("autorité de certification %s expire le %s", certificate.CommonName, certificate.ExpirationTime.Format("2006-01-02")),
+				"Préparer la rotation (gcloud sql ssl server-ca-certs create) et distribuer le nouveau certificat aux clients"))
+		}
+	}
+
+	if !g.instance.Settings.DeletionProtectionEnabled {
+		findings = append(findings, finding(security.CheckDeletionProtection, security.SeverityLow,
+			"protection contre la suppression désactivée",
+			"Activer la protection (gcloud sql instances patch --deletion-protection)"))
+	}
+
+	return findings, nil
+}
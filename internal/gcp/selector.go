@@ -0,0 +1,36 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sqladmin/v1"
+
+	"github.com/robinportigliatti/cloud_helper/internal/selector"
+)
+
+// SelectInstances retourne les noms des instances PostgreSQL du projet dont les étiquettes
+// (userLabels) satisfont le sélecteur
+func (g *GCP) SelectInstances(sel selector.Selector) ([]string, error) {
+	var names []string
+	err := g.service.Instances.List(g.projectID).Pages(context.Background(), func(page *sqladmin.InstancesListResponse) error {
+		for _, instance := range page.Items {
+			if !strings.HasPrefix(instance.DatabaseVersion, "POSTGRES") {
+				continue
+			}
+			var labels map[string]string
+			if instance.Settings != nil {
+				labels = instance.Settings.UserLabels
+			}
+			if sel.Matches(labels) {
+				names = append(names, instance.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GCP: Instances.List: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,246 @@
+package gcp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+	"github.com/robinportigliatti/cloud_helper/internal/sizing"
+)
+
+// Format des types de machine personnalisés Cloud SQL : db-custom-<vCPU>-<mémoire en MB>
+var customTierPattern = regexp.MustCompile(`^db-custom-(\d+)-(\d+)$`)
+
+// Limites des types de machine personnalisés : vCPU égal à 1 ou pair, 0,9 à 6,5 Go par vCPU,
+// mémoire multiple de 256 MB
+const (
+	customMaxVCPU          = 96
+	customMinMemoryPerVCPU = 922
+	customMaxMemoryPerVCPU = 6656
+	customMemoryStep       = 256
+)
+
+// Types de machine prédéfinis (<préfixe>-<vCPU>) : la mémoire est proportionnelle aux vCPU.
+// Les types à cœur partagé (db-f1-micro, db-g1-small) et à mémoire optimisée ne sont pas gérés
+var predefinedFamilies = []struct {
+	Prefix        string
+	MemoryPerVCPU int // MB
+	VCPUs         []int
+}{
+	{"db-n1-standard", 3840, []int{1, 2, 4, 8, 16, 32, 64, 96}},
+	{"db-n1-highmem", 6656, []int{2, 4, 8, 16, 32, 64, 96}},
+	{"db-perf-optimized-N", 8192, []int{2, 4, 8, 16, 32, 48, 64, 80, 96}},
+}
+
+// Valeur par défaut de max_connections selon la mémoire de l'instance (documentation Cloud SQL)
+var defaultMaxConnections = []struct {
+	MemoryMb int
+	Value    int
+}{
+	{122880, 1000},
+	{61440, 800},
+	{30720, 600},
+	{15360, 500},
+	{7680, 400},
+	{6144, 200},
+	{3840, 100},
+	{1740, 50},
+	{0, 25},
+}
+
+// parseCustomTier retourne la classe correspondant à un type de machine personnalisé
+func parseCustomTier(tier string) (sizing.Class, bool) {
+	matches := customTierPattern.FindStringSubmatch(tier)
+	if matches == nil {
+		return sizing.Class{}, false
+	}
+	vcpu, _ := strconv.Atoi(matches[1])
+	memoryMb, _ := strconv.Atoi(matches[2])
+	return sizing.Class{Name: tier, VCPU: vcpu, MemoryBytes: int64(memoryMb) * 1024 * 1024}, true
+}
+
+// customTier construit un type de machine personnalisé en conservant le ratio mémoire / vCPU
+func customTier(vcpu int, memoryPerVCPU int) sizing.Class {
+	memoryPerVCPU = min(max(memoryPerVCPU, customMinMemoryPerVCPU), customMaxMemoryPerVCPU)
+	memoryMb := (vcpu*memoryPerVCPU + customMemoryStep - 1) / customMemoryStep * customMemoryStep
+	return sizing.Class{
+		Name:        fmt.Sprintf("db-custom-%d-%d", vcpu, memoryMb),
+		VCPU:        vcpu,
+		MemoryBytes: int64(memoryMb) * 1024 * 1024,
+	}
+}
+
+// customFamily retourne les types personnalisés voisins (vCPU divisés et multipliés par deux)
+func customFamily(current sizing.Class) []sizing.Class {
+	memoryPerVCPU := int(current.MemoryBytes/1024/1024) / max(current.VCPU, 1)
+	classes := []sizing.Class{current}
+	if current.VCPU > 1 {
+		smaller := current.VCPU / 2
+		if smaller > 1 && smaller%2 == 1 {
+			smaller--
+		}
+		classes = append(classes, customTier(smaller, memoryPerVCPU))
+	}
+	if current.VCPU*2 <= customMaxVCPU {
+		classes = append(classes, customTier(current.VCPU*2, memoryPerVCPU))
+	}
+	sizing.SortClasses(classes)
+	return classes
+}
+
+// predefinedFamily retourne la classe d'un type de machine prédéfini et les types de la même série
+func predefinedFamily(tier string) (sizing.Class, []sizing.Class, bool) {
+	for _, family := range predefinedFamilies {
+		vcpu, err := strconv.Atoi(strings.TrimPrefix(tier, family.Prefix+"-"))
+		if !strings.HasPrefix(tier, family.Prefix+"-") || err != nil || !slices.Contains(family.VCPUs, vcpu) {
+			continue
+		}
+
+		classes := make([]sizing.Class, 0, len(family.VCPUs))
+		for _, candidate := range family.VCPUs {
+			classes = append(classes, sizing.Class{
+				Name:        fmt.Sprintf("%s-%d", family.Prefix, candidate),
+				VCPU:        candidate,
+				MemoryBytes: int64(candidate*family.MemoryPerVCPU) * 1024 * 1024,
+			})
+		}
+		return classes[slices.Index(family.VCPUs, vcpu)], classes, true
+	}
+	return sizing.Class{}, nil, false
+}
+
+// maxConnectionsFor retourne la valeur par défaut de max_connections pour une mémoire donnée
+func maxConnectionsFor(memoryBytes int64) int {
+	memoryMb := int(memoryBytes / 1024 / 1024)
+	for _, step := range defaultMaxConnections {
+		if memoryMb >= step.MemoryMb {
+			return step.Value
+		}
+	}
+	return defaultMaxConnections[len(defaultMaxConnections)-1].Value
+}
+
+// metricStats résume une métrique Cloud Monitoring : p95 des moyennes horaires et maximum des maxima.
+// Pour les compteurs (aligner ALIGN_RATE), moyenne et maximum sont le débit horaire
+func (g *GCP) metricStats(metricType string, meanAligner string, maxAligner string, scale float64, startTime time.Time, endTime time.Time) (sizing.Stats, error) {
+	samples := func(aligner string) ([]forecast.Sample, error) {
+		series, err := g.ListTimeSeries(metricType, aligner, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		var result []forecast.Sample
+		for _, timeSeries := range series.TimeSeries {
+			for _, point := range timeSeries.Points {
+				result = append(result, forecast.Sample{Timestamp: point.Interval.EndTime, Value: point.GetValue() * scale})
+			}
+		}
+		return result, nil
+	}
+
+	average, err := samples(meanAligner)
+	if err != nil {
+		return sizing.Stats{}, fmt.Errorf("%s: %w", metricType, err)
+	}
+	maximum, err := samples(maxAligner)
+	if err != nil {
+		return sizing.Stats{}, fmt.Errorf("%s: %w", metricType, err)
+	}
+	return sizing.Summarize(average, maximum), nil
+}
+
+// Usage retourne l'utilisation de l'instance sur les derniers jours
+func (g *GCP) Usage(windowDays int) (sizing.Usage, error) {
+	var usage sizing.Usage
+	var err error
+
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -windowDays)
+
+	usage.CPUPercent, err = g.metricStats("cloudsql.googleapis.com/database/cpu/utilization", "ALIGN_MEAN", "ALIGN_MAX", 100, startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.MemoryBytes, err = g.metricStats("cloudsql.googleapis.com/database/memory/usage", "ALIGN_MEAN", "ALIGN_MAX", 1, startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.Connections, err = g.metricStats("cloudsql.googleapis.com/database/postgresql/num_backends", "ALIGN_MEAN", "ALIGN_MAX", 1, startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.ReadIops, err = g.metricStats("cloudsql.googleapis.com/database/disk/read_ops_count", "ALIGN_RATE", "ALIGN_RATE", 1, startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	usage.WriteIops, err = g.metricStats("cloudsql.googleapis.com/database/disk/write_ops_count", "ALIGN_RATE", "ALIGN_RATE", 1, startTime, endTime)
+	if err != nil {
+		return usage, err
+	}
+	return usage, nil
+}
+
+// RecommendSizing analyse l'utilisation de l'instance sur la fenêtre et propose un type de machine
+// plus grand ou plus petit (personnalisé ou de la même série prédéfinie), avec le changement
+// de max_connections qui en découle
+func (g *GCP) RecommendSizing(windowDays int) (sizing.Recommendation, error) {
+	if g.instance == nil {
+		return sizing.Recommendation{}, fmt.Errorf("no instance initialized")
+	}
+
+	recommendation := sizing.Recommendation{
+		Provider:   "gcp",
+		Resource:   g.instanceName,
+		WindowDays: windowDays,
+	}
+
+	var candidates []sizing.Class
+	current, ok := parseCustomTier(g.GetTier())
+	if ok {
+		candidates = customFamily(current)
+	} else {
+		current, candidates, ok = predefinedFamily(g.GetTier())
+	}
+	if !ok {
+		return recommendation, fmt.Errorf("type de machine %s non géré, seuls les types db-custom-<vCPU>-<mémoire>, db-n1-standard-*, db-n1-highmem-* et db-perf-optimized-N-* sont analysés", g.GetTier())
+	}
+	recommendation.Current = current
+
+	var err error
+	recommendation.Usage, err = g.Usage(windowDays)
+	if err != nil {
+		return recommendation, fmt.Errorf("Usage: %w", err)
+	}
+
+	recommendation.Action, recommendation.Suggested, recommendation.Reasons = sizing.Recommend(recommendation.Usage, current, candidates)
+	if recommendation.Suggested == nil {
+		return recommendation, nil
+	}
+
+	// max_connections dépend de la mémoire tant qu'il n'est pas fixé par un flag
+	if value, err := g.GetFlagValueByName("max_connections"); err == nil && value != "" {
+		return recommendation, nil
+	}
+	currentMaxConnections := maxConnectionsFor(current.MemoryBytes)
+	suggestedMaxConnections := maxConnectionsFor(recommendation.Suggested.MemoryBytes)
+	if currentMaxConnections == suggestedMaxConnections {
+		return recommendation, nil
+	}
+	recommendation.Parameters = append(recommendation.Parameters, sizing.ParameterChange{
+		Name:      "max_connections",
+		Formula:   "défaut Cloud SQL selon la mémoire",
+		Current:   strconv.Itoa(currentMaxConnections),
+		Suggested: strconv.Itoa(suggestedMaxConnections),
+	})
+	if recommendation.Action == sizing.ActionDownsize && float64(suggestedMaxConnections) < recommendation.Usage.Connections.Max {
+		recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("max_connections passerait à %d pour %.0f connexions observées",
+			suggestedMaxConnections, recommendation.Usage.Connections.Max))
+		recommendation.Action = sizing.ActionKeep
+		recommendation.Suggested = nil
+		recommendation.Parameters = nil
+	}
+	return recommendation, nil
+}
@@ -0,0 +1,92 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/sqladmin/v1"
+
+	"github.com/robinportigliatti/cloud_helper/internal/topology"
+)
+
+// Métriques Cloud Monitoring du retard de réplication : en secondes sur le réplica,
+// en octets sur le primaire (une série par réplica)
+const (
+	replicaLagMetric     = "cloudsql.googleapis.com/database/replication/replica_lag"
+	replicaByteLagMetric = "cloudsql.googleapis.com/database/postgresql/replication/replica_byte_lag"
+)
+
+// lagWindow est la période sur laquelle le retard maximal des réplicas est mesuré
+const lagWindow = time.Hour
+
+// instanceMetricMaximum retourne le maximum d'une métrique d'une instance sur la dernière heure,
+// toutes séries confondues, nil si la métrique n'est pas publiée
+func (g *GCP) instanceMetricMaximum(instanceName string, metricType string) (*float64, error) {
+	endTime := time.Now()
+	result, err := g.instanceTimeSeries(instanceName, metricType, "ALIGN_MAX", endTime.Add(-lagWindow), endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var maximum *float64
+	for _, series := range result.TimeSeries {
+		for _, point := range series.Points {
+			value := point.GetValue()
+			if maximum == nil || value > *maximum {
+				maximum = &value
+			}
+		}
+	}
+	return maximum, nil
+}
+
+// Topology retourne la topologie de réplication des instances PostgreSQL du projet : primaires et
+// réplicas (masterInstanceName, replicaNames), avec le retard des réplicas (replica_lag) et, sur
+// le primaire, le plus grand retard en octets de ses réplicas (replica_byte_lag)
+func (g *GCP) Topology() (topology.Topology, error) {
+	var instances []*sqladmin.DatabaseInstance
+	err := g.service.Instances.List(g.projectID).Pages(context.Background(), func(page *sqladmin.InstancesListResponse) error {
+		for _, instance := range page.Items {
+			if strings.HasPrefix(instance.DatabaseVersion, "POSTGRES") {
+				instances = append(instances, instance)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return topology.Topology{}, fmt.Errorf("GCP: Instances.List: %w", err)
+	}
+
+	var nodes []topology.Node
+	for _, instance := range instances {
+		node := topology.Node{
+			ID:     instance.Name,
+			Name:   instance.Name,
+			Role:   topology.RolePrimary,
+			Region: instance.Region,
+			Status: instance.State,
+		}
+		lagMetric := replicaByteLagMetric
+		if instance.MasterInstanceName != "" {
+			// masterInstanceName est de la forme <projet>:<instance>
+			node.Role = topology.RoleReplica
+			node.Parent = strings.TrimPrefix(instance.MasterInstanceName, g.projectID+":")
+			lagMetric = replicaLagMetric
+		}
+
+		lag, err := g.instanceMetricMaximum(instance.Name, lagMetric)
+		if err != nil {
+			return topology.Topology{}, fmt.Errorf("GCP: instanceMetricMaximum %s: %w", instance.Name, err)
+		}
+		if node.Role == topology.RoleReplica {
+			node.ReplicaLagSeconds = lag
+		} else {
+			node.SlotLagBytes = lag
+		}
+		nodes = append(nodes, node)
+	}
+
+	return topology.New("gcp", nodes), nil
+}
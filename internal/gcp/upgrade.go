@@ -0,0 +1,42 @@
+package gcp
+
+import (
+	"fmt"
+
+	"github.com/robinportigliatti/cloud_helper/internal/upgrade"
+)
+
+// UpgradePlan construit le plan de montée de version de l'instance : versions de maintenance
+// disponibles (availableMaintenanceVersions) et versions majeures proposées (upgradableDatabaseVersions).
+// Cloud SQL montant directement vers n'importe quelle version majeure, le chemin vise la plus récente
+func (g *GCP) UpgradePlan(calendar upgrade.Calendar) (upgrade.Plan, error) {
+	if g.instance == nil {
+		return upgrade.Plan{}, fmt.Errorf("no instance initialized")
+	}
+
+	var targets []upgrade.Target
+	for _, version := range g.instance.AvailableMaintenanceVersions {
+		targets = append(targets, upgrade.Target{Version: version, Description: "maintenance"})
+	}
+	for _, version := range g.instance.UpgradableDatabaseVersions {
+		if upgrade.Compare(version.Name, g.instance.DatabaseVersion) <= 0 {
+			continue
+		}
+		targets = append(targets, upgrade.Target{Version: version.Name, Major: true, Description: version.DisplayName})
+	}
+	upgrade.SortTargets(targets)
+
+	var path []string
+	for i := len(targets) - 1; i >= 0; i-- {
+		if targets[i].Major {
+			path = append(path, targets[i].Version)
+			break
+		}
+	}
+
+	plan := upgrade.NewPlan(calendar, "gcp", g.instanceName, g.instance.DatabaseVersion, targets, path)
+	if g.instance.MaintenanceVersion != "" {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("version de maintenance actuelle : %s", g.instance.MaintenanceVersion))
+	}
+	return plan, nil
+}
@@ -0,0 +1,396 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Point représente une valeur de métrique horodatée
+type Point struct {
+	Timestamp time.Time
+	Value     string
+	Unit      string
+}
+
+// Marker est un repère horodaté (redémarrage, bascule, maintenance...) affiché sur les graphes
+// et dans la page HTML des métriques
+type Marker struct {
+	Time  time.Time
+	Label string
+}
+
+// WriteCSV écrit les points d'une statistique dans un fichier CSV au format attendu par ReadCSV
+func WriteCSV(filePath string, statistic string, points []Point) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	title := fmt.Sprintf("\"%s\";\"%s\";\"%s\";\r\n", "Timestamp", statistic, "Unit")
+	_, err = file.WriteString(title)
+	if err != nil {
+		return fmt.Errorf("file.WriteString: %w", err)
+	}
+
+	for _, point := range points {
+		data := fmt.Sprintf("\"%s\";\"%s\";\"%s\";\r\n", point.Timestamp, point.Value, point.Unit)
+		_, err := file.WriteString(data)
+		if err != nil {
+			return fmt.Errorf("file.WriteString: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreatePNGFromCSV génère le graphe PNG d'un fichier CSV et retourne le nom du fichier PNG.
+// Les repères sont tracés en lignes verticales
+func CreatePNGFromCSV(filePath string, markers ...Marker) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("os.Open: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, yAxisLabel, err := ReadCSV(file)
+	if err != nil {
+		return "", fmt.Errorf("ReadCSV: %w", err)
+	}
+
+	baseFilename := filepath.Base(filePath)
+	outputFilename := strings.Replace(baseFilename, ".csv", ".png", 1)
+	outputPath := filepath.Join(filepath.Dir(filePath), outputFilename)
+
+	err = CreatePNGGraph(data, yAxisLabel, outputPath, markers...)
+	if err != nil {
+		return "", fmt.Errorf("CreatePNGGraph: %w", err)
+	}
+
+	return outputFilename, nil
+}
+
+// ReadCSV lit un fichier CSV de métriques et calcule les moyennes par intervalle de 5 minutes
+func ReadCSV(file io.Reader) (plotter.XYs, string, error) {
+	reader := csv.NewReader(file)
+	reader.Comma = ';'
+	reader.TrimLeadingSpace = true
+
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, "", err
+	}
+
+	yAxisLabel := lines[0][1]
+
+	// Trier les lignes en fonction de la colonne Timestamp
+	sort.Slice(lines[1:], func(i, j int) bool {
+		return lines[1+i][0] < lines[1+j][0]
+	})
+
+	layout := "2006-01-02 15:04:05 -0700 MST"
+
+	interval := 5 * time.Minute
+
+	avgData := make(map[int64]float64)
+	avgCount := make(map[int64]int)
+
+	for _, line := range lines[1:] {
+		t, err := time.Parse(layout, line[0])
+		if err != nil {
+			return nil, "", err
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(line[1]), 64)
+		if err != nil {
+			return nil, "", err
+		}
+
+		intervalStart := t.Truncate(interval).Unix()
+
+		avgData[intervalStart] += y
+		avgCount[intervalStart]++
+	}
+
+	data := make(plotter.XYs, len(avgData))
+	i := 0
+	for k, v := range avgData {
+		data[i].X = float64(k)
+		data[i].Y = v / float64(avgCount[k])
+		i++
+	}
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].X < data[j].X
+	})
+
+	return data, yAxisLabel, nil
+}
+
+func writeHTML(file *os.File, content string, step string) error {
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("file.WriteString: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMetricsHTML génère la page HTML regroupant les graphes PNG d'une instance,
+// précédés de la chronologie des repères
+func CreateMetricsHTML(outputFilenames []string, instanceIdentifier string, markers ...Marker) error {
+	htmlFile, err := os.Create(fmt.Sprintf("%s.html", instanceIdentifier))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = htmlFile.Close() }()
+
+	// Écrire l'en-tête HTML
+	err = writeHTML(htmlFile, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n", "début HTML")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<meta charset=\"UTF-8\">\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1, shrink-to-fit=no\">\n", "meta tags")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<link rel=\"stylesheet\" href=\"https://stackpath.bootstrapcdn.com/bootstrap/4.3.1/css/bootstrap.min.css\" integrity=\"sha384-ggOyR0iXCbMQv3Xipma34MD+dH/1fQ784/j6cY/iJTQUOhcWr7x9JvoRxT2MZw1T\" crossorigin=\"anonymous\">\n", "Bootstrap CSS")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<title>Metrics Viewer</title>\n</head>\n<body>\n", "titre HTML")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<div class=\"container mt-4\">\n<h2>Metrics</h2>\n", "conteneur principal")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	// Chronologie des événements
+	if len(markers) > 0 {
+		err = writeHTML(htmlFile, markersHTML(markers), "chronologie des événements")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+	}
+
+	// Parcourir les catégories de métriques
+	categories := make(map[string]bool)
+	for _, filename := range outputFilenames {
+		category := getCategory(filename)
+		categories[category] = true
+	}
+
+	// Générer le contenu pour chaque catégorie
+	for category := range categories {
+		err = writeHTML(htmlFile, fmt.Sprintf("<div class=\"mt-3\" id=\"%s\">\n", category), "début catégorie")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+
+		err = writeHTML(htmlFile, "<div class=\"card card-body\">\n", "conteneur carte")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+
+		err = writeHTML(htmlFile, fmt.Sprintf("<h5 class=\"card-title\">%s</h5>\n", getCategoryTitle(category)), "titre catégorie")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+
+		// Afficher toutes les images pour la catégorie
+		for _, filename := range outputFilenames {
+			if strings.Contains(filename, "Average") {
+				err = writeHTML(htmlFile, fmt.Sprintf("<img src=\"%s\" class=\"img-fluid\" alt=\"%s\">\n", filename, filepath.Base(filename)), "image catégorie")
+				if err != nil {
+					return fmt.Errorf("writeHTML: %w", err)
+				}
+			}
+		}
+
+		err = writeHTML(htmlFile, "</div>\n</div>\n", "fermeture div catégorie")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+	}
+
+	// Bouton Collapse pour les autres metrics
+	err = writeHTML(htmlFile, "<button class=\"btn btn-secondary mt-3\" type=\"button\" data-toggle=\"collapse\" data-target=\"#otherMetrics\" aria-expanded=\"false\" aria-controls=\"otherMetrics\">\n", "bouton collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "Afficher les autres Metrics\n", "texte bouton collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "</button>\n", "fermeture bouton collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	// Contenu collapse pour les autres Metrics
+	err = writeHTML(htmlFile, "<div class=\"collapse mt-3\" id=\"otherMetrics\">\n", "début collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<div class=\"card card-body\">\n", "conteneur collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	// Afficher toutes les images ici
+	for _, filename := range outputFilenames {
+		err = writeHTML(htmlFile, fmt.Sprintf("<img src=\"%s\" class=\"img-fluid\" alt=\"%s\">\n", filename, filepath.Base(filename)), "image collapse")
+		if err != nil {
+			return fmt.Errorf("writeHTML: %w", err)
+		}
+	}
+
+	err = writeHTML(htmlFile, "</div>\n</div>\n", "fermeture collapse")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	// Fermer le corps et l'HTML
+	err = writeHTML(htmlFile, "<script src=\"https://code.jquery.com/jquery-3.3.1.slim.min.js\" integrity=\"sha384-q8i/X+965DzO0rT7abK41JStQIAqVgRVzpbzo5smXKp4YfRvH+8abtTE1Pi6jizo\" crossorigin=\"anonymous\"></script>\n", "script jQuery")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<script src=\"https://cdn.jsdelivr.net/npm/@popperjs/core@2.10.2/dist/umd/popper.min.js\" integrity=\"sha384-ggOyR0iXCbMQv3Xipma34MD+dH/1fQ784/j6cY/iJTQUOhcWr7x9JvoRxT2MZw1T\" crossorigin=\"anonymous\"></script>\n", "script Popper.js")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "<script src=\"https://stackpath.bootstrapcdn.com/bootstrap/4.3.1/js/bootstrap.min.js\" integrity=\"sha384-JjSmVgyd0p3pXB1rRibZUAYoIIy6OrQ6VrjIEaFf/nJGzIxFDsf4x0xIM+B07jRM\" crossorigin=\"anonymous\"></script>\n", "script Bootstrap")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	err = writeHTML(htmlFile, "</body>\n</html>\n", "fermeture HTML")
+	if err != nil {
+		return fmt.Errorf("writeHTML: %w", err)
+	}
+
+	return nil
+}
+
+// markersHTML génère la carte listant les repères par date croissante
+func markersHTML(markers []Marker) string {
+	sorted := make([]Marker, len(markers))
+	copy(sorted, markers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	var b strings.Builder
+	b.WriteString("<div class=\"mt-3\" id=\"events\">\n<div class=\"card card-body\">\n<h5 class=\"card-title\">Events</h5>\n")
+	b.WriteString("<table class=\"table table-sm\">\n<thead><tr><th>Time</th><th>Event</th></tr></thead>\n<tbody>\n")
+	for _, marker := range sorted {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td></tr>\n", marker.Time.UTC().Format(time.DateTime), html.EscapeString(marker.Label))
+	}
+	b.WriteString("</tbody>\n</table>\n</div>\n</div>\n")
+	return b.String()
+}
+
+// getCategory extrait la catégorie à partir du nom du fichier
+func getCategory(filename string) string {
+	parts := strings.Split(filename, "/")
+	if len(parts) >= 4 {
+		return parts[3]
+	}
+	return ""
+}
+
+// getCategoryTitle génère un titre lisible à partir de la catégorie
+func getCategoryTitle(category string) string {
+	// Vous pouvez personnaliser cette fonction pour obtenir un titre plus convivial
+	// En fonction de la catégorie fournie
+	return category + " Metrics"
+}
+
+// CreatePNGGraph génère un graphe PNG à partir des données d'une métrique.
+// Les repères compris dans la période du graphe sont tracés en lignes verticales pointillées
+func CreatePNGGraph(data plotter.XYs, yAxisLabel, outputFilename string, markers ...Marker) error {
+	p := plot.New()
+
+	baseFilename := filepath.Base(outputFilename)
+	p.Title.Text = "Graphe des données CSV : " + baseFilename
+	p.X.Label.Text = "Timestamp"
+	p.Y.Label.Text = yAxisLabel
+
+	plotter.DefaultGlyphStyle.Radius = vg.Points(2)
+
+	line, err := plotter.NewLine(data)
+	if err != nil {
+		return err
+	}
+	//  points.Shape = draw.CrossGlyph{}
+	line.Color = color.RGBA{R: 255, B: 255, A: 255}
+
+	p.Add(line)
+
+	err = addMarkers(p, data, markers)
+	if err != nil {
+		return err
+	}
+
+	p.X.Tick.Marker = plot.TimeTicks{Format: "2006-01-02\n15:04:05"}
+	p.X.Tick.Label.Rotation = -math.Pi / 2
+	p.X.Tick.Label.XAlign = draw.XRight
+	p.X.Tick.Label.YAlign = draw.YCenter
+
+	err = p.Save(10*vg.Inch, 5*vg.Inch, outputFilename)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// addMarkers trace une ligne verticale pour chaque repère compris dans la période des données
+func addMarkers(p *plot.Plot, data plotter.XYs, markers []Marker) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	xMin, xMax, yMin, yMax := plotter.XYRange(data)
+	if yMin == yMax {
+		yMax = yMin + 1
+	}
+	for _, marker := range markers {
+		x := float64(marker.Time.Unix())
+		if x < xMin || x > xMax {
+			continue
+		}
+		markerLine, err := plotter.NewLine(plotter.XYs{{X: x, Y: yMin}, {X: x, Y: yMax}})
+		if err != nil {
+			return err
+		}
+		markerLine.Color = color.RGBA{R: 255, A: 255}
+		markerLine.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+		p.Add(markerLine)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Setting représente un paramètre PostgreSQL à afficher
+type Setting struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+	Level string `json:"Level,omitempty"` // niveau d'origine de la valeur (ex: cluster ou instance pour Aurora)
+}
+
+// PrintSettings affiche une liste de paramètres au format demandé (default, csv, json)
+func PrintSettings(format string, settings []Setting) error {
+	switch format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		for _, setting := range settings {
+			record := []string{setting.Name, setting.Value}
+			if setting.Level != "" {
+				record = append(record, setting.Level)
+			}
+			if err := w.Write(record); err != nil {
+				return fmt.Errorf("CSV Write: %w", err)
+			}
+		}
+		w.Flush()
+	case "json":
+		data, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON Marshal: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		if len(settings) == 1 {
+			fmt.Println(settings[0].Value)
+		} else {
+			for _, setting := range settings {
+				if setting.Level != "" {
+					fmt.Printf("%s = %s (%s)\n", setting.Name, setting.Value, setting.Level)
+				} else {
+					fmt.Printf("%s = %s\n", setting.Name, setting.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// PrintRows affiche un tableau au format demandé (default, csv, json, markdown)
+func PrintRows(format string, columns []string, rows [][]string) error {
+	switch format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write(columns); err != nil {
+			return fmt.Errorf("CSV Write: %w", err)
+		}
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				return fmt.Errorf("CSV Write: %w", err)
+			}
+		}
+		w.Flush()
+	case "json":
+		records := make([]map[string]string, 0, len(rows))
+		for _, row := range rows {
+			record := make(map[string]string, len(columns))
+			for i, column := range columns {
+				record[column] = row[i]
+			}
+			records = append(records, record)
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON Marshal: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Printf("| %s |\n", strings.Join(columns, " | "))
+		fmt.Printf("|%s\n", strings.Repeat(" --- |", len(columns)))
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			fmt.Printf("| %s |\n", strings.Join(cells, " | "))
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		_, _ = fmt.Fprintln(w, strings.Join(columns, " |\t "))
+		for _, row := range rows {
+			_, _ = fmt.Fprintln(w, strings.Join(row, " |\t "))
+		}
+		_ = w.Flush()
+	}
+	return nil
+}
+
+// PrintJSON affiche une valeur au format JSON indenté
+func PrintJSON(value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON Marshal: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
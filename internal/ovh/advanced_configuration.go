@@ -0,0 +1,49 @@
+package ovh
+
+import (
+	"sort"
+	"strings"
+)
+
+// AdvancedConfiguration représente les paramètres PostgreSQL avancés d'un cluster (clé -> valeur)
+type AdvancedConfiguration map[string]string
+
+// AdvancedConfigurationCapability décrit une clé de configuration avancée autorisée par OVHcloud
+type AdvancedConfigurationCapability struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	Values      []string `json:"values,omitempty"`
+	Unit        string   `json:"unit,omitempty"`
+}
+
+// GetValueByName retourne la valeur d'un paramètre, avec ou sans le préfixe "pg."
+func (a AdvancedConfiguration) GetValueByName(name string) (string, bool) {
+	if value, ok := a[name]; ok {
+		return value, true
+	}
+	if value, ok := a[advancedConfigurationKey(name)]; ok {
+		return value, true
+	}
+	return "", false
+}
+
+// Names retourne les noms des paramètres triés par ordre alphabétique
+func (a AdvancedConfiguration) Names() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// advancedConfigurationKey ajoute le préfixe "pg." utilisé par OVHcloud pour les paramètres PostgreSQL
+func advancedConfigurationKey(name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return "pg." + name
+}
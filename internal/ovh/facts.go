@@ -0,0 +1,35 @@
+package ovh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/rules"
+)
+
+// Facts retourne le modèle commun aux fournisseurs utilisé par le moteur de règles.
+// L'API OVHcloud n'expose ni les vCPU ni la mémoire du flavor : les règles qui en dépendent sont ignorées
+func (o *OVHClient) Facts() (rules.Instance, error) {
+	facts := rules.Instance{
+		Provider:   "ovh",
+		Identifier: o.clusterID,
+		Parameters: make(map[string]rules.Parameter),
+	}
+
+	instance, err := o.GetDatabase()
+	if err != nil {
+		return rules.Instance{}, fmt.Errorf("GetDatabase: %w", err)
+	}
+	facts.EngineVersion = instance.Version
+	facts.MachineType = instance.Plan
+	facts.HighAvailability = instance.NodeNumber > 1
+
+	configuration, err := o.GetAdvancedConfiguration()
+	if err != nil {
+		return rules.Instance{}, fmt.Errorf("GetAdvancedConfiguration: %w", err)
+	}
+	for _, name := range configuration.Names() {
+		facts.Parameters[strings.TrimPrefix(name, "pg.")] = rules.Parameter{Value: configuration[name]}
+	}
+	return facts, nil
+}
@@ -0,0 +1,168 @@
+package ovh
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/metrics"
+)
+
+// DataPoint représente une valeur de métrique OVHcloud
+type DataPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// HostMetric regroupe les valeurs d'une métrique pour un nœud du cluster
+type HostMetric struct {
+	Hostname   string      `json:"hostname"`
+	DataPoints []DataPoint `json:"dataPoints"`
+}
+
+// Metric représente une métrique d'un cluster OVHcloud
+type Metric struct {
+	Name    string       `json:"name"`
+	Unit    string       `json:"unit"`
+	Metrics []HostMetric `json:"metrics"`
+}
+
+// ListMetrics retourne les noms des métriques disponibles pour le cluster
+func (o *OVHClient) ListMetrics() ([]string, error) {
+	output, err := o.Request("GET", "/metric")
+	if err != nil {
+		return nil, fmt.Errorf("OVH: ListMetrics: %w", err)
+	}
+
+	var names []string
+	err = json.Unmarshal([]byte(output), &names)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetMetric récupère les valeurs d'une métrique sur une période (lastHour, lastDay, lastWeek, lastMonth, lastYear)
+func (o *OVHClient) GetMetric(name string, period string) (*Metric, error) {
+	output, err := o.Request("GET", fmt.Sprintf("/metric/%s?extended=false&period=%s", name, period))
+	if err != nil {
+		return nil, fmt.Errorf("OVH: GetMetric: %w", err)
+	}
+
+	var metric Metric
+	err = json.Unmarshal([]byte(output), &metric)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+
+	return &metric, nil
+}
+
+// metricPeriod retourne la plus petite période OVHcloud couvrant l'intervalle démarrant à start
+// L'API ne propose que des périodes relatives à l'instant présent
+func metricPeriod(start time.Time) string {
+	since := time.Since(start)
+	switch {
+	case since <= time.Hour:
+		return "lastHour"
+	case since <= 24*time.Hour:
+		return "lastDay"
+	case since <= 7*24*time.Hour:
+		return "lastWeek"
+	case since <= 31*24*time.Hour:
+		return "lastMonth"
+	default:
+		return "lastYear"
+	}
+}
+
+// DownloadMetrics télécharge les métriques du cluster au format CSV, PNG et HTML
+func (o *OVHClient) DownloadMetrics(start string, end string, directory string) error {
+	// Les dates sont saisies sans fuseau, dans l'heure locale comme leurs valeurs par défaut
+	startTime, err := time.ParseInLocation("2006-01-02T15:04:05", start, time.Local)
+	if err != nil {
+		return fmt.Errorf("time.Parse start: %w", err)
+	}
+	endTime, err := time.ParseInLocation("2006-01-02T15:04:05", end, time.Local)
+	if err != nil {
+		return fmt.Errorf("time.Parse end: %w", err)
+	}
+
+	names, err := o.ListMetrics()
+	if err != nil {
+		return fmt.Errorf("OVH: ListMetrics: %w", err)
+	}
+
+	metricsPath := ""
+	if directory == "./" {
+		metricsPath = "./metrics/"
+	} else {
+		metricsPath = fmt.Sprintf("%s/metrics", directory)
+	}
+
+	dimensionValuePath := fmt.Sprintf("%s/%s/%s", metricsPath, "ClusterID", o.clusterID)
+	err = os.MkdirAll(dimensionValuePath, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	period := metricPeriod(startTime)
+	var pngFiles []string
+
+	for _, name := range names {
+		metric, err := o.GetMetric(name, period)
+		if err != nil {
+			return fmt.Errorf("OVH: GetMetric: %w", err)
+		}
+
+		metricPath := fmt.Sprintf("%s/%s", dimensionValuePath, name)
+		err = os.MkdirAll(metricPath, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("os.MkdirAll: %w", err)
+		}
+
+		for _, host := range metric.Metrics {
+			var points []metrics.Point
+			for _, dataPoint := range host.DataPoints {
+				timestamp := time.Unix(dataPoint.Timestamp, 0).UTC()
+				if timestamp.Before(startTime) || timestamp.After(endTime) {
+					continue
+				}
+				points = append(points, metrics.Point{
+					Timestamp: timestamp,
+					Value:     fmt.Sprintf("%f", dataPoint.Value),
+					Unit:      metric.Unit,
+				})
+			}
+
+			if len(points) == 0 {
+				continue
+			}
+
+			// OVHcloud ne fournit qu'une valeur moyenne par point, nommée comme la statistique Average de CloudWatch
+			// pour que la page HTML l'affiche dans la carte de sa catégorie
+			filePath := fmt.Sprintf("%s/%s.%s.%s.%s.%s.csv", metricPath, "ClusterID", o.clusterID, name, host.Hostname, "Average")
+			err = metrics.WriteCSV(filePath, name, points)
+			if err != nil {
+				return fmt.Errorf("WriteCSV: %w", err)
+			}
+
+			outputFilename, err := metrics.CreatePNGFromCSV(filePath)
+			if err != nil {
+				return fmt.Errorf("CreatePNGFromCSV: %w", err)
+			}
+
+			pngFiles = append(pngFiles, outputFilename)
+		}
+	}
+
+	// Création du fichier HTML
+	err = metrics.CreateMetricsHTML(pngFiles, o.clusterID)
+	if err != nil {
+		return fmt.Errorf("CreateMetricsHTML: %w", err)
+	}
+
+	return nil
+}
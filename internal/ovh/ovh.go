@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/check"
+	"github.com/robinportigliatti/cloud_helper/internal/pgpass"
 )
 
 // OVHClient structure principale pour gérer OVHcloud Database Services
@@ -12,6 +15,9 @@ type OVHClient struct {
 	serviceName string
 	clusterID   string
 	endpoint    string // eu, ca, us
+
+	// Cached data
+	advancedConfiguration AdvancedConfiguration
 }
 
 // DatabaseInstance représente une instance PostgreSQL sur OVHcloud
@@ -47,34 +53,41 @@ func (o *OVHClient) Init(serviceName string, clusterID string, endpoint string)
 	return nil
 }
 
-// Execute exécute une commande via le CLI ovhcloud
-func (o *OVHClient) Execute(resource string, action string, additionalArgs string) (string, error) {
-	var cmdStr string
-
-	if additionalArgs != "" {
-		cmdStr = fmt.Sprintf("ovhcloud-cli %s %s %s --endpoint %s --output json",
-			resource, action, additionalArgs, o.endpoint)
-	} else {
-		cmdStr = fmt.Sprintf("ovhcloud-cli %s %s --endpoint %s --output json",
-			resource, action, o.endpoint)
-	}
-
-	cmd := exec.Command("bash", "-c", cmdStr)
+// Execute exécute une commande du CLI ovhcloud. Les arguments sont passés tels quels, sans shell,
+// pour que les valeurs issues des flags (service, cluster) ne soient jamais interprétées
+func (o *OVHClient) Execute(args ...string) (string, error) {
+	args = append(args, "--endpoint", o.endpoint, "--output", "json")
+	cmd := exec.Command("ovhcloud-cli", args...)
 	output, err := cmd.CombinedOutput()
 	result := string(output)
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %s %w", cmdStr, result, err)
+		return "", fmt.Errorf("ovhcloud-cli %s: %s %w", strings.Join(args, " "), result, err)
 	}
 
 	return result, nil
 }
 
+// Request exécute un appel brut à l'API OVHcloud sur une ressource du cluster
+// (path relatif à /cloud/project/{serviceName}/database/postgresql/{clusterId})
+func (o *OVHClient) Request(method string, path string) (string, error) {
+	if o.clusterID == "" {
+		return "", fmt.Errorf("OVH: cluster ID non spécifié")
+	}
+
+	url := fmt.Sprintf("/cloud/project/%s/database/postgresql/%s%s", o.serviceName, o.clusterID, path)
+	output, err := o.Execute("api", strings.ToLower(method), url)
+	if err != nil {
+		return "", fmt.Errorf("OVH: Request %s %s: %w", method, path, err)
+	}
+
+	return output, nil
+}
+
 // ListDatabases retourne toutes les instances PostgreSQL du service
 func (o *OVHClient) ListDatabases() ([]DatabaseInstance, error) {
 	// Construire la commande pour lister les databases
-	args := fmt.Sprintf("--service-name %s", o.serviceName)
-	output, err := o.Execute("publiccloud", "database list", args)
+	output, err := o.Execute("publiccloud", "database", "list", "--service-name", o.serviceName)
 	if err != nil {
 		return nil, fmt.Errorf("OVH: ListDatabases: %w", err)
 	}
@@ -89,8 +102,7 @@ func (o *OVHClient) ListDatabases() ([]DatabaseInstance, error) {
 
 	// Pour chaque cluster ID, récupérer les détails
 	for _, id := range clusterIDs {
-		args := fmt.Sprintf("--service-name %s --cluster-id %s", o.serviceName, id)
-		output, err := o.Execute("publiccloud", "database info", args)
+		output, err := o.Execute("publiccloud", "database", "info", "--service-name", o.serviceName, "--cluster-id", id)
 		if err != nil {
 			continue
 		}
@@ -116,8 +128,7 @@ func (o *OVHClient) GetDatabase() (*DatabaseInstance, error) {
 		return nil, fmt.Errorf("OVH: cluster ID non spécifié")
 	}
 
-	args := fmt.Sprintf("--service-name %s --cluster-id %s", o.serviceName, o.clusterID)
-	output, err := o.Execute("publiccloud", "database info", args)
+	output, err := o.Execute("publiccloud", "database", "info", "--service-name", o.serviceName, "--cluster-id", o.clusterID)
 	if err != nil {
 		return nil, fmt.Errorf("OVH: GetDatabase: %w", err)
 	}
@@ -166,3 +177,133 @@ func (o *OVHClient) GetConnectionString() (string, int, error) {
 	endpoint := instance.Endpoints[0]
 	return endpoint.Domain, endpoint.Port, nil
 }
+
+// GenPgPass réinitialise le mot de passe de l'utilisateur et génère l'entrée .pgpass correspondante
+// Attention : l'ancien mot de passe de l'utilisateur n'est plus valide après l'appel
+func (o *OVHClient) GenPgPass(username string, database string) (string, error) {
+	host, port, err := o.GetConnectionString()
+	if err != nil {
+		return "", fmt.Errorf("OVH: GetConnectionString: %w", err)
+	}
+
+	user, err := o.GetUserByName(username)
+	if err != nil {
+		return "", fmt.Errorf("OVH: GetUserByName: %w", err)
+	}
+
+	user, err = o.ResetUserPassword(user.ID)
+	if err != nil {
+		return "", fmt.Errorf("OVH: ResetUserPassword: %w", err)
+	}
+
+	entry := pgpass.Entry{
+		Hostname: host,
+		Port:     port,
+		Database: database,
+		Username: user.Username,
+		Password: user.Password,
+	}
+	return entry.String(), nil
+}
+
+// LoadAdvancedConfiguration récupère la configuration PostgreSQL avancée du cluster
+func (o *OVHClient) LoadAdvancedConfiguration() error {
+	output, err := o.Request("GET", "/advancedConfiguration")
+	if err != nil {
+		return fmt.Errorf("OVH: LoadAdvancedConfiguration: %w", err)
+	}
+
+	var configuration AdvancedConfiguration
+	err = json.Unmarshal([]byte(output), &configuration)
+	if err != nil {
+		return fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+
+	o.advancedConfiguration = configuration
+	return nil
+}
+
+// GetAdvancedConfiguration retourne la configuration avancée, en la chargeant si nécessaire
+func (o *OVHClient) GetAdvancedConfiguration() (AdvancedConfiguration, error) {
+	if o.advancedConfiguration == nil {
+		err := o.LoadAdvancedConfiguration()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o.advancedConfiguration, nil
+}
+
+// GetAdvancedConfigurationCapabilities retourne la liste des clés de configuration avancée autorisées
+func (o *OVHClient) GetAdvancedConfigurationCapabilities() ([]AdvancedConfigurationCapability, error) {
+	output, err := o.Request("GET", "/capabilities/advancedConfiguration")
+	if err != nil {
+		return nil, fmt.Errorf("OVH: GetAdvancedConfigurationCapabilities: %w", err)
+	}
+
+	var capabilities []AdvancedConfigurationCapability
+	err = json.Unmarshal([]byte(output), &capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+
+	return capabilities, nil
+}
+
+// GetConfigurationValue retourne la valeur d'un paramètre de la configuration avancée
+func (o *OVHClient) GetConfigurationValue(name string) (string, error) {
+	configuration, err := o.GetAdvancedConfiguration()
+	if err != nil {
+		return "", fmt.Errorf("GetAdvancedConfiguration: %w", err)
+	}
+
+	value, _ := configuration.GetValueByName(name)
+	return value, nil
+}
+
+func (o *OVHClient) GenPostgreSQLConf() (string, error) {
+	configuration, err := o.GetAdvancedConfiguration()
+	if err != nil {
+		return "", fmt.Errorf("GetAdvancedConfiguration: %w", err)
+	}
+
+	var result strings.Builder
+	_, err = result.WriteString("ParameterName;ParameterValue\n")
+	if err != nil {
+		return "", fmt.Errorf("WriteString: %w", err)
+	}
+
+	for _, name := range configuration.Names() {
+		if configuration[name] != "" {
+			str := fmt.Sprintf("%s;%s\n", strings.TrimPrefix(name, "pg."), configuration[name])
+			_, err := result.WriteString(str)
+			if err != nil {
+				return "", fmt.Errorf("WriteString: %w", err)
+			}
+		}
+	}
+	return result.String(), nil
+}
+
+// Check évalue un profil de vérification embarqué sur la configuration avancée du cluster.
+// Seuls les paramètres exposés par OVHcloud sont vérifiables, les autres prennent la valeur par défaut de PostgreSQL
+func (o *OVHClient) Check(profile string) ([]check.Result, error) {
+	configuration, err := o.GetAdvancedConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("GetAdvancedConfiguration: %w", err)
+	}
+
+	results, err := check.Run(profile, "ovh", func(name string) (string, bool) {
+		value, _ := configuration.GetValueByName(name)
+		return value, value != ""
+	})
+	if err != nil {
+		return nil, fmt.Errorf("check: Run: %w", err)
+	}
+	return results, nil
+}
+
+// CheckPgbadger vérifie les paramètres nécessaires à pgbadger
+func (o *OVHClient) CheckPgbadger() ([]check.Result, error) {
+	return o.Check("pgbadger")
+}
@@ -0,0 +1,54 @@
+package ovh
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/security"
+)
+
+// ListIPRestrictions retourne les plages d'adresses autorisées à se connecter au cluster
+func (o *OVHClient) ListIPRestrictions() ([]string, error) {
+	output, err := o.Request("GET", "/ipRestriction")
+	if err != nil {
+		return nil, fmt.Errorf("Request: %w", err)
+	}
+
+	var ips []string
+	err = json.Unmarshal([]byte(output), &ips)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+	return ips, nil
+}
+
+// SecurityFindings évalue l'exposition du cluster. OVHcloud impose TLS et chiffre le stockage,
+// seules les restrictions d'adresses IP sont configurables
+func (o *OVHClient) SecurityFindings() ([]security.Finding, error) {
+	ips, err := o.ListIPRestrictions()
+	if err != nil {
+		return nil, fmt.Errorf("ListIPRestrictions: %w", err)
+	}
+
+	var open []string
+	for _, ip := range ips {
+		if security.IsOpenToInternet(ip) {
+			open = append(open, ip)
+		}
+	}
+
+	var findings []security.Finding
+	if len(open) > 0 {
+		findings = append(findings, security.Finding{
+			Provider:    "ovh",
+			Resource:    o.clusterID,
+			Check:       security.CheckPublicExposure,
+			Severity:    security.SeverityHigh,
+			Detail:      fmt.Sprintf("restrictions IP ouvertes à tout Internet sur le port %d : %s", security.PostgreSQLPort, strings.Join(open, ", ")),
+			Remediation: "Remplacer 0.0.0.0/0 par les adresses des clients autorisés",
+		})
+	}
+
+	return findings, nil
+}
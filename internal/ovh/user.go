@@ -0,0 +1,87 @@
+package ovh
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// User représente un utilisateur PostgreSQL d'un cluster OVHcloud
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+	Roles     []string  `json:"roles,omitempty"`
+	Password  string    `json:"password,omitempty"`
+}
+
+// ListUsers retourne les utilisateurs PostgreSQL du cluster
+func (o *OVHClient) ListUsers() ([]User, error) {
+	output, err := o.Request("GET", "/user")
+	if err != nil {
+		return nil, fmt.Errorf("OVH: ListUsers: %w", err)
+	}
+
+	var userIDs []string
+	err = json.Unmarshal([]byte(output), &userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal user IDs: %w", err)
+	}
+
+	var users []User
+
+	// Pour chaque user ID, récupérer les détails
+	for _, id := range userIDs {
+		output, err := o.Request("GET", fmt.Sprintf("/user/%s", id))
+		if err != nil {
+			return nil, fmt.Errorf("OVH: GetUser: %w", err)
+		}
+
+		var user User
+		err = json.Unmarshal([]byte(output), &user)
+		if err != nil {
+			return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetUserByName retourne l'utilisateur PostgreSQL portant le nom donné
+func (o *OVHClient) GetUserByName(username string) (*User, error) {
+	users, err := o.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("OVH: ListUsers: %w", err)
+	}
+
+	for _, user := range users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+
+	return nil, fmt.Errorf("OVH: utilisateur %s introuvable", username)
+}
+
+// ResetUserPassword réinitialise le mot de passe d'un utilisateur et retourne les nouveaux identifiants
+func (o *OVHClient) ResetUserPassword(userID string) (*User, error) {
+	output, err := o.Request("POST", fmt.Sprintf("/user/%s/credentials/reset", userID))
+	if err != nil {
+		return nil, fmt.Errorf("OVH: ResetUserPassword: %w", err)
+	}
+
+	var user User
+	err = json.Unmarshal([]byte(output), &user)
+	if err != nil {
+		return nil, fmt.Errorf("OVH: Unmarshal: %w", err)
+	}
+
+	if user.Password == "" {
+		return nil, fmt.Errorf("OVH: aucun mot de passe retourné pour l'utilisateur %s", userID)
+	}
+
+	return &user, nil
+}
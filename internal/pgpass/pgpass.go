@@ -0,0 +1,124 @@
+package pgpass
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Entry représente une ligne du fichier .pgpass (hostname:port:database:username:password)
+type Entry struct {
+	Hostname string
+	Port     int
+	Database string
+	Username string
+	Password string
+}
+
+// String retourne la ligne .pgpass correspondant à l'entrée
+func (e Entry) String() string {
+	return fmt.Sprintf("%s:%d:%s:%s:%s",
+		escape(e.Hostname),
+		e.Port,
+		escape(orWildcard(e.Database)),
+		escape(e.Username),
+		escape(e.Password))
+}
+
+// escape protège les caractères ':' et '\' comme l'attend libpq
+func escape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, ":", `\:`)
+}
+
+func orWildcard(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return value
+}
+
+// Masked retourne la ligne .pgpass avec le mot de passe masqué, pour les logs
+func (e Entry) Masked() string {
+	masked := e
+	masked.Password = Mask(e.Password)
+	return masked.String()
+}
+
+// Mask masque une valeur sensible
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "********"
+}
+
+// key retourne le préfixe hostname:port:database:username identifiant l'entrée
+func (e Entry) key() string {
+	return fmt.Sprintf("%s:%d:%s:%s:",
+		escape(e.Hostname),
+		e.Port,
+		escape(orWildcard(e.Database)),
+		escape(e.Username))
+}
+
+// DefaultPath retourne le chemin du fichier .pgpass (PGPASSFILE ou ~/.pgpass)
+func DefaultPath() (string, error) {
+	if path := os.Getenv("PGPASSFILE"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserHomeDir: %w", err)
+	}
+
+	return filepath.Join(home, ".pgpass"), nil
+}
+
+// Write ajoute les entrées au fichier .pgpass en remplaçant celles portant sur le même
+// hostname:port:database:username. Le fichier est écrit avec le mode 0600 exigé par libpq
+func Write(path string, entries []Entry) error {
+	var lines []string
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == "" {
+			continue
+		}
+		replaced := false
+		for _, entry := range entries {
+			if strings.HasPrefix(line, entry.key()) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, entry := range entries {
+		lines = append(lines, entry.String())
+		slog.Info("Entrée .pgpass écrite", slog.String("entry", entry.Masked()))
+	}
+
+	err = os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+	if err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	// os.WriteFile ne modifie pas le mode d'un fichier existant
+	err = os.Chmod(path, 0o600)
+	if err != nil {
+		return fmt.Errorf("os.Chmod: %w", err)
+	}
+
+	return nil
+}
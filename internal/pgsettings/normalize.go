@@ -0,0 +1,60 @@
+package pgsettings
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NormalizeBool retourne la forme canonique (on/off) d'une valeur booléenne PostgreSQL
+func NormalizeBool(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1":
+		return "on", true
+	case "off", "false", "no", "0":
+		return "off", true
+	}
+	return "", false
+}
+
+// Equivalent indique si deux valeurs sont identiques une fois normalisées (casse, booléens, unités)
+func Equivalent(a string, b string, unit string) bool {
+	equal, _ := Compare(a, b, unit)
+	return equal
+}
+
+// Assignment représente une affectation name=value passée en ligne de commande
+type Assignment struct {
+	Name  string
+	Value string
+}
+
+// ParseAssignments analyse une liste d'arguments de la forme name=value
+func ParseAssignments(args []string) ([]Assignment, error) {
+	assignments := make([]Assignment, 0, len(args))
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("argument %s invalide (format attendu : name=value)", arg)
+		}
+		assignments = append(assignments, Assignment{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return assignments, nil
+}
+
+var rangeRegexp = regexp.MustCompile(`^(-?[0-9]+(?:\.[0-9]+)?)-(-?[0-9]+(?:\.[0-9]+)?)$`)
+
+// InRange indique si un nombre respecte une contrainte de valeurs autorisées
+// au format des fournisseurs cloud : intervalle min-max ou valeur exacte
+func InRange(allowed string, number float64) bool {
+	allowed = strings.TrimSpace(allowed)
+	if matches := rangeRegexp.FindStringSubmatch(allowed); matches != nil {
+		minimum, _ := strconv.ParseFloat(matches[1], 64)
+		maximum, _ := strconv.ParseFloat(matches[2], 64)
+		return number >= minimum && number <= maximum
+	}
+
+	exact, err := strconv.ParseFloat(allowed, 64)
+	return err == nil && exact == number
+}
@@ -0,0 +1,97 @@
+package pgsettings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// Setting représente une ligne de pg_settings
+type Setting struct {
+	Name           string `json:"name"`
+	Setting        string `json:"setting"`
+	Unit           string `json:"unit,omitempty"`
+	Source         string `json:"source"`
+	SourceFile     string `json:"sourcefile,omitempty"`
+	Context        string `json:"context"`
+	PendingRestart bool   `json:"pending_restart"`
+}
+
+// RoleSetting représente une surcharge définie par ALTER ROLE / ALTER DATABASE ... SET (pg_db_role_setting)
+type RoleSetting struct {
+	Database string `json:"database"`
+	Role     string `json:"role"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
+
+const settingsQuery = `SELECT name, setting, coalesce(unit, ''), source, coalesce(sourcefile, ''), context, pending_restart::text
+FROM pg_settings
+ORDER BY name`
+
+const roleSettingsQuery = `SELECT coalesce(d.datname, '*'), coalesce(r.rolname, '*'), c.config
+FROM pg_db_role_setting s
+LEFT JOIN pg_database d ON d.oid = s.setdatabase
+LEFT JOIN pg_roles r ON r.oid = s.setrole
+CROSS JOIN LATERAL unnest(s.setconfig) AS c(config)
+ORDER BY 1, 2, 3`
+
+// LoadSettings lit les paramètres actifs du serveur (pg_settings)
+func LoadSettings(ctx context.Context, conn *pgx.Conn) ([]Setting, error) {
+	_, rows, err := postgres.Query(ctx, conn, settingsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("pg_settings: %w", err)
+	}
+
+	settings := make([]Setting, 0, len(rows))
+	for _, row := range rows {
+		settings = append(settings, Setting{
+			Name:           row[0],
+			Setting:        row[1],
+			Unit:           row[2],
+			Source:         row[3],
+			SourceFile:     row[4],
+			Context:        row[5],
+			PendingRestart: row[6] == "true",
+		})
+	}
+
+	return settings, nil
+}
+
+// LoadRoleSettings lit les surcharges par rôle et par base de données (pg_db_role_setting)
+func LoadRoleSettings(ctx context.Context, conn *pgx.Conn) ([]RoleSetting, error) {
+	_, rows, err := postgres.Query(ctx, conn, roleSettingsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("pg_db_role_setting: %w", err)
+	}
+
+	roleSettings := make([]RoleSetting, 0, len(rows))
+	for _, row := range rows {
+		name, value, found := strings.Cut(row[2], "=")
+		if !found {
+			continue
+		}
+		roleSettings = append(roleSettings, RoleSetting{
+			Database: row[0],
+			Role:     row[1],
+			Name:     name,
+			Value:    value,
+		})
+	}
+
+	return roleSettings, nil
+}
+
+// Find retourne le paramètre portant ce nom (insensible à la casse)
+func Find(settings []Setting, name string) (Setting, bool) {
+	for _, setting := range settings {
+		if strings.EqualFold(setting.Name, name) {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
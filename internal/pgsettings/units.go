@@ -0,0 +1,123 @@
+package pgsettings
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Multiplicateurs des unités mémoire (en octets) et temps (en microsecondes) acceptées par PostgreSQL
+var memoryUnits = map[string]float64{
+	"B":  1,
+	"kB": 1024,
+	"MB": 1024 * 1024,
+	"GB": 1024 * 1024 * 1024,
+	"TB": 1024 * 1024 * 1024 * 1024,
+}
+
+var timeUnits = map[string]float64{
+	"us":  1,
+	"ms":  1000,
+	"s":   1000 * 1000,
+	"min": 60 * 1000 * 1000,
+	"h":   60 * 60 * 1000 * 1000,
+	"d":   24 * 60 * 60 * 1000 * 1000,
+}
+
+var valueRegexp = regexp.MustCompile(`^\s*(-?[0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+var unitRegexp = regexp.MustCompile(`^([0-9]*)([a-zA-Z]+)$`)
+
+// unitMultiplier retourne le multiplicateur d'une unité de pg_settings (ex: 8kB, 16MB, ms) et sa famille
+func unitMultiplier(unit string) (float64, string, bool) {
+	matches := unitRegexp.FindStringSubmatch(unit)
+	if matches == nil {
+		return 0, "", false
+	}
+
+	factor := 1.0
+	if matches[1] != "" {
+		f, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, "", false
+		}
+		factor = f
+	}
+
+	if multiplier, ok := memoryUnits[matches[2]]; ok {
+		return factor * multiplier, "memory", true
+	}
+	if multiplier, ok := timeUnits[matches[2]]; ok {
+		return factor * multiplier, "time", true
+	}
+	return 0, "", false
+}
+
+// Normalize convertit une valeur dans l'unité de base de sa famille (octets ou microsecondes).
+// Une valeur sans unité est exprimée dans l'unité du paramètre (colonne unit de pg_settings)
+func Normalize(value string, unit string) (float64, bool) {
+	matches := valueRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, false
+	}
+
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	// -1 et 0 désactivent généralement le paramètre, quelle que soit l'unité
+	if number == -1 || number == 0 {
+		return number, true
+	}
+
+	suffix := matches[2]
+	if suffix == "" {
+		if unit == "" {
+			return number, true
+		}
+		multiplier, _, ok := unitMultiplier(unit)
+		if !ok {
+			return number, true
+		}
+		return number * multiplier, true
+	}
+
+	multiplier, family, ok := unitMultiplier(suffix)
+	if !ok {
+		return 0, false
+	}
+	if _, parameterFamily, ok := unitMultiplier(unit); ok && parameterFamily != family {
+		return 0, false
+	}
+	return number * multiplier, true
+}
+
+// Compare indique si deux valeurs sont identiques, et si elles ne diffèrent que par leur unité
+// (ex: 128MB et 16384 pour un paramètre exprimé en 8kB)
+func Compare(expected string, actual string, unit string) (equal bool, unitsOnly bool) {
+	if strings.EqualFold(strings.TrimSpace(expected), strings.TrimSpace(actual)) {
+		return true, false
+	}
+
+	// Booléens : on, true, yes et 1 sont équivalents
+	if expectedBool, ok := NormalizeBool(expected); ok {
+		if actualBool, ok := NormalizeBool(actual); ok {
+			return expectedBool == actualBool, false
+		}
+	}
+
+	expectedValue, ok := Normalize(expected, unit)
+	if !ok {
+		return false, false
+	}
+	actualValue, ok := Normalize(actual, unit)
+	if !ok {
+		return false, false
+	}
+
+	if math.Abs(expectedValue-actualValue) < 0.5 {
+		return true, true
+	}
+	return false, false
+}
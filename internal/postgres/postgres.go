@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Connect ouvre une connexion pgx. Sans mot de passe, pgx utilise le fichier .pgpass (ou PGPASSFILE)
+func Connect(ctx context.Context, c Connection) (*pgx.Conn, error) {
+	config, err := pgx.ParseConfig(c.ConnString())
+	if err != nil {
+		return nil, fmt.Errorf("pgx.ParseConfig: %w", err)
+	}
+
+	if c.Password != "" {
+		config.Password = c.Password
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("pgx.ConnectConfig: %w", err)
+	}
+
+	return conn, nil
+}
+
+// Query exécute une requête et retourne les noms de colonnes et les valeurs au format texte de PostgreSQL
+// (numeric, interval, tableaux, timestamptz... tels que psql les affiche). Une valeur NULL est une chaîne vide
+func Query(ctx context.Context, conn *pgx.Conn, sql string, args ...any) ([]string, [][]string, error) {
+	// Toutes les colonnes sont demandées au format texte : les valeurs ne sont pas décodées en types Go
+	args = append([]any{pgx.QueryResultFormats{pgx.TextFormatCode}}, args...)
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Query: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for _, field := range rows.FieldDescriptions() {
+		columns = append(columns, field.Name)
+	}
+
+	var result [][]string
+	for rows.Next() {
+		values := rows.RawValues()
+		record := make([]string, len(values))
+		for i, value := range values {
+			if value != nil {
+				record[i] = string(value)
+			}
+		}
+		result = append(result, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return columns, result, nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Connection regroupe les paramètres de connexion à une instance PostgreSQL
+type Connection struct {
+	Host        string
+	Port        int
+	Database    string
+	Username    string
+	Password    string
+	SSLMode     string
+	SSLRootCert string
+}
+
+// ConnString retourne la chaîne de connexion libpq (sans le mot de passe)
+func (c Connection) ConnString() string {
+	parts := []string{
+		fmt.Sprintf("host=%s", c.Host),
+		fmt.Sprintf("port=%d", c.Port),
+		fmt.Sprintf("dbname=%s", c.Database),
+		fmt.Sprintf("user=%s", c.Username),
+	}
+	if c.SSLMode != "" {
+		parts = append(parts, fmt.Sprintf("sslmode=%s", c.SSLMode))
+	}
+	if c.SSLRootCert != "" {
+		parts = append(parts, fmt.Sprintf("sslrootcert=%s", c.SSLRootCert))
+	}
+	return strings.Join(parts, " ")
+}
+
+// LaunchPsql lance psql en mode interactif, le mot de passe étant transmis via PGPASSWORD
+func LaunchPsql(c Connection) error {
+	cmd := exec.Command("psql", c.ConnString())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if c.Password != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PGPASSWORD=%s", c.Password))
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("psql: %w", err)
+	}
+
+	return nil
+}
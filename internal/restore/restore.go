@@ -0,0 +1,264 @@
+package restore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+// Étapes d'un test de restauration
+const (
+	StepCreate   = "create"
+	StepWait     = "wait"
+	StepValidate = "validate"
+	StepDelete   = "delete"
+)
+
+// Statuts d'une étape
+const (
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// PollInterval est l'intervalle d'interrogation de l'état du clone
+const PollInterval = 30 * time.Second
+
+// maxNameLength est la longueur maximale d'un identifiant d'instance RDS ou de serveur Azure
+const maxNameLength = 63
+
+// Étiquettes posées sur chaque clone pour le retrouver et le supprimer
+const (
+	TagPurpose = "cloud-helper"
+	TagSource  = "restore-source"
+)
+
+// Step décrit une étape exécutée, sa durée et son résultat
+type Step struct {
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Seconds  float64   `json:"seconds"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// Report est le rapport d'un test de restauration : durée de chaque étape et durée de la restauration
+// (création et attente de disponibilité du clone)
+type Report struct {
+	Provider       string            `json:"provider"`
+	Source         string            `json:"source"`
+	Clone          string            `json:"clone"`
+	RestoreTime    *time.Time        `json:"restore_time,omitempty"`
+	Tags           map[string]string `json:"tags"`
+	Steps          []Step            `json:"steps"`
+	RestoreSeconds float64           `json:"restore_seconds"`
+}
+
+// GenerateName retourne le nom du clone : <source>-rt-<AAAAMMJJHHMM>, tronqué à 63 caractères
+func GenerateName(source string, now time.Time) string {
+	suffix := "-rt-" + now.UTC().Format("200601021504")
+	source = strings.TrimRight(source[:min(len(source), maxNameLength-len(suffix))], "-")
+	return source + suffix
+}
+
+// Tags retourne les étiquettes du clone : étiquettes supplémentaires, puis usage et source. Ces deux
+// dernières sont posées en dernier pour qu'une étiquette supplémentaire ne puisse pas remplacer les
+// étiquettes vérifiées par CheckDeletable
+func Tags(source string, extra map[string]string) map[string]string {
+	tags := make(map[string]string, len(extra)+2)
+	for key, value := range extra {
+		tags[key] = value
+	}
+	tags[TagPurpose] = "restore-test"
+	tags[TagSource] = source
+	return tags
+}
+
+// CheckDeletable refuse de supprimer une ressource qui n'est pas un clone de test de restauration
+// de la source : la suppression ne peut viser que les étiquettes posées par Tags
+func CheckDeletable(clone string, source string, tags map[string]string) error {
+	if clone == source {
+		return fmt.Errorf("%s est l'instance source", clone)
+	}
+	if tags[TagPurpose] != "restore-test" || tags[TagSource] != source {
+		return fmt.Errorf("%s n'a pas les étiquettes %s=restore-test et %s=%s, suppression refusée", clone, TagPurpose, TagSource, source)
+	}
+	return nil
+}
+
+// NewReport initialise le rapport d'un test de restauration
+func NewReport(provider string, source string, clone string, restoreTime *time.Time, tags map[string]string) *Report {
+	return &Report{
+		Provider:    provider,
+		Source:      source,
+		Clone:       clone,
+		RestoreTime: restoreTime,
+		Tags:        tags,
+		Steps:       []Step{},
+	}
+}
+
+// Run exécute une étape et enregistre sa durée et son résultat
+func (r *Report) Run(name string, fn func() (string, error)) error {
+	step := Step{Name: name, Started: time.Now()}
+	slog.Info("Étape", slog.String("step", name), slog.String("clone", r.Clone))
+
+	detail, err := fn()
+	step.Finished = time.Now()
+	step.Seconds = math.Round(step.Finished.Sub(step.Started).Seconds())
+	step.Status = StatusDone
+	step.Detail = detail
+	if err != nil {
+		step.Status = StatusFailed
+		step.Detail = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+
+	if (name == StepCreate || name == StepWait) && step.Status == StatusDone {
+		r.RestoreSeconds += step.Seconds
+	}
+	return err
+}
+
+// Skip enregistre une étape non demandée
+func (r *Report) Skip(name string, reason string) {
+	r.Steps = append(r.Steps, Step{Name: name, Status: StatusSkipped, Detail: reason})
+}
+
+// DeleteBlocked indique si le clone ne doit pas être supprimé, et pourquoi : sa création a échoué, ou l'attente
+// a échoué ou expiré alors que le clone est peut-être encore en cours de création
+func (r *Report) DeleteBlocked() (string, bool) {
+	for _, step := range r.Steps {
+		if step.Status != StatusFailed {
+			continue
+		}
+		switch step.Name {
+		case StepCreate:
+			return "création en échec", true
+		case StepWait:
+			return fmt.Sprintf("attente en échec ou expirée : %s est peut-être encore en cours de création, à supprimer une fois disponible", r.Clone), true
+		}
+	}
+	return "", false
+}
+
+// Wait interroge l'état du clone jusqu'à ce qu'il soit disponible, en affichant la progression.
+// status retourne l'état courant, s'il est disponible, et une erreur pour un état définitivement en échec
+func Wait(status func() (string, bool, error), interval time.Duration, timeout time.Duration) (string, error) {
+	start := time.Now()
+	for {
+		state, ready, err := status()
+		if err != nil {
+			return state, err
+		}
+
+		elapsed := time.Since(start).Round(time.Second)
+		slog.Info("Attente du clone", slog.String("state", state), slog.String("elapsed", elapsed.String()))
+		if ready {
+			return fmt.Sprintf("%s après %s", state, elapsed), nil
+		}
+		if elapsed >= timeout {
+			return state, fmt.Errorf("clone toujours %s après %s", state, timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Validate exécute le script SQL de validation sur le clone. Sans paramètre, pgx utilise le protocole
+// simple : le script peut contenir plusieurs requêtes et échoue à la première erreur (RAISE EXCEPTION...)
+func Validate(ctx context.Context, connection postgres.Connection, scriptFile string) (string, error) {
+	script, err := os.ReadFile(scriptFile)
+	if err != nil {
+		return "", fmt.Errorf("ReadFile: %w", err)
+	}
+
+	conn, err := postgres.Connect(ctx, connection)
+	if err != nil {
+		return "", fmt.Errorf("postgres.Connect: %w", err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	tag, err := conn.Exec(ctx, string(script))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(scriptFile), err)
+	}
+	return fmt.Sprintf("%s: %s", filepath.Base(scriptFile), tag.String()), nil
+}
+
+// Print affiche les étapes au format demandé (default, csv, json, markdown)
+func Print(format string, report *Report) error {
+	if format == "json" {
+		return output.PrintJSON(report)
+	}
+
+	columns := []string{"Step", "Status", "Started", "Duration", "Detail"}
+	rows := make([][]string, 0, len(report.Steps)+1)
+	for _, step := range report.Steps {
+		started := ""
+		if !step.Started.IsZero() {
+			started = step.Started.UTC().Format(time.DateTime)
+		}
+		rows = append(rows, []string{step.Name, step.Status, started, formatSeconds(step.Seconds), step.Detail})
+	}
+	rows = append(rows, []string{"restore", "", "", formatSeconds(report.RestoreSeconds), fmt.Sprintf("%s -> %s", report.Source, report.Clone)})
+	return output.PrintRows(format, columns, rows)
+}
+
+// Write écrit le rapport dans un fichier, en JSON (extension .json) ou en Markdown
+func Write(path string, report *Report) error {
+	var content []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("json.MarshalIndent: %w", err)
+		}
+		content = append(data, '\n')
+	} else {
+		content = []byte(markdown(report))
+	}
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("WriteFile: %w", err)
+	}
+	return nil
+}
+
+// markdown met en forme le rapport pour un compte rendu de test de restauration
+func markdown(report *Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Restore test %s\n\n", report.Clone)
+	fmt.Fprintf(&b, "- Provider: %s\n", report.Provider)
+	fmt.Fprintf(&b, "- Source: %s\n", report.Source)
+	if report.RestoreTime != nil {
+		fmt.Fprintf(&b, "- Restore time: %s\n", report.RestoreTime.UTC().Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(&b, "- Restore time: latest restorable time\n")
+	}
+	fmt.Fprintf(&b, "- Restore duration: %s\n\n", formatSeconds(report.RestoreSeconds))
+
+	b.WriteString("| Step | Status | Started | Duration | Detail |\n")
+	b.WriteString("|------|--------|---------|----------|--------|\n")
+	for _, step := range report.Steps {
+		started := ""
+		if !step.Started.IsZero() {
+			started = step.Started.UTC().Format(time.DateTime)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", step.Name, step.Status, started, formatSeconds(step.Seconds),
+			strings.ReplaceAll(step.Detail, "|", "\\|"))
+	}
+	return b.String()
+}
+
+func formatSeconds(seconds float64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
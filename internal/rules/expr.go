@@ -0,0 +1,623 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// ErrUndefined est retournée lorsqu'une expression référence un fait ou un paramètre inconnu
+var ErrUndefined = errors.New("valeur non définie")
+
+// Resolver retourne la valeur d'un identifiant (float64, string ou bool) et false s'il est inconnu
+type Resolver func(name string) (any, bool)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+}
+
+var operators = strings.Fields("&& || == != <= >= < > + - * / ! ( ) ,")
+
+// tokenize découpe une expression en lexèmes. Les nombres peuvent porter une unité PostgreSQL
+// (8GB, 200ms...) et sont alors convertis dans l'unité de base (octets ou microsecondes)
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			literal := string(runes[start:i])
+			number := literal
+			if strings.HasPrefix(number, ".") {
+				// .5 est accepté comme 0.5
+				number = "0" + number
+			}
+			value, ok := pgsettings.Normalize(number, "")
+			if !ok {
+				return nil, fmt.Errorf("nombre %s invalide", literal)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: literal, value: value})
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("chaîne non terminée à la position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i])})
+		default:
+			operator := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "&&", "||", "==", "!=", "<=", ">=":
+					operator = two
+				}
+			}
+			if !slices.Contains(operators, operator) {
+				return nil, fmt.Errorf("caractère %q inattendu à la position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator})
+			i += len([]rune(operator))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// Expression représente une expression analysée, évaluable sur plusieurs instances
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse analyse une expression. Grammaire, par priorité croissante :
+// ||, &&, comparaisons (== != < <= > >=), + -, * /, opérateurs unaires (! -),
+// littéraux (nombres avec unité, chaînes, true, false), identifiants et appels de fonctions
+func Parse(expression string) (*Expression, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("lexème %s inattendu", p.peek().text)
+	}
+	return &Expression{source: expression, root: root}, nil
+}
+
+// String retourne le texte de l'expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval évalue l'expression avec les identifiants fournis par le resolver
+func (e *Expression) Eval(resolve Resolver) (any, error) {
+	return e.root.eval(resolve)
+}
+
+// EvalBool évalue une expression dont le résultat doit être un booléen
+func (e *Expression) EvalBool(resolve Resolver) (bool, error) {
+	value, err := e.Eval(resolve)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("l'expression %s ne retourne pas un booléen", e.source)
+	}
+	return result, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(operators ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, operator := range operators {
+		if t.text == operator {
+			p.pos++
+			return operator, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{operator: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{operator: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	operator, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return comparisonNode{operator: operator, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmeticNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if operator, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{operator: operator, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return literalNode{value: t.value}, nil
+	case tokenString:
+		return literalNode{value: t.text}, nil
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t.text)
+		}
+		return identNode{name: t.text}, nil
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("parenthèse fermante attendue")
+			}
+			return inner, nil
+		}
+	}
+	if t.kind == tokenEOF {
+		return nil, fmt.Errorf("fin d'expression inattendue")
+	}
+	return nil, fmt.Errorf("lexème %s inattendu", t.text)
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	function, ok := functions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("fonction %s inconnue", name)
+	}
+
+	call := callNode{name: strings.ToLower(name), function: function}
+	if _, ok := p.accept(")"); ok {
+		return call, nil
+	}
+	for {
+		argument, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.arguments = append(call.arguments, argument)
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("parenthèse fermante attendue après les arguments de %s", name)
+		}
+		return call, nil
+	}
+}
+
+type node interface {
+	eval(resolve Resolver) (any, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n literalNode) eval(Resolver) (any, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n identNode) eval(resolve Resolver) (any, error) {
+	value, ok := resolve(n.name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", n.name, ErrUndefined)
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+func (n unaryNode) eval(resolve Resolver) (any, error) {
+	value, err := n.operand.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	if n.operator == "!" {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("! attend un booléen")
+		}
+		return !b, nil
+	}
+	number, ok := toNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("- attend un nombre")
+	}
+	return -number, nil
+}
+
+type logicalNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n logicalNode) eval(resolve Resolver) (any, error) {
+	left, err := n.left.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := left.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s attend des booléens", n.operator)
+	}
+	// Évaluation paresseuse : la partie droite peut référencer un paramètre absent
+	if (n.operator == "&&" && !l) || (n.operator == "||" && l) {
+		return l, nil
+	}
+
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := right.(bool)
+	if !ok {
+		return nil, fmt.Errorf("%s attend des booléens", n.operator)
+	}
+	return r, nil
+}
+
+type arithmeticNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n arithmeticNode) eval(resolve Resolver) (any, error) {
+	left, err := n.left.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s attend des nombres", n.operator)
+	}
+
+	switch n.operator {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	}
+	if r == 0 {
+		return nil, fmt.Errorf("division par zéro")
+	}
+	return l / r, nil
+}
+
+type comparisonNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n comparisonNode) eval(resolve Resolver) (any, error) {
+	left, err := n.left.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les chaînes sont comparées sans tenir compte de la casse
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			c := strings.Compare(strings.ToLower(l), strings.ToLower(r))
+			return compare(n.operator, float64(c), 0), nil
+		}
+	}
+
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		if n.operator == "==" || n.operator == "!=" {
+			return (n.operator == "!=") != (fmt.Sprint(left) == fmt.Sprint(right)), nil
+		}
+		return nil, fmt.Errorf("%s attend des valeurs comparables (%v, %v)", n.operator, left, right)
+	}
+	return compare(n.operator, l, r), nil
+}
+
+func compare(operator string, l float64, r float64) bool {
+	switch operator {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	}
+	return l >= r
+}
+
+// toNumber convertit une valeur en nombre ; les booléens valent 1 ou 0 (paramètres RDS)
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+type callNode struct {
+	name      string
+	function  func(arguments []any) (any, error)
+	arguments []node
+}
+
+func (n callNode) eval(resolve Resolver) (any, error) {
+	arguments := make([]any, 0, len(n.arguments))
+	for _, argument := range n.arguments {
+		value, err := argument.eval(resolve)
+		// defined() doit pouvoir recevoir un paramètre absent
+		if n.name == "defined" && errors.Is(err, ErrUndefined) {
+			return false, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+	return n.function(arguments)
+}
+
+// functions regroupe les fonctions disponibles dans les expressions. greatest, least, sum et log
+// reprennent les fonctions des formules des groupes de paramètres RDS
+var functions = map[string]func(arguments []any) (any, error){
+	"defined": func(arguments []any) (any, error) {
+		return len(arguments) > 0, nil
+	},
+	"min":      numbers("min", math.Min),
+	"least":    numbers("least", math.Min),
+	"max":      numbers("max", math.Max),
+	"greatest": numbers("greatest", math.Max),
+	"sum":      numbers("sum", func(a float64, b float64) float64 { return a + b }),
+	"log": func(arguments []any) (any, error) {
+		if len(arguments) != 1 {
+			return nil, fmt.Errorf("log attend un argument")
+		}
+		number, ok := toNumber(arguments[0])
+		if !ok || number <= 0 {
+			return nil, fmt.Errorf("log attend un nombre positif")
+		}
+		return math.Log2(number), nil
+	},
+	"contains": func(arguments []any) (any, error) {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("contains attend deux arguments")
+		}
+		list := fmt.Sprint(arguments[0])
+		item := fmt.Sprint(arguments[1])
+		for _, element := range strings.Split(list, ",") {
+			if strings.EqualFold(strings.Trim(strings.TrimSpace(element), `"`), item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+}
+
+func numbers(name string, reduce func(float64, float64) float64) func(arguments []any) (any, error) {
+	return func(arguments []any) (any, error) {
+		if len(arguments) == 0 {
+			return nil, fmt.Errorf("%s attend au moins un argument", name)
+		}
+		result, ok := toNumber(arguments[0])
+		if !ok {
+			return nil, fmt.Errorf("%s attend des nombres", name)
+		}
+		for _, argument := range arguments[1:] {
+			number, ok := toNumber(argument)
+			if !ok {
+				return nil, fmt.Errorf("%s attend des nombres", name)
+			}
+			result = reduce(result, number)
+		}
+		return result, nil
+	}
+}
+
+// EvalFormula évalue une formule de groupe de paramètres RDS
+// (ex: {DBInstanceClassMemory/32768}, GREATEST({DBInstanceClassMemory/9531392},5000))
+func EvalFormula(formula string, variables map[string]float64) (float64, error) {
+	expression, err := Parse(strings.NewReplacer("{", "(", "}", ")").Replace(formula))
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := expression.Eval(func(name string) (any, bool) {
+		number, ok := variables[name]
+		return number, ok
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	number, ok := toNumber(value)
+	if !ok {
+		return 0, fmt.Errorf("la formule %s ne retourne pas un nombre", formula)
+	}
+	return number, nil
+}
@@ -0,0 +1,218 @@
+package rules
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		expression string
+		texts      []string
+		kinds      []tokenKind
+	}{
+		{"a >= 3", []string{"a", ">=", "3", ""}, []tokenKind{tokenIdent, tokenOperator, tokenNumber, tokenEOF}},
+		{"rds.force_ssl == 1", []string{"rds.force_ssl", "==", "1", ""}, []tokenKind{tokenIdent, tokenOperator, tokenNumber, tokenEOF}},
+		{"x!=.5&&!y", []string{"x", "!=", ".5", "&&", "!", "y", ""}, []tokenKind{tokenIdent, tokenOperator, tokenNumber, tokenOperator, tokenOperator, tokenIdent, tokenEOF}},
+		{`level == 'ddl' || "all"`, []string{"level", "==", "ddl", "||", "all", ""}, []tokenKind{tokenIdent, tokenOperator, tokenString, tokenOperator, tokenString, tokenEOF}},
+		{"max(a, 8GB)", []string{"max", "(", "a", ",", "8GB", ")", ""}, []tokenKind{tokenIdent, tokenOperator, tokenIdent, tokenOperator, tokenNumber, tokenOperator, tokenEOF}},
+	}
+	for _, tt := range tests {
+		tokens, err := tokenize(tt.expression)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expression, err)
+		}
+		var texts []string
+		var kinds []tokenKind
+		for _, token := range tokens {
+			texts = append(texts, token.text)
+			kinds = append(kinds, token.kind)
+		}
+		if !reflect.DeepEqual(texts, tt.texts) || !reflect.DeepEqual(kinds, tt.kinds) {
+			t.Errorf("%s = %q %v, attendu %q %v", tt.expression, texts, kinds, tt.texts, tt.kinds)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	for _, expression := range []string{"a # b", "'non terminée", "12XB", "a & b"} {
+		if _, err := tokenize(expression); err == nil {
+			t.Errorf("%s: erreur attendue", expression)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	// Les mémoires sont converties en octets et les durées en microsecondes
+	tests := []struct {
+		literal string
+		value   float64
+	}{
+		{"42", 42},
+		{"1.5", 1.5},
+		{"8kB", 8 * 1024},
+		{"128MB", 128 * 1024 * 1024},
+		{"8GB", 8 * 1024 * 1024 * 1024},
+		{"1TB", 1024 * 1024 * 1024 * 1024},
+		{"200ms", 200 * 1000},
+		{"30s", 30 * 1000 * 1000},
+		{"15min", 15 * 60 * 1000 * 1000},
+		{"1h", 60 * 60 * 1000 * 1000},
+		{"1d", 24 * 60 * 60 * 1000 * 1000},
+	}
+	for _, tt := range tests {
+		tokens, err := tokenize(tt.literal)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.literal, err)
+		}
+		if tokens[0].kind != tokenNumber || tokens[0].value != tt.value {
+			t.Errorf("%s = %v, attendu %v", tt.literal, tokens[0].value, tt.value)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	facts := map[string]any{
+		"vcpu":                   float64(8),
+		"memory":                 float64(16 * 1024 * 1024 * 1024),
+		"shared_buffers":         float64(4 * 1024 * 1024 * 1024),
+		"ha":                     true,
+		"provider":               "rds",
+		"shared_preload_library": "pg_stat_statements,auto_explain",
+	}
+	resolve := func(name string) (any, bool) {
+		value, ok := facts[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		expression string
+		value      any
+	}{
+		// Priorité : * / avant + -, arithmétique avant comparaison, && avant ||
+		{"1 + 2 * 3", float64(7)},
+		{"(1 + 2) * 3", float64(9)},
+		{"10 - 4 - 3", float64(3)},
+		{"12 / 3 / 2", float64(2)},
+		{"-2 * 3", float64(-6)},
+		{"1 + 2 == 3", true},
+		{"true || false && false", true},
+		{"(true || false) && false", false},
+		{"!ha || vcpu < 4", false},
+		{"!(vcpu < 4)", true},
+		// Unités et faits
+		{"shared_buffers >= 0.15 * memory && shared_buffers <= 0.40 * memory", true},
+		{"memory == 16GB", true},
+		{"2 * 1GB", float64(2 * 1024 * 1024 * 1024)},
+		// Chaînes comparées sans tenir compte de la casse
+		{"provider == 'RDS'", true},
+		{"provider != 'gcp'", true},
+		// Fonctions
+		{"max(1, vcpu, 3)", float64(8)},
+		{"greatest(1, 5) + least(4, 2)", float64(7)},
+		{"sum(1, 2, 3)", float64(6)},
+		{"log(1024)", float64(10)},
+		{"contains(shared_preload_library, 'auto_explain')", true},
+		{"defined(vcpu) && !defined(unknown)", true},
+		// Évaluation paresseuse : la partie droite n'est pas évaluée
+		{"vcpu < 4 && unknown > 1", false},
+		{"vcpu >= 4 || unknown > 1", true},
+	}
+	for _, tt := range tests {
+		expression, err := Parse(tt.expression)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tt.expression, err)
+		}
+		value, err := expression.Eval(resolve)
+		if err != nil {
+			t.Fatalf("%s: Eval: %v", tt.expression, err)
+		}
+		if value != tt.value {
+			t.Errorf("%s = %v, attendu %v", tt.expression, value, tt.value)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	resolve := func(name string) (any, bool) {
+		if name == "vcpu" {
+			return float64(2), true
+		}
+		return nil, false
+	}
+
+	tests := []struct {
+		expression string
+		undefined  bool
+	}{
+		{"unknown > 1", true},
+		{"vcpu / 0 > 1", false},
+		{"vcpu && true", false},
+		{"!vcpu", false},
+		{"'a' < 1", false},
+	}
+	for _, tt := range tests {
+		expression, err := Parse(tt.expression)
+		if err != nil {
+			t.Fatalf("%s: Parse: %v", tt.expression, err)
+		}
+		_, err = expression.Eval(resolve)
+		if err == nil {
+			t.Errorf("%s: erreur attendue", tt.expression)
+			continue
+		}
+		if errors.Is(err, ErrUndefined) != tt.undefined {
+			t.Errorf("%s: %v, ErrUndefined attendu : %v", tt.expression, err, tt.undefined)
+		}
+	}
+
+	// Une expression qui ne retourne pas un booléen est refusée par EvalBool
+	expression, err := Parse("vcpu + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expression.EvalBool(resolve); err == nil {
+		t.Errorf("vcpu + 1: EvalBool: erreur attendue")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{"", "1 +", "(1 + 2", "1 2", "unknown_function(1)", "max(1, 2", "a == == b"} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("%q: erreur attendue", expression)
+		}
+	}
+}
+
+func TestEvalFormula(t *testing.T) {
+	variables := map[string]float64{
+		"DBInstanceClassMemory": 16 * 1024 * 1024 * 1024,
+		"DBInstanceVCPU":        4,
+	}
+
+	tests := []struct {
+		formula string
+		value   float64
+	}{
+		{"{DBInstanceClassMemory/32768}", 524288},
+		{"{DBInstanceClassMemory*3/4}", 12 * 1024 * 1024 * 1024},
+		{"GREATEST({DBInstanceClassMemory/9531392},5000)", 5000},
+		{"LEAST({DBInstanceClassMemory/9531392},5000)", float64(16*1024*1024*1024) / 9531392},
+		{"SUM({DBInstanceVCPU*2},1)", 9},
+		{"{log(DBInstanceClassMemory/1073741824)*1000}", 4000},
+	}
+	for _, tt := range tests {
+		value, err := EvalFormula(tt.formula, variables)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.formula, err)
+		}
+		if value != tt.value {
+			t.Errorf("%s = %v, attendu %v", tt.formula, value, tt.value)
+		}
+	}
+
+	if _, err := EvalFormula("{Unknown/2}", variables); !errors.Is(err, ErrUndefined) {
+		t.Errorf("{Unknown/2}: %v, ErrUndefined attendu", err)
+	}
+}
@@ -0,0 +1,294 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/robinportigliatti/cloud_helper/internal/pgsettings"
+)
+
+// Sévérités d'une règle
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Statuts d'un résultat
+const (
+	StatusOK    = "ok"
+	StatusFail  = "fail"
+	StatusSkip  = "skip"
+	StatusError = "error"
+)
+
+// Parameter représente un paramètre PostgreSQL tel que configuré chez le fournisseur
+type Parameter struct {
+	Value string
+	Unit  string
+}
+
+// Instance est le modèle commun aux fournisseurs sur lequel les règles sont évaluées
+type Instance struct {
+	Provider            string
+	Identifier          string
+	EngineVersion       string
+	MachineType         string
+	VCPU                int
+	MemoryBytes         int64
+	StorageGB           int
+	HighAvailability    bool
+	BackupRetentionDays *int // nil si le fournisseur n'exprime pas la rétention en jours
+	RetainedBackups     int  // nombre de sauvegardes conservées (Cloud SQL)
+	Parameters          map[string]Parameter
+}
+
+// Unités des paramètres courants, utilisées lorsque le fournisseur ne les renseigne pas
+// (groupes de paramètres RDS, database flags Cloud SQL)
+var knownUnits = map[string]string{
+	"shared_buffers":                      "8kB",
+	"effective_cache_size":                "8kB",
+	"wal_buffers":                         "8kB",
+	"temp_buffers":                        "8kB",
+	"work_mem":                            "kB",
+	"maintenance_work_mem":                "kB",
+	"autovacuum_work_mem":                 "kB",
+	"logical_decoding_work_mem":           "kB",
+	"max_wal_size":                        "MB",
+	"min_wal_size":                        "MB",
+	"wal_keep_size":                       "MB",
+	"log_rotation_size":                   "kB",
+	"temp_file_limit":                     "kB",
+	"checkpoint_timeout":                  "s",
+	"autovacuum_naptime":                  "s",
+	"log_rotation_age":                    "min",
+	"log_min_duration_statement":          "ms",
+	"log_autovacuum_min_duration":         "ms",
+	"statement_timeout":                   "ms",
+	"lock_timeout":                        "ms",
+	"idle_in_transaction_session_timeout": "ms",
+	"deadlock_timeout":                    "ms",
+	"autovacuum_vacuum_cost_delay":        "ms",
+}
+
+// Facts retourne les faits de l'instance accessibles dans les expressions.
+// Les ressources inconnues (0) ne sont pas définies, pour que les règles qui en dépendent soient ignorées
+func (i Instance) Facts() map[string]any {
+	facts := map[string]any{
+		"provider":       i.Provider,
+		"identifier":     i.Identifier,
+		"engine_version": i.EngineVersion,
+		"major_version":  majorVersion(i.EngineVersion),
+		"machine_type":   i.MachineType,
+		"ha":             i.HighAvailability,
+	}
+	if i.BackupRetentionDays != nil {
+		facts["backup_retention"] = float64(*i.BackupRetentionDays)
+	}
+	if i.RetainedBackups > 0 {
+		facts["retained_backups"] = float64(i.RetainedBackups)
+	}
+	if i.VCPU > 0 {
+		facts["vcpu"] = float64(i.VCPU)
+	}
+	if i.MemoryBytes > 0 {
+		facts["memory"] = float64(i.MemoryBytes)
+	}
+	if i.StorageGB > 0 {
+		facts["storage"] = float64(i.StorageGB) * 1024 * 1024 * 1024
+	}
+	return facts
+}
+
+// majorVersion extrait la version majeure (ex: 16.4 -> 16, POSTGRES_15 -> 15)
+func majorVersion(version string) float64 {
+	version = strings.TrimPrefix(strings.ToUpper(version), "POSTGRES_")
+	major, _, _ := strings.Cut(version, ".")
+	number, ok := toNumber(major)
+	if !ok {
+		return 0
+	}
+	return number
+}
+
+// Resolve retourne la valeur d'un fait ou d'un paramètre. Les paramètres numériques sont
+// convertis dans l'unité de base (octets ou microsecondes), les booléens en true/false
+func (i Instance) Resolve(name string) (any, bool) {
+	if value, ok := i.Facts()[name]; ok {
+		return value, true
+	}
+
+	parameter, ok := i.Parameters[name]
+	if !ok || parameter.Value == "" {
+		return nil, false
+	}
+
+	unit := parameter.Unit
+	if unit == "" {
+		unit = knownUnits[name]
+	}
+	if number, ok := pgsettings.Normalize(parameter.Value, unit); ok {
+		return number, true
+	}
+	if b, ok := pgsettings.NormalizeBool(parameter.Value); ok {
+		return b == "on", true
+	}
+	return parameter.Value, true
+}
+
+// Rule décrit une règle de bonne pratique. When restreint les instances concernées,
+// Expr doit être vraie pour que la règle soit respectée
+type Rule struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+	When        string `yaml:"when" json:"when,omitempty"`
+	Expr        string `yaml:"expr" json:"expr"`
+	Severity    string `yaml:"severity" json:"severity"`
+	Remediation string `yaml:"remediation" json:"remediation"`
+
+	when *Expression
+	expr *Expression
+}
+
+// RuleSet est le contenu d'un fichier de règles
+type RuleSet struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Result représente le résultat d'une règle sur une instance
+type Result struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Expr        string `json:"expr"`
+	Status      string `json:"status"`
+	Severity    string `json:"severity"`
+	Remediation string `json:"remediation,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// Load charge un fichier de règles YAML ou JSON (selon l'extension) et analyse ses expressions
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ReadFile: %w", err)
+	}
+
+	var ruleSet RuleSet
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &ruleSet)
+	} else {
+		err = yaml.Unmarshal(data, &ruleSet)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: Unmarshal: %w", path, err)
+	}
+
+	for i := range ruleSet.Rules {
+		if err := ruleSet.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("%s: règle %s: %w", path, ruleSet.Rules[i].Name, err)
+		}
+	}
+	return ruleSet.Rules, nil
+}
+
+// LoadFiles charge plusieurs fichiers de règles
+func LoadFiles(paths []string) ([]Rule, error) {
+	var rules []Rule
+	for _, path := range paths {
+		fileRules, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+	return rules, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("nom manquant")
+	}
+
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityWarning
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("sévérité %s inconnue", r.Severity)
+	}
+
+	var err error
+	r.expr, err = Parse(r.Expr)
+	if err != nil {
+		return fmt.Errorf("expr: %w", err)
+	}
+	if r.When != "" {
+		r.when, err = Parse(r.When)
+		if err != nil {
+			return fmt.Errorf("when: %w", err)
+		}
+	}
+	return nil
+}
+
+// Evaluate applique les règles à une instance. Une règle dont la condition when n'est pas
+// remplie, ou qui référence un paramètre absent, est ignorée (skip)
+func Evaluate(rules []Rule, instance Instance) []Result {
+	results := make([]Result, 0, len(rules))
+	for _, rule := range rules {
+		result := Result{
+			Rule:        rule.Name,
+			Description: rule.Description,
+			Expr:        rule.Expr,
+			Severity:    rule.Severity,
+			Remediation: rule.Remediation,
+		}
+
+		if rule.expr == nil {
+			if err := rule.compile(); err != nil {
+				result.Status = StatusError
+				result.Message = err.Error()
+				results = append(results, result)
+				continue
+			}
+		}
+
+		applies := true
+		var err error
+		if rule.when != nil {
+			applies, err = rule.when.EvalBool(instance.Resolve)
+		}
+		if err == nil && !applies {
+			result.Status = StatusSkip
+			result.Message = "condition non remplie : " + rule.When
+			results = append(results, result)
+			continue
+		}
+
+		var ok bool
+		if err == nil {
+			ok, err = rule.expr.EvalBool(instance.Resolve)
+		}
+		switch {
+		case errors.Is(err, ErrUndefined):
+			result.Status = StatusSkip
+			result.Message = err.Error()
+		case err != nil:
+			result.Status = StatusError
+			result.Message = err.Error()
+		case ok:
+			result.Status = StatusOK
+		default:
+			result.Status = StatusFail
+			result.Message = "non respectée : " + rule.Expr
+		}
+		results = append(results, result)
+	}
+	return results
+}
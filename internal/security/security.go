@@ -0,0 +1,73 @@
+package security
+
+import (
+	"net"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// Sévérités d'un constat
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// Contrôles effectués
+const (
+	CheckPublicExposure      = "public_exposure"
+	CheckTLSEnforcement      = "tls_enforcement"
+	CheckStorageEncryption   = "storage_encryption"
+	CheckPasswordOnlyAuth    = "password_only_auth"
+	CheckCACertificateExpiry = "ca_certificate_expiry"
+	CheckDeletionProtection  = "deletion_protection"
+)
+
+// PostgreSQLPort est le port par défaut exposé par les services managés
+const PostgreSQLPort = 5432
+
+// Finding représente un constat de sécurité sur une ressource
+type Finding struct {
+	Provider    string `json:"provider"`
+	Resource    string `json:"resource"`
+	Check       string `json:"check"`
+	Severity    string `json:"severity"`
+	Detail      string `json:"detail"`
+	Remediation string `json:"remediation"`
+}
+
+// IsOpenToInternet indique si une plage d'adresses couvre tout Internet (0.0.0.0/0, ::/0)
+func IsOpenToInternet(cidr string) bool {
+	cidr = strings.TrimSpace(cidr)
+	if cidr == "" {
+		return false
+	}
+	if !strings.Contains(cidr, "/") {
+		return false
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := network.Mask.Size()
+	return ones == 0
+}
+
+// PrintFindings affiche les constats au format demandé (default, csv, json, markdown)
+func PrintFindings(format string, findings []Finding) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []Finding{}
+		}
+		return output.PrintJSON(findings)
+	}
+
+	columns := []string{"Provider", "Resource", "Check", "Severity", "Detail", "Remediation"}
+	rows := make([][]string, 0, len(findings))
+	for _, finding := range findings {
+		rows = append(rows, []string{finding.Provider, finding.Resource, finding.Check, finding.Severity, finding.Detail, finding.Remediation})
+	}
+	return output.PrintRows(format, columns, rows)
+}
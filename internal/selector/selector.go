@@ -0,0 +1,152 @@
+package selector
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Opérateurs d'une condition du sélecteur
+const (
+	OpEquals    = "="
+	OpNotEquals = "!="
+	OpExists    = "exists"
+	OpNotExists = "!exists"
+)
+
+// Requirement est une condition sur une étiquette : key=value, key!=value, key (présente) ou !key (absente)
+type Requirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// Selector est un ensemble de conditions qui doivent toutes être satisfaites
+type Selector []Requirement
+
+// Parse analyse un sélecteur de la forme env=prod,team=payments,!deprecated
+func Parse(expression string) (Selector, error) {
+	var selector Selector
+	for _, part := range strings.Split(expression, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var requirement Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			requirement = Requirement{Key: strings.TrimSpace(key), Operator: OpNotEquals, Value: strings.TrimSpace(value)}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			requirement = Requirement{Key: strings.TrimSpace(key), Operator: OpEquals, Value: strings.TrimSpace(value)}
+		case strings.HasPrefix(part, "!"):
+			requirement = Requirement{Key: strings.TrimSpace(part[1:]), Operator: OpNotExists}
+		default:
+			requirement = Requirement{Key: part, Operator: OpExists}
+		}
+		if requirement.Key == "" {
+			return nil, fmt.Errorf("condition sans clé : %q", part)
+		}
+		selector = append(selector, requirement)
+	}
+
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("sélecteur vide")
+	}
+	return selector, nil
+}
+
+// Matches indique si les étiquettes satisfont toutes les conditions du sélecteur
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := labels[requirement.Key]
+		switch requirement.Operator {
+		case OpEquals:
+			if !ok || value != requirement.Value {
+				return false
+			}
+		case OpNotEquals:
+			if ok && value == requirement.Value {
+				return false
+			}
+		case OpExists:
+			if !ok {
+				return false
+			}
+		case OpNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String restitue le sélecteur sous sa forme textuelle
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, requirement := range s {
+		switch requirement.Operator {
+		case OpExists:
+			parts = append(parts, requirement.Key)
+		case OpNotExists:
+			parts = append(parts, "!"+requirement.Key)
+		default:
+			parts = append(parts, requirement.Key+requirement.Operator+requirement.Value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Result est le résultat de la commande pour une instance sélectionnée
+type Result struct {
+	Name string
+	Err  error
+}
+
+// ForEach exécute la commande pour chaque instance sélectionnée, dans l'ordre alphabétique, sans
+// s'arrêter à la première erreur. Un résumé par instance est affiché sur la sortie d'erreur à la fin,
+// pour ne pas se mêler à la sortie de la commande ; une erreur est retournée si une instance a échoué
+func ForEach(names []string, run func(name string) error) error {
+	if len(names) == 0 {
+		return fmt.Errorf("aucune instance ne correspond au sélecteur")
+	}
+	sort.Strings(names)
+
+	results := make([]Result, 0, len(names))
+	failed := 0
+	for _, name := range names {
+		slog.Info("Instance sélectionnée", slog.String("instance", name))
+		err := run(name)
+		if err != nil {
+			failed++
+		}
+		results = append(results, Result{Name: name, Err: err})
+	}
+
+	PrintSummary(os.Stderr, results)
+	if failed > 0 {
+		return fmt.Errorf("%d instance(s) en erreur sur %d", failed, len(results))
+	}
+	return nil
+}
+
+// PrintSummary affiche le statut de chaque instance
+func PrintSummary(w io.Writer, results []Result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Instance\tStatus\tError")
+	for _, result := range results {
+		status, message := "ok", ""
+		if result.Err != nil {
+			status, message = "error", result.Err.Error()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Name, status, message)
+	}
+	_ = tw.Flush()
+}
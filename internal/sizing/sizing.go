@@ -0,0 +1,295 @@
+package sizing
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/forecast"
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// Actions recommandées
+const (
+	ActionUpsize   = "upsize"
+	ActionDownsize = "downsize"
+	ActionKeep     = "keep"
+)
+
+// Seuils d'utilisation (en pourcentage) déclenchant une recommandation
+const (
+	// Au-delà, la classe est sous-dimensionnée
+	CPUHighP95    = 75.0
+	CPUHighMax    = 95.0
+	MemoryHighP95 = 90.0
+
+	// En deçà, la classe est surdimensionnée
+	CPULowP95    = 30.0
+	CPULowMax    = 60.0
+	MemoryLowMax = 50.0
+
+	// Au-delà, les IOPS (lectures + écritures) approchent la limite de la classe ou du stockage
+	IopsHighP95 = 80.0
+
+	// Utilisation maximale tolérée sur la classe inférieure après réduction
+	DownsizeTarget = 70.0
+)
+
+// Stats résume une métrique sur la fenêtre d'analyse
+type Stats struct {
+	P95 float64 `json:"p95"`
+	Max float64 `json:"max"`
+}
+
+// Class décrit une classe d'instance (RDS) ou un type de machine (Cloud SQL).
+// Iops est le débit soutenu de la classe vers le stockage (EBS baseline), 0 s'il est inconnu
+type Class struct {
+	Name        string `json:"name"`
+	VCPU        int    `json:"vcpu"`
+	MemoryBytes int64  `json:"memory_bytes"`
+	Iops        int    `json:"iops,omitempty"`
+}
+
+// Usage regroupe l'utilisation observée de l'instance. L'utilisation mémoire est en octets.
+// StorageIops est la limite d'IOPS du stockage (provisionnées ou de base), 0 si elle est inconnue
+type Usage struct {
+	CPUPercent  Stats `json:"cpu_percent"`
+	MemoryBytes Stats `json:"memory_bytes"`
+	Connections Stats `json:"connections"`
+	ReadIops    Stats `json:"read_iops"`
+	WriteIops   Stats `json:"write_iops"`
+	StorageIops int   `json:"storage_iops,omitempty"`
+}
+
+// ParameterChange décrit un paramètre dont la valeur dépend de la mémoire ou des vCPU de la classe
+type ParameterChange struct {
+	Name      string `json:"name"`
+	Formula   string `json:"formula"`
+	Current   string `json:"current"`
+	Suggested string `json:"suggested"`
+}
+
+// Recommendation est le résultat de l'analyse de dimensionnement d'une instance
+type Recommendation struct {
+	Provider   string            `json:"provider"`
+	Resource   string            `json:"resource"`
+	WindowDays int               `json:"window_days"`
+	Current    Class             `json:"current"`
+	Action     string            `json:"action"`
+	Suggested  *Class            `json:"suggested,omitempty"`
+	Usage      Usage             `json:"usage"`
+	Reasons    []string          `json:"reasons"`
+	Parameters []ParameterChange `json:"parameters,omitempty"`
+}
+
+// Percentile retourne le percentile p (0-100) des valeurs, par interpolation linéaire
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Summarize calcule le p95 des moyennes par période et le maximum des maxima
+func Summarize(average []forecast.Sample, maximum []forecast.Sample) Stats {
+	values := make([]float64, 0, len(average))
+	for _, sample := range average {
+		values = append(values, sample.Value)
+	}
+	return Stats{
+		P95: math.Round(Percentile(values, 95)*100) / 100,
+		Max: math.Round(forecast.Peak(maximum)*100) / 100,
+	}
+}
+
+// MemoryPercent convertit l'utilisation mémoire en pourcentage de la mémoire de la classe
+func (u Usage) MemoryPercent(class Class) Stats {
+	if class.MemoryBytes == 0 {
+		return Stats{}
+	}
+	return Stats{
+		P95: math.Round(u.MemoryBytes.P95/float64(class.MemoryBytes)*10000) / 100,
+		Max: math.Round(u.MemoryBytes.Max/float64(class.MemoryBytes)*10000) / 100,
+	}
+}
+
+// Iops additionne les lectures et les écritures. La somme des p95 et des maxima majore
+// ceux de la série totale
+func (u Usage) Iops() Stats {
+	return Stats{
+		P95: u.ReadIops.P95 + u.WriteIops.P95,
+		Max: u.ReadIops.Max + u.WriteIops.Max,
+	}
+}
+
+// iopsPercent convertit des IOPS en pourcentage d'une limite, 0 si la limite est inconnue
+func iopsPercent(iops float64, limit int) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return iops / float64(limit) * 100
+}
+
+// Family retourne le préfixe de famille d'un nom de classe (db.r6g.large -> db.r6g)
+func Family(name string) string {
+	index := strings.LastIndex(name, ".")
+	if index == -1 {
+		return name
+	}
+	return name[:index]
+}
+
+// SortClasses trie les classes par vCPU puis par mémoire croissantes
+func SortClasses(classes []Class) {
+	slices.SortFunc(classes, func(a, b Class) int {
+		if a.VCPU != b.VCPU {
+			return a.VCPU - b.VCPU
+		}
+		switch {
+		case a.MemoryBytes < b.MemoryBytes:
+			return -1
+		case a.MemoryBytes > b.MemoryBytes:
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Recommend compare l'utilisation aux seuils et propose la classe voisine de la même famille.
+// candidates doit être trié (SortClasses) et contenir la classe actuelle. Des IOPS proches de la limite
+// du stockage sont signalées sans changer l'action : c'est le stockage qu'il faut modifier
+func Recommend(usage Usage, current Class, candidates []Class) (string, *Class, []string) {
+	action, suggested, reasons := recommendClass(usage, current, candidates)
+
+	iops := usage.Iops()
+	if percent := iopsPercent(iops.P95, usage.StorageIops); percent > IopsHighP95 {
+		reasons = append(reasons, fmt.Sprintf("IOPS p95 %.0f à %.0f%% de la limite du stockage (%d) : augmenter les IOPS provisionnées ou la taille du stockage",
+			iops.P95, percent, usage.StorageIops))
+	}
+	return action, suggested, reasons
+}
+
+// recommendClass choisit l'action et la classe à partir du CPU, de la mémoire et des IOPS de la classe
+func recommendClass(usage Usage, current Class, candidates []Class) (string, *Class, []string) {
+	var reasons []string
+	memory := usage.MemoryPercent(current)
+	iops := usage.Iops()
+
+	if usage.CPUPercent.P95 > CPUHighP95 {
+		reasons = append(reasons, fmt.Sprintf("CPU p95 %.0f%% > %.0f%%", usage.CPUPercent.P95, CPUHighP95))
+	}
+	if usage.CPUPercent.Max > CPUHighMax {
+		reasons = append(reasons, fmt.Sprintf("CPU max %.0f%% > %.0f%%", usage.CPUPercent.Max, CPUHighMax))
+	}
+	if memory.P95 > MemoryHighP95 {
+		reasons = append(reasons, fmt.Sprintf("mémoire utilisée p95 %.0f%% > %.0f%%", memory.P95, MemoryHighP95))
+	}
+	if percent := iopsPercent(iops.P95, current.Iops); percent > IopsHighP95 {
+		reasons = append(reasons, fmt.Sprintf("IOPS p95 %.0f à %.0f%% du débit de la classe (%d) > %.0f%%", iops.P95, percent, current.Iops, IopsHighP95))
+	}
+
+	index := slices.IndexFunc(candidates, func(c Class) bool { return c.Name == current.Name })
+
+	if len(reasons) > 0 {
+		if index == -1 || index == len(candidates)-1 {
+			reasons = append(reasons, "aucune classe supérieure dans la même famille")
+			return ActionKeep, nil, reasons
+		}
+		larger := candidates[index+1]
+		return ActionUpsize, &larger, reasons
+	}
+
+	if usage.CPUPercent.P95 >= CPULowP95 || usage.CPUPercent.Max >= CPULowMax || memory.Max >= MemoryLowMax {
+		reasons = append(reasons, fmt.Sprintf("utilisation dans les seuils (CPU p95 %.0f%%, max %.0f%%, mémoire max %.0f%%)",
+			usage.CPUPercent.P95, usage.CPUPercent.Max, memory.Max))
+		return ActionKeep, nil, reasons
+	}
+
+	reasons = append(reasons, fmt.Sprintf("CPU p95 %.0f%% < %.0f%%, max %.0f%% < %.0f%%, mémoire max %.0f%% < %.0f%%",
+		usage.CPUPercent.P95, CPULowP95, usage.CPUPercent.Max, CPULowMax, memory.Max, MemoryLowMax))
+	if index <= 0 {
+		reasons = append(reasons, "aucune classe inférieure dans la même famille")
+		return ActionKeep, nil, reasons
+	}
+
+	// La classe inférieure doit absorber la charge observée
+	smaller := candidates[index-1]
+	projectedCPU := usage.CPUPercent.Max * float64(current.VCPU) / float64(max(smaller.VCPU, 1))
+	projectedMemory := usage.MemoryPercent(smaller).Max
+	projectedIops := iopsPercent(iops.Max, smaller.Iops)
+	if projectedCPU > DownsizeTarget || projectedMemory > DownsizeTarget || projectedIops > DownsizeTarget {
+		reasons = append(reasons, fmt.Sprintf("%s serait trop juste (CPU max %.0f%%, mémoire max %.0f%%, IOPS max %.0f%%)", smaller.Name, projectedCPU, projectedMemory, projectedIops))
+		return ActionKeep, nil, reasons
+	}
+	return ActionDownsize, &smaller, reasons
+}
+
+// Print affiche la recommandation au format demandé (default, csv, json, markdown)
+func Print(format string, recommendation Recommendation) error {
+	if format == "json" {
+		return output.PrintJSON(recommendation)
+	}
+
+	memory := recommendation.Usage.MemoryPercent(recommendation.Current)
+	usageRows := [][]string{
+		{"CPU (%)", formatFloat(recommendation.Usage.CPUPercent.P95), formatFloat(recommendation.Usage.CPUPercent.Max)},
+		{"Memory used (%)", formatFloat(memory.P95), formatFloat(memory.Max)},
+		{"Connections", formatFloat(recommendation.Usage.Connections.P95), formatFloat(recommendation.Usage.Connections.Max)},
+		{"Read IOPS", formatFloat(recommendation.Usage.ReadIops.P95), formatFloat(recommendation.Usage.ReadIops.Max)},
+		{"Write IOPS", formatFloat(recommendation.Usage.WriteIops.P95), formatFloat(recommendation.Usage.WriteIops.Max)},
+	}
+	if recommendation.Current.Iops > 0 {
+		iops := recommendation.Usage.Iops()
+		usageRows = append(usageRows, []string{"IOPS / class limit (%)", formatFloat(iopsPercent(iops.P95, recommendation.Current.Iops)), formatFloat(iopsPercent(iops.Max, recommendation.Current.Iops))})
+	}
+	if recommendation.Usage.StorageIops > 0 {
+		iops := recommendation.Usage.Iops()
+		usageRows = append(usageRows, []string{"IOPS / storage limit (%)", formatFloat(iopsPercent(iops.P95, recommendation.Usage.StorageIops)), formatFloat(iopsPercent(iops.Max, recommendation.Usage.StorageIops))})
+	}
+	if err := output.PrintRows(format, []string{"Metric", "P95", "Max"}, usageRows); err != nil {
+		return err
+	}
+	fmt.Println()
+
+	suggested := "-"
+	if recommendation.Suggested != nil {
+		suggested = formatClass(*recommendation.Suggested)
+	}
+	rows := [][]string{
+		{"Resource", recommendation.Resource},
+		{"Window", fmt.Sprintf("%d days", recommendation.WindowDays)},
+		{"Current", formatClass(recommendation.Current)},
+		{"Action", recommendation.Action},
+		{"Suggested", suggested},
+		{"Reasons", strings.Join(recommendation.Reasons, "; ")},
+	}
+	if err := output.PrintRows(format, []string{"Field", "Value"}, rows); err != nil {
+		return err
+	}
+
+	if len(recommendation.Parameters) == 0 {
+		return nil
+	}
+	fmt.Println()
+	parameterRows := make([][]string, 0, len(recommendation.Parameters))
+	for _, parameter := range recommendation.Parameters {
+		parameterRows = append(parameterRows, []string{parameter.Name, parameter.Formula, parameter.Current, parameter.Suggested})
+	}
+	return output.PrintRows(format, []string{"Parameter", "Formula", "Current", "Suggested"}, parameterRows)
+}
+
+func formatClass(class Class) string {
+	return fmt.Sprintf("%s (%d vCPU, %.1f GiB)", class.Name, class.VCPU, float64(class.MemoryBytes)/forecast.GiB)
+}
+
+func formatFloat(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
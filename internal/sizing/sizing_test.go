@@ -0,0 +1,59 @@
+package sizing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecommendIops(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	candidates := []Class{
+		{Name: "db.r6g.large", VCPU: 2, MemoryBytes: 16 * gib, Iops: 3600},
+		{Name: "db.r6g.xlarge", VCPU: 4, MemoryBytes: 32 * gib, Iops: 6000},
+		{Name: "db.r6g.2xlarge", VCPU: 8, MemoryBytes: 64 * gib, Iops: 12000},
+	}
+	current := candidates[1]
+	// CPU et mémoire dans les seuils
+	usage := Usage{
+		CPUPercent:  Stats{P95: 40, Max: 70},
+		MemoryBytes: Stats{P95: 20 * gib, Max: 22 * gib},
+	}
+
+	tests := []struct {
+		name        string
+		read        Stats
+		write       Stats
+		storageIops int
+		action      string
+		reason      string
+	}{
+		{"dans les seuils", Stats{P95: 1000, Max: 1500}, Stats{P95: 500, Max: 800}, 0, ActionKeep, "utilisation dans les seuils"},
+		{"limite de la classe", Stats{P95: 3000, Max: 4000}, Stats{P95: 2000, Max: 2500}, 0, ActionUpsize, "débit de la classe"},
+		{"limite du stockage", Stats{P95: 1500, Max: 2000}, Stats{P95: 1200, Max: 1500}, 3000, ActionKeep, "limite du stockage"},
+	}
+	for _, tt := range tests {
+		usage.ReadIops, usage.WriteIops, usage.StorageIops = tt.read, tt.write, tt.storageIops
+		action, suggested, reasons := Recommend(usage, current, candidates)
+		if action != tt.action {
+			t.Errorf("%s: action %s, attendu %s (%v)", tt.name, action, tt.action, reasons)
+		}
+		if action == ActionUpsize && (suggested == nil || suggested.Name != "db.r6g.2xlarge") {
+			t.Errorf("%s: classe proposée %v, attendu db.r6g.2xlarge", tt.name, suggested)
+		}
+		if !strings.Contains(strings.Join(reasons, "; "), tt.reason) {
+			t.Errorf("%s: raisons %v, attendu %q", tt.name, reasons, tt.reason)
+		}
+	}
+
+	// Une classe inférieure qui ne tiendrait pas les IOPS observées n'est pas proposée
+	idle := Usage{
+		CPUPercent:  Stats{P95: 5, Max: 10},
+		MemoryBytes: Stats{P95: 4 * gib, Max: 5 * gib},
+		ReadIops:    Stats{P95: 1500, Max: 2500},
+		WriteIops:   Stats{P95: 500, Max: 1000},
+	}
+	action, _, reasons := Recommend(idle, current, candidates)
+	if action != ActionKeep || !strings.Contains(strings.Join(reasons, "; "), "trop juste") {
+		t.Errorf("réduction: action %s (%v), attendu keep", action, reasons)
+	}
+}
@@ -0,0 +1,281 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+)
+
+// Rôles d'un nœud de la topologie
+const (
+	RolePrimary = "primary"
+	RoleReplica = "replica"
+	RoleCluster = "cluster"
+	RoleWriter  = "writer"
+	RoleReader  = "reader"
+)
+
+// Node est une instance (ou un cluster Aurora) de la topologie de réplication.
+// Parent est l'identifiant du nœud source ; les retards sont les maxima de la dernière heure
+type Node struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Role              string   `json:"role"`
+	Region            string   `json:"region"`
+	Status            string   `json:"status,omitempty"`
+	Parent            string   `json:"parent,omitempty"`
+	CrossRegion       bool     `json:"cross_region"`
+	ReplicaLagSeconds *float64 `json:"replica_lag_seconds,omitempty"`
+	SlotLagBytes      *float64 `json:"slot_lag_bytes,omitempty"`
+}
+
+// Topology regroupe les nœuds d'un fournisseur
+type Topology struct {
+	Provider string `json:"provider"`
+	Nodes    []Node `json:"nodes"`
+}
+
+// New construit la topologie : les nœuds sont triés par nom et les réplications
+// vers une autre région que la source sont marquées
+func New(provider string, nodes []Node) Topology {
+	regions := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		regions[node.ID] = node.Region
+	}
+	for i := range nodes {
+		parentRegion, ok := regions[nodes[i].Parent]
+		nodes[i].CrossRegion = ok && parentRegion != "" && nodes[i].Region != "" && parentRegion != nodes[i].Region
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	return Topology{Provider: provider, Nodes: nodes}
+}
+
+// Family retourne la partie de la topologie contenant le nœud désigné par son nom ou son identifiant :
+// sa racine et tous ses descendants. La topologie retournée est vide si le nœud est inconnu
+func (t Topology) Family(name string) Topology {
+	byID := make(map[string]Node, len(t.Nodes))
+	root := ""
+	for _, node := range t.Nodes {
+		byID[node.ID] = node
+		if root == "" && (node.Name == name || node.ID == name) {
+			root = node.ID
+		}
+	}
+	if root == "" {
+		return Topology{Provider: t.Provider}
+	}
+
+	for {
+		node, ok := byID[root]
+		if !ok || node.Parent == "" {
+			break
+		}
+		if _, ok := byID[node.Parent]; !ok {
+			break
+		}
+		root = node.Parent
+	}
+
+	family := Topology{Provider: t.Provider}
+	for _, node := range t.Nodes {
+		current := node.ID
+		for current != "" && current != root {
+			current = byID[current].Parent
+		}
+		if current == root {
+			family.Nodes = append(family.Nodes, node)
+		}
+	}
+	return family
+}
+
+// roots retourne les nœuds sans source connue, et children les nœuds par source
+func (t Topology) roots() ([]Node, map[string][]Node) {
+	known := make(map[string]bool, len(t.Nodes))
+	for _, node := range t.Nodes {
+		known[node.ID] = true
+	}
+
+	var roots []Node
+	children := make(map[string][]Node)
+	for _, node := range t.Nodes {
+		if node.Parent == "" || !known[node.Parent] {
+			roots = append(roots, node)
+			continue
+		}
+		children[node.Parent] = append(children[node.Parent], node)
+	}
+	return roots, children
+}
+
+// Print affiche la topologie au format demandé : arbre (default), dot (Graphviz), mermaid, json, csv ou markdown
+func Print(format string, t Topology) error {
+	switch format {
+	case "json":
+		return output.PrintJSON(t)
+	case "dot":
+		fmt.Print(Dot(t))
+		return nil
+	case "mermaid":
+		fmt.Print(Mermaid(t))
+		return nil
+	case "csv", "markdown":
+		columns := []string{"Name", "Role", "Region", "Status", "Source", "Cross-region", "Replica lag", "Slot lag"}
+		names := make(map[string]string, len(t.Nodes))
+		for _, node := range t.Nodes {
+			names[node.ID] = node.Name
+		}
+		rows := make([][]string, 0, len(t.Nodes))
+		for _, node := range t.Nodes {
+			source := names[node.Parent]
+			if source == "" {
+				source = node.Parent
+			}
+			rows = append(rows, []string{node.Name, node.Role, node.Region, node.Status, source,
+				fmt.Sprintf("%t", node.CrossRegion), formatLag(node.ReplicaLagSeconds), formatBytes(node.SlotLagBytes)})
+		}
+		return output.PrintRows(format, columns, rows)
+	default:
+		fmt.Print(Tree(t))
+		return nil
+	}
+}
+
+// Tree met en forme la topologie en arbre, une racine par primaire
+func Tree(t Topology) string {
+	roots, children := t.roots()
+
+	var b strings.Builder
+	var walk func(node Node, prefix string, last bool, depth int)
+	walk = func(node Node, prefix string, last bool, depth int) {
+		branch, indent := "", ""
+		if depth > 0 {
+			branch, indent = "├── ", "│   "
+			if last {
+				branch, indent = "└── ", "    "
+			}
+		}
+		fmt.Fprintf(&b, "%s%s%s\n", prefix, branch, label(node))
+		for i, child := range children[node.ID] {
+			walk(child, prefix+indent, i == len(children[node.ID])-1, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", true, 0)
+	}
+	return b.String()
+}
+
+// Dot met en forme la topologie pour Graphviz ; les réplications inter-régions sont en pointillés
+func Dot(t Topology) string {
+	ids := nodeIDs(t)
+	var b strings.Builder
+	b.WriteString("digraph topology {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range t.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%q];\n", ids[node.ID], strings.Join(details(node), "\n"))
+	}
+	for _, node := range t.Nodes {
+		parent, ok := ids[node.Parent]
+		if !ok {
+			continue
+		}
+		style := ""
+		if node.CrossRegion {
+			style = " [style=dashed, label=\"cross-region\"]"
+		}
+		fmt.Fprintf(&b, "  %s -> %s%s;\n", parent, ids[node.ID], style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid met en forme la topologie en diagramme Mermaid ; les réplications inter-régions sont en pointillés
+func Mermaid(t Topology) string {
+	ids := nodeIDs(t)
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, node := range t.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[node.ID], strings.ReplaceAll(strings.Join(details(node), "<br/>"), "\"", "#quot;"))
+	}
+	for _, node := range t.Nodes {
+		parent, ok := ids[node.Parent]
+		if !ok {
+			continue
+		}
+		if node.CrossRegion {
+			fmt.Fprintf(&b, "  %s -. cross-region .-> %s\n", parent, ids[node.ID])
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", parent, ids[node.ID])
+		}
+	}
+	return b.String()
+}
+
+// nodeIDs attribue à chaque nœud un identifiant utilisable par Graphviz et Mermaid
+func nodeIDs(t Topology) map[string]string {
+	ids := make(map[string]string, len(t.Nodes))
+	for i, node := range t.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// label décrit un nœud sur une ligne de l'arbre
+func label(node Node) string {
+	lines := details(node)
+	return fmt.Sprintf("%s [%s]", lines[0], strings.Join(lines[1:], ", "))
+}
+
+// details retourne le nom du nœud suivi de son rôle, sa région, son état et ses retards
+func details(node Node) []string {
+	lines := []string{node.Name, node.Role}
+	if node.Region != "" {
+		lines = append(lines, node.Region)
+	}
+	if node.CrossRegion {
+		lines = append(lines, "cross-region")
+	}
+	if node.Status != "" {
+		lines = append(lines, node.Status)
+	}
+	if node.ReplicaLagSeconds != nil {
+		lines = append(lines, "replica lag "+formatLag(node.ReplicaLagSeconds))
+	}
+	if node.SlotLagBytes != nil {
+		lines = append(lines, "slot lag "+formatBytes(node.SlotLagBytes))
+	}
+	return lines
+}
+
+func formatLag(seconds *float64) string {
+	if seconds == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.1fs", *seconds)
+}
+
+// formatBytes convertit un nombre d'octets en format lisible
+func formatBytes(bytes *float64) string {
+	if bytes == nil {
+		return ""
+	}
+	const unit = 1024
+	b := int64(*bytes)
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(b)/float64(div), "kMGTPE"[exp])
+}
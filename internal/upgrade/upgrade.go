@@ -0,0 +1,324 @@
+package upgrade
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/robinportigliatti/cloud_helper/internal/output"
+	"github.com/robinportigliatti/cloud_helper/internal/postgres"
+)
+
+//go:embed calendar.yaml
+var calendarData []byte
+
+// SoonDays est le délai (en jours) en deçà duquel une fin de support est signalée
+const SoonDays = 180
+
+// Statuts de support d'une version
+const (
+	StatusSupported = "supported"
+	StatusSoon      = "end-of-support-soon"
+	StatusEOL       = "end-of-support"
+	StatusUnknown   = "unknown"
+)
+
+// Calendar est le calendrier de fin de support embarqué
+type Calendar struct {
+	Community         map[string]time.Time            `yaml:"community"`
+	Providers         map[string]map[string]time.Time `yaml:"providers"`
+	RemovedExtensions map[string][]string             `yaml:"removed_extensions"`
+}
+
+// Target est une version cible d'une montée de version
+type Target struct {
+	Version     string `json:"version"`
+	Major       bool   `json:"major"`
+	Description string `json:"description,omitempty"`
+}
+
+// ExtensionIssue décrit une extension installée à traiter avant la montée de version
+type ExtensionIssue struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	DefaultVersion string `json:"default_version,omitempty"`
+	Issue          string `json:"issue"`
+}
+
+// Plan est le plan de montée de version d'une instance
+type Plan struct {
+	Provider           string           `json:"provider"`
+	Resource           string           `json:"resource"`
+	Version            string           `json:"version"`
+	EndOfSupport       *time.Time       `json:"end_of_support,omitempty"`
+	DaysLeft           *int             `json:"days_left,omitempty"`
+	Status             string           `json:"status"`
+	Targets            []Target         `json:"targets"`
+	Path               []string         `json:"path"`
+	TargetEndOfSupport *time.Time       `json:"target_end_of_support,omitempty"`
+	Extensions         []ExtensionIssue `json:"extensions,omitempty"`
+	Notes              []string         `json:"notes,omitempty"`
+}
+
+// LoadCalendar charge le calendrier embarqué
+func LoadCalendar() (Calendar, error) {
+	var calendar Calendar
+	if err := yaml.Unmarshal(calendarData, &calendar); err != nil {
+		return calendar, fmt.Errorf("YAML Unmarshal: %w", err)
+	}
+	return calendar, nil
+}
+
+// EndOfSupport retourne la fin de support d'une version majeure chez le fournisseur,
+// ou la date communautaire à défaut
+func (c Calendar) EndOfSupport(provider string, major string) (time.Time, bool) {
+	if date, ok := c.Providers[provider][major]; ok {
+		return date, true
+	}
+	date, ok := c.Community[major]
+	return date, ok
+}
+
+// RemovedBetween retourne les extensions retirées entre deux versions majeures (exclue, incluse)
+func (c Calendar) RemovedBetween(fromMajor string, toMajor string) map[string]string {
+	removed := make(map[string]string)
+	for major, extensions := range c.RemovedExtensions {
+		if Compare(major, fromMajor) <= 0 || Compare(major, toMajor) > 0 {
+			continue
+		}
+		for _, extension := range extensions {
+			removed[extension] = major
+		}
+	}
+	return removed
+}
+
+// MajorVersion retourne la version majeure (13.12 -> 13, 9.6.24 -> 9.6, POSTGRES_15 -> 15)
+func MajorVersion(version string) string {
+	parts := strings.Split(normalize(version), ".")
+	if len(parts) >= 2 && parts[0] == "9" {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
+
+// Compare compare deux versions numériquement (13.9 < 13.12)
+func Compare(a string, b string) int {
+	partsA := strings.Split(normalize(a), ".")
+	partsB := strings.Split(normalize(b), ".")
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var numberA, numberB int
+		if i < len(partsA) {
+			numberA, _ = strconv.Atoi(strings.TrimFunc(partsA[i], isNotDigit))
+		}
+		if i < len(partsB) {
+			numberB, _ = strconv.Atoi(strings.TrimFunc(partsB[i], isNotDigit))
+		}
+		if numberA != numberB {
+			return numberA - numberB
+		}
+	}
+	return 0
+}
+
+// normalize convertit les noms de version Cloud SQL (POSTGRES_9_6) en numéro de version
+func normalize(version string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(strings.ToUpper(version), "POSTGRES_"), "_", ".")
+}
+
+func isNotDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
+
+// SortTargets trie les versions cibles par ordre croissant
+func SortTargets(targets []Target) {
+	slices.SortFunc(targets, func(a, b Target) int { return Compare(a.Version, b.Version) })
+}
+
+// NewPlan construit le plan d'une instance : statut de support de sa version, versions cibles
+// et chemin de montée de version (path, de la version actuelle à la cible finale)
+func NewPlan(calendar Calendar, provider string, resource string, version string, targets []Target, path []string) Plan {
+	SortTargets(targets)
+	plan := Plan{
+		Provider: provider,
+		Resource: resource,
+		Version:  version,
+		Status:   StatusUnknown,
+		Targets:  targets,
+		Path:     append([]string{version}, path...),
+	}
+	if plan.Targets == nil {
+		plan.Targets = []Target{}
+	}
+
+	if date, ok := calendar.EndOfSupport(provider, MajorVersion(version)); ok {
+		days := int(math.Floor(time.Until(date).Hours() / 24))
+		plan.EndOfSupport = &date
+		plan.DaysLeft = &days
+		switch {
+		case days < 0:
+			plan.Status = StatusEOL
+		case days <= SoonDays:
+			plan.Status = StatusSoon
+		default:
+			plan.Status = StatusSupported
+		}
+	}
+
+	if len(path) > 0 {
+		if date, ok := calendar.EndOfSupport(provider, MajorVersion(path[len(path)-1])); ok {
+			plan.TargetEndOfSupport = &date
+		}
+	}
+	if len(targets) == 0 {
+		plan.Notes = append(plan.Notes, "aucune version cible proposée par le fournisseur")
+	}
+	return plan
+}
+
+// extensionsNote rappelle que les versions par défaut viennent du serveur actuel
+const extensionsNote = "extensions : la version par défaut est celle de pg_available_extensions sur le serveur actuel, " +
+	"pas celle de la version cible"
+
+// unsupportedNote est ajoutée lorsque le fournisseur ne publie pas la liste des extensions de la version cible
+const unsupportedNote = "extensions : la liste des extensions proposées par le fournisseur n'est pas vérifiée, " +
+	"la consulter pour la version cible"
+
+// SupportedExtensions est la liste des extensions proposées par le fournisseur (rds.extensions,
+// azure.extensions) et l'origine de cette liste, reprise dans les messages
+type SupportedExtensions struct {
+	Names  []string
+	Source string
+}
+
+// ParseSupportedExtensions construit la liste à partir des valeurs autorisées d'un paramètre (a,b,c)
+func ParseSupportedExtensions(allowedValues string, source string) *SupportedExtensions {
+	supported := &SupportedExtensions{Source: source}
+	for _, name := range strings.Split(allowedValues, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			supported.Names = append(supported.Names, strings.ToLower(name))
+		}
+	}
+	return supported
+}
+
+// CheckExtensions vérifie les extensions installées par rapport à la version finale du chemin de montée de version.
+// Sans liste des extensions du fournisseur (supported nil), seules les extensions retirées du calendrier sont signalées
+func (p *Plan) CheckExtensions(ctx context.Context, calendar Calendar, connection postgres.Connection, supported *SupportedExtensions) error {
+	issues, err := CheckExtensions(ctx, calendar, connection, p.Version, p.Path[len(p.Path)-1], supported)
+	if err != nil {
+		return err
+	}
+	p.Extensions = issues
+	p.Notes = append(p.Notes, extensionsNote)
+	if supported == nil {
+		p.Notes = append(p.Notes, unsupportedNote)
+	}
+	return nil
+}
+
+// CheckExtensions compare les extensions installées dans la base à la version cible :
+// extensions retirées entre les deux versions majeures, extensions absentes de la liste du fournisseur,
+// et extensions dont la version installée n'est pas la version par défaut (ALTER EXTENSION ... UPDATE
+// à faire avant la montée de version). La version par défaut est lue sur le serveur actuel :
+// une extension mise à jour dans la version cible n'est pas détectée
+func CheckExtensions(ctx context.Context, calendar Calendar, connection postgres.Connection, version string, target string, supported *SupportedExtensions) ([]ExtensionIssue, error) {
+	conn, err := postgres.Connect(ctx, connection)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.Connect: %w", err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	_, rows, err := postgres.Query(ctx, conn, `SELECT e.extname, e.extversion, coalesce(a.default_version, '')
+FROM pg_extension e LEFT JOIN pg_available_extensions a ON a.name = e.extname
+WHERE e.extname <> 'plpgsql' ORDER BY e.extname`)
+	if err != nil {
+		return nil, fmt.Errorf("postgres.Query: %w", err)
+	}
+
+	removed := calendar.RemovedBetween(MajorVersion(version), MajorVersion(target))
+	issues := []ExtensionIssue{}
+	for _, row := range rows {
+		issue := ExtensionIssue{Name: row[0], Version: row[1], DefaultVersion: row[2]}
+		switch {
+		case removed[issue.Name] != "":
+			issue.Issue = fmt.Sprintf("retirée en PostgreSQL %s, à supprimer avant la montée de version", removed[issue.Name])
+		case supported != nil && !slices.Contains(supported.Names, strings.ToLower(issue.Name)):
+			issue.Issue = fmt.Sprintf("absente de %s, à supprimer ou remplacer avant la montée de version", supported.Source)
+		case issue.DefaultVersion != "" && issue.DefaultVersion != issue.Version:
+			issue.Issue = fmt.Sprintf("ALTER EXTENSION %s UPDATE; (version par défaut %s)", issue.Name, issue.DefaultVersion)
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// Print affiche les plans au format demandé : json, ou un plan par instance en Markdown
+// (default, markdown) à reprendre tel quel dans une demande de changement
+func Print(format string, plans []Plan) error {
+	if format == "json" {
+		if plans == nil {
+			plans = []Plan{}
+		}
+		return output.PrintJSON(plans)
+	}
+
+	for i, plan := range plans {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("## %s (%s)\n\n", plan.Resource, plan.Provider)
+		fmt.Printf("- Current version: %s\n", plan.Version)
+		if plan.EndOfSupport != nil {
+			fmt.Printf("- End of support: %s (%d days, %s)\n", plan.EndOfSupport.Format(time.DateOnly), *plan.DaysLeft, plan.Status)
+		} else {
+			fmt.Printf("- End of support: %s\n", plan.Status)
+		}
+
+		var minors, majors []string
+		for _, target := range plan.Targets {
+			if target.Major {
+				majors = append(majors, target.Version)
+			} else {
+				minors = append(minors, target.Version)
+			}
+		}
+		fmt.Printf("- Minor targets: %s\n", joinOrNone(minors))
+		fmt.Printf("- Major targets: %s\n", joinOrNone(majors))
+
+		if len(plan.Path) > 1 {
+			fmt.Printf("- Upgrade path: %s", strings.Join(plan.Path, " -> "))
+			if plan.TargetEndOfSupport != nil {
+				fmt.Printf(" (supported until %s)", plan.TargetEndOfSupport.Format(time.DateOnly))
+			}
+			fmt.Println()
+		}
+
+		if len(plan.Extensions) > 0 {
+			fmt.Println("- Extensions:")
+			for _, extension := range plan.Extensions {
+				fmt.Printf("  - %s %s: %s\n", extension.Name, extension.Version, extension.Issue)
+			}
+		}
+		for _, note := range plan.Notes {
+			fmt.Printf("- Note: %s\n", note)
+		}
+	}
+	return nil
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}